@@ -0,0 +1,143 @@
+package otelx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	logx "github.com/bionicotaku/lingo-utils-logx"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// SamplingRatioMetricName is the gauge reporting the head sampling ratio chosen by
+// Config.SamplingTargetPerMinute.
+const SamplingRatioMetricName = "otelx.sampler.ratio"
+
+const (
+	// adaptiveInterval is how often the adaptive controller re-evaluates the ratio.
+	adaptiveInterval = 10 * time.Second
+	// minAdaptiveRatio keeps a trickle of traces even under extreme load.
+	minAdaptiveRatio = 1.0 / 10000
+	// maxAdaptiveStep bounds how much the ratio may change per interval, so a burst does not
+	// swing it from one extreme to the other.
+	maxAdaptiveStep = 4
+)
+
+// errAdaptiveSampling rejects sampling ratio changes on providers using SamplingTargetPerMinute.
+var errAdaptiveSampling = errors.New("otelx: sampling ratio is managed by samplingTargetPerMinute")
+
+// adaptiveController counts exported spans and steers the SamplingRatio sampler towards a span
+// budget per minute. It is registered as a span processor so TracerProvider.Shutdown stops it.
+type adaptiveController struct {
+	sampler  *dynamicSampler
+	target   float64
+	observer func(float64)
+	logger   logx.Logger
+	now      func() time.Time
+
+	exported atomic.Int64
+	last     time.Time
+	stop     func()
+}
+
+func newAdaptiveController(sampler *dynamicSampler, target float64, mp *sdkmetric.MeterProvider, observer func(float64), logger logx.Logger) (*adaptiveController, error) {
+	c := &adaptiveController{sampler: sampler, target: target, observer: observer, logger: logger, now: time.Now}
+	if mp != nil {
+		_, err := mp.Meter(instrumentationName).Float64ObservableGauge(SamplingRatioMetricName,
+			metric.WithDescription("Head sampling ratio chosen to meet the span budget."),
+			metric.WithUnit("1"),
+			metric.WithFloat64Callback(func(_ context.Context, o metric.Float64Observer) error {
+				o.Observe(sampler.ratio())
+				return nil
+			}))
+		if err != nil {
+			return nil, fmt.Errorf("otelx: create sampler ratio gauge: %w", err)
+		}
+	}
+	if observer != nil {
+		observer(sampler.ratio())
+	}
+	return c, nil
+}
+
+// start adjusts the ratio every interval until Shutdown.
+func (c *adaptiveController) start(interval time.Duration) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	c.last = c.now()
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				c.adjust(ctx)
+			}
+		}
+	}()
+	var once sync.Once
+	c.stop = func() {
+		once.Do(func() {
+			cancel()
+			<-done
+		})
+	}
+}
+
+// adjust scales the ratio by target/observed throughput of the last interval, at most
+// maxAdaptiveStep either way. With nothing exported it grows by the full step, so a ratio driven
+// down by a burst recovers once traffic calms.
+func (c *adaptiveController) adjust(ctx context.Context) {
+	now := c.now()
+	elapsed := now.Sub(c.last)
+	c.last = now
+	if elapsed <= 0 {
+		return
+	}
+	exported := float64(c.exported.Swap(0))
+	current := c.sampler.ratio()
+	next := current * maxAdaptiveStep
+	if exported > 0 {
+		perMinute := exported / elapsed.Minutes()
+		next = current * math.Min(c.target/perMinute, maxAdaptiveStep)
+	}
+	next = math.Max(next, current/maxAdaptiveStep)
+	next = math.Min(math.Max(next, minAdaptiveRatio), 1)
+	if next == current {
+		return
+	}
+	c.sampler.setRatio(next)
+	if c.observer != nil {
+		c.observer(next)
+	}
+	if c.logger != nil {
+		c.logger.Debug(ctx, "otelx.sampler.adaptive.adjusted",
+			logx.Float64("from", current), logx.Float64("to", next), logx.Float64("exportedPerMinute", exported/elapsed.Minutes()))
+	}
+}
+
+func (c *adaptiveController) OnStart(context.Context, sdktrace.ReadWriteSpan) {}
+
+func (c *adaptiveController) OnEnd(s sdktrace.ReadOnlySpan) {
+	if s.SpanContext().IsSampled() {
+		c.exported.Add(1)
+	}
+}
+
+func (c *adaptiveController) Shutdown(context.Context) error {
+	if c.stop != nil {
+		c.stop()
+	}
+	return nil
+}
+
+func (c *adaptiveController) ForceFlush(context.Context) error { return nil }
@@ -0,0 +1,107 @@
+package otelx
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestAdaptiveControllerTracksBudget(t *testing.T) {
+	sampler := newDynamicSampler(0.5)
+	var observed []float64
+	c, err := newAdaptiveController(sampler, 600, nil, func(r float64) { observed = append(observed, r) }, nil)
+	if err != nil {
+		t.Fatalf("new controller: %v", err)
+	}
+	now := time.Unix(0, 0)
+	c.now = func() time.Time { return now }
+	c.last = now
+	step := func(exported int64) float64 {
+		now = now.Add(10 * time.Second)
+		c.exported.Store(exported)
+		c.adjust(context.Background())
+		return sampler.ratio()
+	}
+
+	// 1000 spans in 10s is 6000/min, ten times the budget: the ratio drops by the maximum step.
+	if got := step(1000); got != 0.125 {
+		t.Fatalf("expected the ratio to fall to 0.125, got %v", got)
+	}
+	// 200 spans in 10s is 1200/min, twice the budget.
+	if got := step(200); got != 0.0625 {
+		t.Fatalf("expected the ratio to halve, got %v", got)
+	}
+	// On budget: unchanged.
+	if got := step(100); got != 0.0625 {
+		t.Fatalf("expected the ratio to hold, got %v", got)
+	}
+	// Idle: grows again, capped at 1.
+	step(0)
+	step(0)
+	if got := step(0); got != 1 {
+		t.Fatalf("expected the ratio to recover to 1, got %v", got)
+	}
+	if len(observed) != 5 || observed[0] != 0.5 || observed[len(observed)-1] != 1 {
+		t.Fatalf("unexpected observed ratios %v", observed)
+	}
+
+	sampler.setRatio(minAdaptiveRatio)
+	if got := step(1_000_000); got != minAdaptiveRatio {
+		t.Fatalf("expected the ratio to stay at the floor, got %v", got)
+	}
+}
+
+func TestSamplingTargetPerMinute(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	var initial float64
+	cfg := Config{ServiceName: "svc", Exporter: ExporterMemory, SamplingRatio: Float64(0.2), SamplingTargetPerMinute: 1000}
+	prov, err := Setup(context.Background(), cfg, nil,
+		WithMetricReader(reader),
+		WithSamplingRatioObserver(func(r float64) { initial = r }))
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	defer prov.Shutdown(context.Background())
+
+	if initial != 0.2 {
+		t.Fatalf("expected the observer to see the starting ratio, got %v", initial)
+	}
+	if err := prov.SetSamplingRatio(1); !errors.Is(err, errAdaptiveSampling) {
+		t.Fatalf("expected manual ratio changes to be rejected, got %v", err)
+	}
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("collect failed: %v", err)
+	}
+	ratio := math.NaN()
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name == SamplingRatioMetricName {
+				ratio = m.Data.(metricdata.Gauge[float64]).DataPoints[0].Value
+			}
+		}
+	}
+	if ratio != 0.2 {
+		t.Fatalf("expected %s gauge at 0.2, got %v", SamplingRatioMetricName, ratio)
+	}
+
+	found := false
+	for _, p := range prov.Describe().Processors {
+		found = found || p.Name == "adaptiveSampling"
+	}
+	if !found {
+		t.Fatalf("expected adaptiveSampling in the description")
+	}
+
+	bad := Config{ServiceName: "svc", Exporter: ExporterMemory, SamplingTargetPerMinute: 10,
+		RemoteSampling: &RemoteSamplingConfig{Endpoint: "http://localhost:5778/sampling"}}
+	if _, err := Setup(context.Background(), bad, nil); err == nil {
+		t.Fatalf("expected samplingTargetPerMinute and remoteSampling to be rejected together")
+	}
+}
@@ -0,0 +1,123 @@
+package otelx
+
+import (
+	"net/http"
+	"slices"
+	"sync"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// AttributeSet is an immutable attribute set computed once for hot paths. Build it at startup,
+// e.g. for the fixed attributes of a route, and pass its options on every call instead of
+// building the same KeyValue slice per request.
+type AttributeSet struct {
+	set         attribute.Set
+	kvs         []attribute.KeyValue
+	measurement metric.MeasurementOption
+	span        trace.SpanStartEventOption
+}
+
+// NewAttributeSet sorts and de-duplicates kvs, last value wins, and precomputes the options.
+func NewAttributeSet(kvs ...attribute.KeyValue) AttributeSet {
+	set := attribute.NewSet(kvs...)
+	// Clip so that callers appending to the shared slice copy it instead of writing into it.
+	sorted := slices.Clip(set.ToSlice())
+	return AttributeSet{
+		set:         set,
+		kvs:         sorted,
+		measurement: metric.WithAttributeSet(set),
+		span:        trace.WithAttributes(sorted...),
+	}
+}
+
+// Set returns the underlying attribute.Set.
+func (s AttributeSet) Set() attribute.Set { return s.set }
+
+// KeyValues returns the attributes in sorted order. The slice is shared and must not be modified.
+func (s AttributeSet) KeyValues() []attribute.KeyValue { return s.kvs }
+
+// Measurement returns the option for Add and Record calls on metric instruments.
+func (s AttributeSet) Measurement() metric.MeasurementOption {
+	if s.measurement == nil {
+		return metric.WithAttributeSet(attribute.NewSet())
+	}
+	return s.measurement
+}
+
+// SpanStart returns the option for Tracer.Start.
+func (s AttributeSet) SpanStart() trace.SpanStartEventOption {
+	if s.span == nil {
+		return trace.WithAttributes()
+	}
+	return s.span
+}
+
+// HTTPAttributes adds set to the spans and metrics of HTTPHandler or HTTPTransport.
+func HTTPAttributes(set AttributeSet) []otelhttp.Option {
+	kvs := set.KeyValues()
+	return []otelhttp.Option{
+		otelhttp.WithSpanOptions(set.SpanStart()),
+		otelhttp.WithMetricAttributesFn(func(*http.Request) []attribute.KeyValue { return kvs }),
+	}
+}
+
+// GRPCAttributes adds set to the spans and metrics of GRPCServerHandler or GRPCClientHandler.
+func GRPCAttributes(set AttributeSet) []otelgrpc.Option {
+	return []otelgrpc.Option{
+		otelgrpc.WithSpanAttributes(set.KeyValues()...),
+		otelgrpc.WithMetricAttributes(set.KeyValues()...),
+	}
+}
+
+// AttributeSetCache memoizes AttributeSets for attributes that vary per call but take few
+// distinct values, such as route and status. Once it holds limit sets it stops growing and
+// builds uncached sets, so an unexpectedly high-cardinality key cannot leak memory.
+type AttributeSetCache[K comparable] struct {
+	build func(K) []attribute.KeyValue
+	limit int
+
+	mu   sync.RWMutex
+	sets map[K]AttributeSet
+}
+
+// NewAttributeSetCache creates a cache that builds the attributes for a key with build.
+// A limit of zero or less means 1024.
+func NewAttributeSetCache[K comparable](limit int, build func(K) []attribute.KeyValue) *AttributeSetCache[K] {
+	if limit <= 0 {
+		limit = 1024
+	}
+	return &AttributeSetCache[K]{build: build, limit: limit, sets: map[K]AttributeSet{}}
+}
+
+// Get returns the set for key, building it on first use.
+func (c *AttributeSetCache[K]) Get(key K) AttributeSet {
+	c.mu.RLock()
+	set, ok := c.sets[key]
+	c.mu.RUnlock()
+	if ok {
+		return set
+	}
+
+	set = NewAttributeSet(c.build(key)...)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if existing, ok := c.sets[key]; ok {
+		return existing
+	}
+	if len(c.sets) < c.limit {
+		c.sets[key] = set
+	}
+	return set
+}
+
+// Len returns the number of cached sets.
+func (c *AttributeSetCache[K]) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.sets)
+}
@@ -0,0 +1,156 @@
+package otelx
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var benchAttrs = []attribute.KeyValue{
+	attribute.String("http.route", "/videos/{id}"),
+	attribute.String("rpc.service", "lingo.video.v1.VideoService"),
+	attribute.String("deployment.environment", "prod"),
+}
+
+func TestAttributeSetOptions(t *testing.T) {
+	set := NewAttributeSet(attribute.String("b", "2"), attribute.String("a", "1"), attribute.String("a", "3"))
+	if got := set.KeyValues(); len(got) != 2 || got[0] != attribute.String("a", "3") || got[1] != attribute.String("b", "2") {
+		t.Fatalf("expected sorted, de-duplicated attributes, got %v", got)
+	}
+	if kvs := set.KeyValues(); cap(kvs) != len(kvs) {
+		t.Fatalf("expected a clipped slice so appends copy, cap %d len %d", cap(kvs), len(kvs))
+	}
+
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	counter, _ := mp.Meter("test").Int64Counter("calls")
+	counter.Add(context.Background(), 1, set.Measurement())
+	counter.Add(context.Background(), 1, set.Measurement())
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("collect failed: %v", err)
+	}
+	points := rm.ScopeMetrics[0].Metrics[0].Data.(metricdata.Sum[int64]).DataPoints
+	if len(points) != 1 || points[0].Value != 2 || !points[0].Attributes.Equals(&set.set) {
+		t.Fatalf("expected one point with the set, got %+v", points)
+	}
+
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	_, span := tp.Tracer("test").Start(context.Background(), "op", set.SpanStart())
+	span.End()
+	if !spanHasAttribute(recorder.Ended()[0].Attributes(), "a", "3") {
+		t.Fatalf("expected span to carry the set, got %v", recorder.Ended()[0].Attributes())
+	}
+
+	var zero AttributeSet
+	counter.Add(context.Background(), 1, zero.Measurement())
+	_, span = tp.Tracer("test").Start(context.Background(), "empty", zero.SpanStart())
+	span.End()
+}
+
+func TestAttributeSetCacheLimit(t *testing.T) {
+	builds := 0
+	cache := NewAttributeSetCache(2, func(code int) []attribute.KeyValue {
+		builds++
+		return []attribute.KeyValue{attribute.Int("code", code)}
+	})
+
+	first := cache.Get(200)
+	if again := cache.Get(200); again.set.Equivalent() != first.set.Equivalent() || builds != 1 {
+		t.Fatalf("expected cached set, builds = %d", builds)
+	}
+	cache.Get(404)
+	overflow := cache.Get(500)
+	if cache.Len() != 2 {
+		t.Fatalf("expected the cache to stop at its limit, got %d", cache.Len())
+	}
+	if v, _ := overflow.set.Value("code"); v.AsInt64() != 500 {
+		t.Fatalf("expected uncached sets past the limit, got %v", overflow.KeyValues())
+	}
+}
+
+func BenchmarkMeasurementAttributes(b *testing.B) {
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(sdkmetric.NewManualReader()))
+	counter, _ := mp.Meter("bench").Int64Counter("calls")
+	ctx := context.Background()
+
+	b.Run("per-call", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			counter.Add(ctx, 1, metric.WithAttributes(
+				attribute.String("http.route", "/videos/{id}"),
+				attribute.String("rpc.service", "lingo.video.v1.VideoService"),
+				attribute.String("deployment.environment", "prod"),
+			))
+		}
+	})
+	b.Run("precomputed", func(b *testing.B) {
+		set := NewAttributeSet(benchAttrs...)
+		b.ReportAllocs()
+		for b.Loop() {
+			counter.Add(ctx, 1, set.Measurement())
+		}
+	})
+	b.Run("cached", func(b *testing.B) {
+		cache := NewAttributeSetCache(0, func(code int) []attribute.KeyValue {
+			return append([]attribute.KeyValue{attribute.Int("http.response.status_code", code)}, benchAttrs...)
+		})
+		b.ReportAllocs()
+		for i := 0; b.Loop(); i++ {
+			counter.Add(ctx, 1, cache.Get(200+i%4).Measurement())
+		}
+	})
+}
+
+func BenchmarkSpanStartAttributes(b *testing.B) {
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSampler(sdktrace.NeverSample()))
+	tracer := tp.Tracer("bench")
+	ctx := context.Background()
+
+	b.Run("per-call", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			_, span := tracer.Start(ctx, "op", trace.WithAttributes(
+				attribute.String("http.route", "/videos/{id}"),
+				attribute.String("rpc.service", "lingo.video.v1.VideoService"),
+				attribute.String("deployment.environment", "prod"),
+			))
+			span.End()
+		}
+	})
+	b.Run("precomputed", func(b *testing.B) {
+		set := NewAttributeSet(benchAttrs...)
+		b.ReportAllocs()
+		for b.Loop() {
+			_, span := tracer.Start(ctx, "op", set.SpanStart())
+			span.End()
+		}
+	})
+}
+
+func BenchmarkSpanMetricsAttributes(b *testing.B) {
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(sdkmetric.NewManualReader()))
+	processor, _ := newSpanMetricsProcessor(context.Background(), mp, nil)
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	for i := range 8 {
+		_, span := tp.Tracer("bench").Start(context.Background(), "GET /route/"+strconv.Itoa(i))
+		span.End()
+	}
+	spans := recorder.Ended()
+
+	b.ReportAllocs()
+	for i := 0; b.Loop(); i++ {
+		processor.OnEnd(spans[i%len(spans)])
+	}
+}
@@ -0,0 +1,259 @@
+package otelx
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// defaultAzureIngestionEndpoint is used when the connection string has no IngestionEndpoint.
+const defaultAzureIngestionEndpoint = "https://dc.services.visualstudio.com/"
+
+// azureConnection holds the parts of an Application Insights connection string otelx needs.
+type azureConnection struct {
+	InstrumentationKey string
+	IngestionEndpoint  string
+}
+
+// parseAzureConnectionString parses "InstrumentationKey=...;IngestionEndpoint=https://..." as
+// shown on the Application Insights resource. Keys are case-insensitive.
+func parseAzureConnectionString(s string) (azureConnection, error) {
+	var conn azureConnection
+	for _, part := range strings.Split(s, ";") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "instrumentationkey":
+			conn.InstrumentationKey = strings.TrimSpace(value)
+		case "ingestionendpoint":
+			conn.IngestionEndpoint = strings.TrimSpace(value)
+		}
+	}
+	if conn.InstrumentationKey == "" {
+		return conn, fmt.Errorf("azureConnectionString has no InstrumentationKey")
+	}
+	if conn.IngestionEndpoint == "" {
+		conn.IngestionEndpoint = defaultAzureIngestionEndpoint
+	}
+	return conn, nil
+}
+
+// azureMonitorExporter sends spans to the Application Insights ingestion API as request and
+// dependency telemetry, the same mapping the official OpenTelemetry distros use.
+type azureMonitorExporter struct {
+	iKey   string
+	url    string
+	client *http.Client
+}
+
+func newAzureMonitorExporter(conn azureConnection, client *http.Client) *azureMonitorExporter {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &azureMonitorExporter{
+		iKey:   conn.InstrumentationKey,
+		url:    strings.TrimSuffix(conn.IngestionEndpoint, "/") + "/v2.1/track",
+		client: client,
+	}
+}
+
+func (e *azureMonitorExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	var body bytes.Buffer
+	enc := json.NewEncoder(&body)
+	for _, span := range spans {
+		if err := enc.Encode(azureEnvelopeFromSpan(e.iKey, span)); err != nil {
+			return fmt.Errorf("otelx: encode azure monitor telemetry: %w", err)
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url, &body)
+	if err != nil {
+		return fmt.Errorf("otelx: create azure monitor request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-json-stream")
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("otelx: send azure monitor telemetry: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		ItemsReceived int `json:"itemsReceived"`
+		ItemsAccepted int `json:"itemsAccepted"`
+		Errors        []struct {
+			StatusCode int    `json:"statusCode"`
+			Message    string `json:"message"`
+		} `json:"errors"`
+	}
+	_ = json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&result)
+	switch {
+	case resp.StatusCode == http.StatusOK:
+		return nil
+	case len(result.Errors) > 0:
+		return fmt.Errorf("otelx: azure monitor accepted %d of %d items (status %d): %s",
+			result.ItemsAccepted, result.ItemsReceived, resp.StatusCode, result.Errors[0].Message)
+	default:
+		return fmt.Errorf("otelx: azure monitor returned status %d", resp.StatusCode)
+	}
+}
+
+func (e *azureMonitorExporter) Shutdown(context.Context) error {
+	return nil
+}
+
+// azureEnvelope is the Application Insights telemetry envelope.
+type azureEnvelope struct {
+	Name string            `json:"name"`
+	Time string            `json:"time"`
+	IKey string            `json:"iKey"`
+	Tags map[string]string `json:"tags"`
+	Data azureData         `json:"data"`
+}
+
+type azureData struct {
+	BaseType string        `json:"baseType"`
+	BaseData azureBaseData `json:"baseData"`
+}
+
+// azureBaseData covers both RequestData and RemoteDependencyData; unused fields are omitted.
+type azureBaseData struct {
+	Ver          int               `json:"ver"`
+	ID           string            `json:"id"`
+	Name         string            `json:"name"`
+	Duration     string            `json:"duration"`
+	Success      bool              `json:"success"`
+	ResponseCode string            `json:"responseCode,omitempty"`
+	URL          string            `json:"url,omitempty"`
+	ResultCode   string            `json:"resultCode,omitempty"`
+	Type         string            `json:"type,omitempty"`
+	Target       string            `json:"target,omitempty"`
+	Data         string            `json:"data,omitempty"`
+	Properties   map[string]string `json:"properties,omitempty"`
+}
+
+// azureEnvelopeFromSpan maps server and consumer spans to RequestData and everything else to
+// RemoteDependencyData, keeping span attributes as custom properties.
+func azureEnvelopeFromSpan(iKey string, span sdktrace.ReadOnlySpan) azureEnvelope {
+	sc := span.SpanContext()
+	tags := map[string]string{
+		"ai.operation.id":   sc.TraceID().String(),
+		"ai.operation.name": span.Name(),
+	}
+	if parent := span.Parent(); parent.IsValid() {
+		tags["ai.operation.parentId"] = parent.SpanID().String()
+	}
+	if res := span.Resource(); res != nil {
+		if v, ok := res.Set().Value(semconv.ServiceNameKey); ok {
+			tags["ai.cloud.role"] = v.AsString()
+		}
+		if v, ok := res.Set().Value(semconv.ServiceInstanceIDKey); ok {
+			tags["ai.cloud.roleInstance"] = v.AsString()
+		}
+	}
+
+	data := azureBaseData{
+		Ver:      2,
+		ID:       sc.SpanID().String(),
+		Name:     span.Name(),
+		Duration: azureDuration(span.EndTime().Sub(span.StartTime())),
+	}
+	var status string
+	for _, kv := range span.Attributes() {
+		switch kv.Key {
+		case semconv.HTTPResponseStatusCodeKey, "http.status_code", semconv.RPCGRPCStatusCodeKey:
+			status = kv.Value.Emit()
+		case semconv.URLFullKey, "http.url":
+			data.URL = kv.Value.Emit()
+		case semconv.ServerAddressKey, semconv.PeerServiceKey:
+			data.Target = kv.Value.Emit()
+		case semconv.DBQueryTextKey:
+			data.Data = kv.Value.Emit()
+		}
+		if data.Properties == nil {
+			data.Properties = map[string]string{}
+		}
+		data.Properties[string(kv.Key)] = kv.Value.Emit()
+	}
+	data.Success = azureSuccess(span, status)
+
+	env := azureEnvelope{
+		Time: span.StartTime().UTC().Format(time.RFC3339Nano),
+		IKey: iKey,
+		Tags: tags,
+	}
+	switch span.SpanKind() {
+	case trace.SpanKindServer, trace.SpanKindConsumer:
+		data.ResponseCode = status
+		if data.ResponseCode == "" {
+			data.ResponseCode = "0"
+		}
+		env.Name = "Microsoft.ApplicationInsights.Request"
+		env.Data = azureData{BaseType: "RequestData", BaseData: data}
+	default:
+		data.ResultCode = status
+		data.Type = azureDependencyType(span)
+		env.Name = "Microsoft.ApplicationInsights.RemoteDependency"
+		env.Data = azureData{BaseType: "RemoteDependencyData", BaseData: data}
+	}
+	return env
+}
+
+func azureSuccess(span sdktrace.ReadOnlySpan, status string) bool {
+	if span.Status().Code == codes.Error {
+		return false
+	}
+	if code, err := strconv.Atoi(status); err == nil && code >= 400 {
+		return false
+	}
+	return true
+}
+
+func azureDependencyType(span sdktrace.ReadOnlySpan) string {
+	if span.SpanKind() == trace.SpanKindInternal {
+		return "InProc"
+	}
+	attrs := attribute.NewSet(span.Attributes()...)
+	switch {
+	case attrs.HasValue(semconv.HTTPRequestMethodKey), attrs.HasValue("http.method"):
+		return "HTTP"
+	case attrs.HasValue(semconv.DBSystemNameKey):
+		return "SQL"
+	case attrs.HasValue(semconv.RPCSystemKey):
+		if v, _ := attrs.Value(semconv.RPCSystemKey); v.AsString() != "" {
+			return v.AsString()
+		}
+	case attrs.HasValue(semconv.MessagingSystemKey):
+		return "Queue Message"
+	}
+	return "Dependency"
+}
+
+// azureDuration formats d as the d.hh:mm:ss.ffffff TimeSpan Application Insights expects.
+func azureDuration(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+	days := d / (24 * time.Hour)
+	d -= days * 24 * time.Hour
+	h := d / time.Hour
+	d -= h * time.Hour
+	m := d / time.Minute
+	d -= m * time.Minute
+	s := d / time.Second
+	d -= s * time.Second
+	return fmt.Sprintf("%d.%02d:%02d:%02d.%06d", days, h, m, s, d/time.Microsecond)
+}
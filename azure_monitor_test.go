@@ -0,0 +1,114 @@
+package otelx
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestParseAzureConnectionString(t *testing.T) {
+	conn, err := parseAzureConnectionString("InstrumentationKey=abc; IngestionEndpoint=https://westeurope-5.in.applicationinsights.azure.com/;LiveEndpoint=https://live")
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	if conn.InstrumentationKey != "abc" || conn.IngestionEndpoint != "https://westeurope-5.in.applicationinsights.azure.com/" {
+		t.Fatalf("unexpected connection %+v", conn)
+	}
+	if conn, _ := parseAzureConnectionString("instrumentationkey=abc"); conn.IngestionEndpoint != defaultAzureIngestionEndpoint {
+		t.Fatalf("expected default ingestion endpoint, got %q", conn.IngestionEndpoint)
+	}
+	if _, err := parseAzureConnectionString("IngestionEndpoint=https://x"); err == nil {
+		t.Fatalf("expected error without InstrumentationKey")
+	}
+}
+
+func TestAzureMonitorExporterSendsTelemetry(t *testing.T) {
+	var envelopes []azureEnvelope
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2.1/track" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		scanner := bufio.NewScanner(r.Body)
+		for scanner.Scan() {
+			var env azureEnvelope
+			if err := json.Unmarshal(scanner.Bytes(), &env); err != nil {
+				t.Errorf("invalid envelope: %v", err)
+			}
+			envelopes = append(envelopes, env)
+		}
+		_, _ = w.Write([]byte(`{"itemsReceived":2,"itemsAccepted":2,"errors":[]}`))
+	}))
+	defer srv.Close()
+
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	ctx, server := tp.Tracer("test").Start(context.Background(), "GET /items", trace.WithSpanKind(trace.SpanKindServer))
+	server.SetAttributes(attribute.Int("http.response.status_code", 200))
+	_, client := tp.Tracer("test").Start(ctx, "GET", trace.WithSpanKind(trace.SpanKindClient))
+	client.SetAttributes(attribute.String("http.request.method", "GET"), attribute.Int("http.response.status_code", 503))
+	client.End()
+	server.End()
+
+	exporter := newAzureMonitorExporter(azureConnection{InstrumentationKey: "ikey", IngestionEndpoint: srv.URL + "/"}, nil)
+	if err := exporter.ExportSpans(context.Background(), recorder.Ended()); err != nil {
+		t.Fatalf("export failed: %v", err)
+	}
+	if len(envelopes) != 2 {
+		t.Fatalf("expected two envelopes, got %d", len(envelopes))
+	}
+	dep, req := envelopes[0], envelopes[1]
+	if req.Data.BaseType != "RequestData" || req.IKey != "ikey" || !req.Data.BaseData.Success || req.Data.BaseData.ResponseCode != "200" {
+		t.Fatalf("unexpected request telemetry %+v", req)
+	}
+	if dep.Data.BaseType != "RemoteDependencyData" || dep.Data.BaseData.Type != "HTTP" || dep.Data.BaseData.Success {
+		t.Fatalf("unexpected dependency telemetry %+v", dep)
+	}
+	if dep.Tags["ai.operation.id"] != server.SpanContext().TraceID().String() || dep.Tags["ai.operation.parentId"] != req.Data.BaseData.ID {
+		t.Fatalf("expected dependency to be correlated with the request, got %v", dep.Tags)
+	}
+}
+
+func TestAzureMonitorExporterReportsPartialFailure(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusPartialContent)
+		_, _ = w.Write([]byte(`{"itemsReceived":1,"itemsAccepted":0,"errors":[{"index":0,"statusCode":400,"message":"invalid"}]}`))
+	}))
+	defer srv.Close()
+
+	exporter := newAzureMonitorExporter(azureConnection{InstrumentationKey: "ikey", IngestionEndpoint: srv.URL}, nil)
+	if err := exporter.ExportSpans(context.Background(), []sdktrace.ReadOnlySpan{spanWithAttributes(t)}); err == nil {
+		t.Fatalf("expected error for rejected items")
+	}
+}
+
+func TestAzureDuration(t *testing.T) {
+	if got := azureDuration(26*time.Hour + 3*time.Minute + 4*time.Second + 5*time.Millisecond); got != "1.02:03:04.005000" {
+		t.Fatalf("unexpected duration %q", got)
+	}
+}
+
+func TestSetupAzureMonitorValidation(t *testing.T) {
+	prov, err := Setup(context.Background(), Config{ServiceName: "svc", Exporter: ExporterAzureMonitor, AzureConnectionString: "InstrumentationKey=abc"}, noopLogger{})
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	_ = prov.Shutdown(context.Background())
+
+	for name, cfg := range map[string]Config{
+		"missing":   {ServiceName: "svc", Exporter: ExporterAzureMonitor},
+		"elsewhere": {ServiceName: "svc", Exporter: ExporterStdout, AzureConnectionString: "InstrumentationKey=abc"},
+	} {
+		if _, err := Setup(context.Background(), cfg, nil); err == nil {
+			t.Fatalf("%s: expected validation error", name)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package otelx
+
+import (
+	"context"
+	"sort"
+	"sync/atomic"
+	"time"
+
+	logx "github.com/bionicotaku/lingo-utils-logx"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// W3C Baggage limits applied on inject when Config leaves them unset.
+const (
+	DefaultBaggageMaxMembers = 64
+	DefaultBaggageMaxBytes   = 8192
+)
+
+// baggageWarnInterval throttles truncation warnings so a hot path cannot flood the logs.
+const baggageWarnInterval = time.Minute
+
+// LimitedBaggage returns a W3C Baggage propagator that enforces member count and header size limits
+// on inject. Members beyond the limits are dropped (in key order) and a throttled warning is logged,
+// instead of emitting oversized headers that some proxies silently discard. Non-positive limits fall
+// back to the W3C defaults.
+func LimitedBaggage(maxMembers, maxBytes int, logger logx.Logger) propagation.TextMapPropagator {
+	if maxMembers <= 0 {
+		maxMembers = DefaultBaggageMaxMembers
+	}
+	if maxBytes <= 0 {
+		maxBytes = DefaultBaggageMaxBytes
+	}
+	return &limitedBaggage{maxMembers: maxMembers, maxBytes: maxBytes, logger: logger}
+}
+
+type limitedBaggage struct {
+	propagation.Baggage
+	maxMembers int
+	maxBytes   int
+	logger     logx.Logger
+	lastWarn   atomic.Int64
+}
+
+func (b *limitedBaggage) Inject(ctx context.Context, carrier propagation.TextMapCarrier) {
+	bag := baggage.FromContext(ctx)
+	if limited, dropped := limitBaggage(bag, b.maxMembers, b.maxBytes); dropped > 0 {
+		b.warn(ctx, bag.Len(), dropped)
+		ctx = baggage.ContextWithBaggage(ctx, limited)
+	}
+	b.Baggage.Inject(ctx, carrier)
+}
+
+func (b *limitedBaggage) warn(ctx context.Context, members, dropped int) {
+	if b.logger == nil {
+		return
+	}
+	now := time.Now().UnixNano()
+	last := b.lastWarn.Load()
+	if now-last < int64(baggageWarnInterval) || !b.lastWarn.CompareAndSwap(last, now) {
+		return
+	}
+	b.logger.Warn(ctx, "otelx.baggage.truncated",
+		logx.Int("members", members),
+		logx.Int("dropped", dropped),
+		logx.Int("maxMembers", b.maxMembers),
+		logx.Int("maxBytes", b.maxBytes),
+	)
+}
+
+// limitBaggage keeps members in key order until either limit is reached and reports how many were dropped.
+func limitBaggage(bag baggage.Baggage, maxMembers, maxBytes int) (baggage.Baggage, int) {
+	if bag.Len() == 0 || (bag.Len() <= maxMembers && len(bag.String()) <= maxBytes) {
+		return bag, 0
+	}
+
+	members := bag.Members()
+	sort.Slice(members, func(i, j int) bool { return members[i].Key() < members[j].Key() })
+
+	kept := make([]baggage.Member, 0, maxMembers)
+	size := 0
+	for _, m := range members {
+		if len(kept) == maxMembers {
+			break
+		}
+		n := len(m.String())
+		if len(kept) > 0 {
+			n++ // list-member separator
+		}
+		if size+n > maxBytes {
+			continue
+		}
+		kept = append(kept, m)
+		size += n
+	}
+
+	limited, err := baggage.New(kept...)
+	if err != nil {
+		return baggage.Baggage{}, len(members)
+	}
+	return limited, len(members) - len(kept)
+}
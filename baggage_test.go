@@ -0,0 +1,87 @@
+package otelx
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+func TestLimitedBaggageDropsMembersBeyondCount(t *testing.T) {
+	ctx := contextWithBaggage(t, 5, 4)
+	carrier := propagation.MapCarrier{}
+	LimitedBaggage(3, 0, noopLogger{}).Inject(ctx, carrier)
+
+	extracted := baggage.FromContext(propagation.Baggage{}.Extract(context.Background(), carrier))
+	if extracted.Len() != 3 {
+		t.Fatalf("expected 3 members, got %q", carrier.Get("baggage"))
+	}
+	for _, key := range []string{"k0", "k1", "k2"} {
+		if extracted.Member(key).Key() == "" {
+			t.Fatalf("expected members to be kept in key order, got %q", carrier.Get("baggage"))
+		}
+	}
+}
+
+func TestLimitedBaggageDropsMembersBeyondBytes(t *testing.T) {
+	ctx := contextWithBaggage(t, 4, 10)
+	carrier := propagation.MapCarrier{}
+	LimitedBaggage(0, 30, nil).Inject(ctx, carrier)
+
+	header := carrier.Get("baggage")
+	if len(header) > 30 {
+		t.Fatalf("expected header within 30 bytes, got %d: %q", len(header), header)
+	}
+	if n := len(strings.Split(header, ",")); n != 2 {
+		t.Fatalf("expected 2 members to fit, got %d: %q", n, header)
+	}
+}
+
+func TestLimitedBaggageKeepsBaggageWithinLimits(t *testing.T) {
+	ctx := contextWithBaggage(t, 2, 2)
+	carrier := propagation.MapCarrier{}
+	LimitedBaggage(0, 0, nil).Inject(ctx, carrier)
+
+	extracted := baggage.FromContext(propagation.Baggage{}.Extract(context.Background(), carrier))
+	if extracted.Len() != 2 {
+		t.Fatalf("expected baggage to be untouched, got %q", carrier.Get("baggage"))
+	}
+}
+
+func TestSetupRejectsNegativeBaggageLimits(t *testing.T) {
+	if _, err := Setup(context.Background(), Config{ServiceName: "svc", BaggageMaxBytes: -1}, nil); err == nil {
+		t.Fatalf("expected error for negative baggage limit")
+	}
+}
+
+func TestSetupDisabledKeepsBaggageLimits(t *testing.T) {
+	prov, err := Setup(context.Background(), Config{ServiceName: "svc", Enabled: Bool(false), BaggageMaxMembers: 3}, nil)
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	carrier := propagation.MapCarrier{}
+	prov.Propagator.Inject(contextWithBaggage(t, 5, 4), carrier)
+	if n := len(strings.Split(carrier.Get("baggage"), ",")); n != 3 {
+		t.Fatalf("expected the disabled provider to keep 3 members, got %q", carrier.Get("baggage"))
+	}
+}
+
+func contextWithBaggage(t *testing.T, members, valueLen int) context.Context {
+	t.Helper()
+	list := make([]baggage.Member, 0, members)
+	for i := 0; i < members; i++ {
+		m, err := baggage.NewMember(fmt.Sprintf("k%d", i), strings.Repeat("v", valueLen))
+		if err != nil {
+			t.Fatalf("new member: %v", err)
+		}
+		list = append(list, m)
+	}
+	bag, err := baggage.New(list...)
+	if err != nil {
+		t.Fatalf("new baggage: %v", err)
+	}
+	return baggage.ContextWithBaggage(context.Background(), bag)
+}
@@ -0,0 +1,68 @@
+package otelx
+
+import (
+	"errors"
+	"time"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+const (
+	// DefaultBatchTimeout is how long the span batcher waits before exporting a partial batch.
+	DefaultBatchTimeout = 5 * time.Second
+	// DefaultMaxExportBatchSize is the largest batch the span batcher exports at once.
+	DefaultMaxExportBatchSize = 512
+)
+
+// BatchConfig tunes the span batchers, e.g. a larger queue and batches for high-throughput
+// services. Zero fields keep the defaults: DefaultBatchTimeout, DefaultMaxExportBatchSize and the
+// SDK's queue of 2048 spans and 30s export timeout.
+type BatchConfig struct {
+	// MaxQueueSize bounds the spans waiting for export; further spans are dropped.
+	MaxQueueSize int `json:"maxQueueSize"`
+	// BatchTimeout exports a partial batch after this long.
+	BatchTimeout time.Duration `json:"batchTimeout"`
+	// MaxExportBatchSize caps the spans per export call; it must not exceed MaxQueueSize and is
+	// lowered to a smaller queue when only MaxQueueSize is set.
+	MaxExportBatchSize int `json:"maxExportBatchSize"`
+	// ExportTimeout bounds a single export call.
+	ExportTimeout time.Duration `json:"exportTimeout"`
+}
+
+func (c BatchConfig) validate() error {
+	if c.MaxQueueSize < 0 || c.MaxExportBatchSize < 0 {
+		return errors.New("maxQueueSize and maxExportBatchSize must not be negative")
+	}
+	if c.BatchTimeout < 0 || c.ExportTimeout < 0 {
+		return errors.New("batchTimeout and exportTimeout must not be negative")
+	}
+	if c.MaxQueueSize > 0 && c.MaxExportBatchSize > c.MaxQueueSize {
+		return errors.New("maxExportBatchSize must not exceed maxQueueSize")
+	}
+	return nil
+}
+
+// batchOptions returns the BatchSpanProcessor options for c.
+func (c BatchConfig) batchOptions() []sdktrace.BatchSpanProcessorOption {
+	timeout, batchSize := c.BatchTimeout, c.MaxExportBatchSize
+	if timeout == 0 {
+		timeout = DefaultBatchTimeout
+	}
+	if batchSize == 0 {
+		batchSize = DefaultMaxExportBatchSize
+		if c.MaxQueueSize > 0 && c.MaxQueueSize < batchSize {
+			batchSize = c.MaxQueueSize
+		}
+	}
+	opts := []sdktrace.BatchSpanProcessorOption{
+		sdktrace.WithBatchTimeout(timeout),
+		sdktrace.WithMaxExportBatchSize(batchSize),
+	}
+	if c.MaxQueueSize > 0 {
+		opts = append(opts, sdktrace.WithMaxQueueSize(c.MaxQueueSize))
+	}
+	if c.ExportTimeout > 0 {
+		opts = append(opts, sdktrace.WithExportTimeout(c.ExportTimeout))
+	}
+	return opts
+}
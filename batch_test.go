@@ -0,0 +1,53 @@
+package otelx
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func TestBatchConfigOptions(t *testing.T) {
+	cases := []struct {
+		cfg  BatchConfig
+		want sdktrace.BatchSpanProcessorOptions
+	}{
+		{
+			cfg:  BatchConfig{},
+			want: sdktrace.BatchSpanProcessorOptions{BatchTimeout: DefaultBatchTimeout, MaxExportBatchSize: DefaultMaxExportBatchSize},
+		},
+		{
+			cfg:  BatchConfig{MaxQueueSize: 8192, BatchTimeout: time.Second, MaxExportBatchSize: 2048, ExportTimeout: 10 * time.Second},
+			want: sdktrace.BatchSpanProcessorOptions{MaxQueueSize: 8192, BatchTimeout: time.Second, MaxExportBatchSize: 2048, ExportTimeout: 10 * time.Second},
+		},
+		{
+			cfg:  BatchConfig{MaxQueueSize: 100},
+			want: sdktrace.BatchSpanProcessorOptions{MaxQueueSize: 100, BatchTimeout: DefaultBatchTimeout, MaxExportBatchSize: 100},
+		},
+	}
+	for _, tc := range cases {
+		var got sdktrace.BatchSpanProcessorOptions
+		for _, opt := range tc.cfg.batchOptions() {
+			opt(&got)
+		}
+		if got != tc.want {
+			t.Fatalf("%+v: expected %+v, got %+v", tc.cfg, tc.want, got)
+		}
+	}
+}
+
+func TestBatchConfigValidation(t *testing.T) {
+	cases := map[string]Config{
+		"otelx: batch: maxExportBatchSize must not exceed maxQueueSize":     {Batch: &BatchConfig{MaxQueueSize: 10, MaxExportBatchSize: 20}},
+		"otelx: batch: batchTimeout and exportTimeout must not be negative": {Batch: &BatchConfig{ExportTimeout: -time.Second}},
+		"otelx: batch and deferredExport are mutually exclusive":            {Batch: &BatchConfig{}, DeferredExport: &DeferredExportConfig{}},
+	}
+	for want, cfg := range cases {
+		cfg.ServiceName, cfg.Exporter = "svc", ExporterMemory
+		if _, err := Setup(context.Background(), cfg, nil); err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected %q, got %v", want, err)
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package otelx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	logx "github.com/bionicotaku/lingo-utils-logx"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// CanarySpanName names the synthetic spans emitted by Provider.StartCanary.
+	CanarySpanName = "otelx.canary"
+	// CanaryKey marks synthetic canary spans so backends and queries can filter them out.
+	CanaryKey = attribute.Key("otelx.canary")
+)
+
+// CanaryResult describes one canary round.
+type CanaryResult struct {
+	TraceID trace.TraceID
+	// Latency is the time from emitting the canary span until every pipeline accepted it.
+	Latency time.Duration
+	// Err is non-nil when at least one pipeline did not export the canary span.
+	Err error
+}
+
+type canaryContextKey struct{}
+
+// canarySampler samples canary spans regardless of the configured ratio and defers everything
+// else to the wrapped sampler.
+type canarySampler struct {
+	sdktrace.Sampler
+}
+
+func (s canarySampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	if p.ParentContext != nil && p.ParentContext.Value(canaryContextKey{}) != nil {
+		return sdktrace.SamplingResult{
+			Decision:   sdktrace.RecordAndSample,
+			Tracestate: trace.SpanContextFromContext(p.ParentContext).TraceState(),
+		}
+	}
+	return s.Sampler.ShouldSample(p)
+}
+
+// isCanarySpan reports whether span was emitted by Provider.StartCanary.
+func isCanarySpan(span sdktrace.ReadOnlySpan) bool {
+	if span.Name() != CanarySpanName {
+		return false
+	}
+	for _, kv := range span.Attributes() {
+		if kv.Key == CanaryKey {
+			return kv.Value.AsBool()
+		}
+	}
+	return false
+}
+
+// canaryMonitor counts, per canary span, how many export pipelines accepted it.
+type canaryMonitor struct {
+	pipelines int
+
+	mu      sync.Mutex
+	pending map[trace.SpanID]int
+}
+
+func newCanaryMonitor(pipelines int) *canaryMonitor {
+	return &canaryMonitor{pipelines: pipelines, pending: map[trace.SpanID]int{}}
+}
+
+func (m *canaryMonitor) expect(id trace.SpanID) {
+	m.mu.Lock()
+	m.pending[id] = m.pipelines
+	m.mu.Unlock()
+}
+
+func (m *canaryMonitor) delivered(id trace.SpanID) {
+	m.mu.Lock()
+	if n, ok := m.pending[id]; ok {
+		m.pending[id] = n - 1
+	}
+	m.mu.Unlock()
+}
+
+// settle reports how many pipelines have not exported id yet and stops tracking it.
+func (m *canaryMonitor) settle(id trace.SpanID) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	missing := m.pending[id]
+	delete(m.pending, id)
+	return missing
+}
+
+// canaryExporter reports canary spans to the monitor once the wrapped exporter accepted them.
+type canaryExporter struct {
+	sdktrace.SpanExporter
+	monitor *canaryMonitor
+}
+
+func (e *canaryExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	if err := e.SpanExporter.ExportSpans(ctx, spans); err != nil {
+		return err
+	}
+	for _, span := range spans {
+		if isCanarySpan(span) {
+			e.monitor.delivered(span.SpanContext().SpanID())
+		}
+	}
+	return nil
+}
+
+// StartCanary emits a synthetic CanarySpanName trace every interval, flushes it through the full
+// pipeline and checks that every exporter accepted it. Failures are logged as otelx.canary.failed
+// and, like successes, passed to onResult when it is non-nil. Each round force-flushes the
+// provider, so pending application spans are exported early. Call the returned stop function to
+// end the canary; it is only available for providers whose exporters Setup created.
+func (p *Provider) StartCanary(interval time.Duration, onResult func(CanaryResult)) (func(), error) {
+	if p == nil || p.canary == nil {
+		return nil, errors.New("otelx: canary requires a provider built by Setup")
+	}
+	if interval <= 0 {
+		return nil, fmt.Errorf("otelx: canary interval must be positive, got %v", interval)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				result := p.runCanary(ctx, interval)
+				if ctx.Err() != nil {
+					return
+				}
+				p.reportCanary(ctx, result)
+				if onResult != nil {
+					onResult(result)
+				}
+			}
+		}
+	}()
+	return func() {
+		cancel()
+		<-done
+	}, nil
+}
+
+func (p *Provider) runCanary(ctx context.Context, timeout time.Duration) CanaryResult {
+	spanCtx := context.WithValue(ctx, canaryContextKey{}, true)
+	_, span := p.TP.Tracer(instrumentationName).Start(spanCtx, CanarySpanName,
+		trace.WithNewRoot(),
+		trace.WithAttributes(CanaryKey.Bool(true)),
+	)
+	sc := span.SpanContext()
+	p.canary.expect(sc.SpanID())
+	start := time.Now()
+	span.End()
+
+	flushCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	flushErr := p.TP.ForceFlush(flushCtx)
+
+	result := CanaryResult{TraceID: sc.TraceID(), Latency: time.Since(start)}
+	if missing := p.canary.settle(sc.SpanID()); missing > 0 {
+		result.Err = fmt.Errorf("otelx: canary not exported by %d of %d pipelines", missing, p.canary.pipelines)
+		if flushErr != nil {
+			result.Err = fmt.Errorf("%w: %w", result.Err, flushErr)
+		}
+	}
+	return result
+}
+
+func (p *Provider) reportCanary(ctx context.Context, result CanaryResult) {
+	if p.logger == nil {
+		return
+	}
+	if result.Err != nil {
+		p.logger.Warn(ctx, "otelx.canary.failed",
+			logx.String("traceId", result.TraceID.String()),
+			logx.String("error", result.Err.Error()),
+		)
+		return
+	}
+	p.logger.Debug(ctx, "otelx.canary.delivered",
+		logx.String("traceId", result.TraceID.String()),
+		logx.String("latency", result.Latency.String()),
+	)
+}
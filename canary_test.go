@@ -0,0 +1,91 @@
+package otelx
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+type failingExporter struct{}
+
+func (failingExporter) ExportSpans(context.Context, []sdktrace.ReadOnlySpan) error {
+	return errors.New("backend unavailable")
+}
+func (failingExporter) Shutdown(context.Context) error { return nil }
+
+func TestStartCanaryDeliversThroughUnsampledPipeline(t *testing.T) {
+	cfg := Config{
+		ServiceName:   "svc",
+		SamplingRatio: Float64(0),
+		Exporters: []ExporterConfig{
+			{Exporter: ExporterMemory},
+			{Exporter: ExporterMemory, ExportRatio: Float64(0)},
+		},
+	}
+	prov, err := Setup(context.Background(), cfg, noopLogger{})
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	defer prov.Shutdown(context.Background())
+
+	results := make(chan CanaryResult, 4)
+	stop, err := prov.StartCanary(10*time.Millisecond, func(r CanaryResult) { results <- r })
+	if err != nil {
+		t.Fatalf("start canary: %v", err)
+	}
+	result := <-results
+	stop()
+
+	if result.Err != nil {
+		t.Fatalf("expected canary to be delivered, got %v", result.Err)
+	}
+	for i, mem := range prov.memories {
+		spans := mem.GetSpans()
+		if len(spans) == 0 || spans[0].Name != CanarySpanName || spans[0].SpanContext.TraceID() != result.TraceID {
+			t.Fatalf("expected pipeline %d to receive the canary span, got %v", i, spans)
+		}
+	}
+}
+
+func TestStartCanaryReportsExportFailure(t *testing.T) {
+	rec := &recordingLogger{}
+	mem := tracetest.NewInMemoryExporter()
+	prov, err := Setup(context.Background(), Config{ServiceName: "svc"}, rec,
+		WithSpanExporter(mem), WithSpanExporter(failingExporter{}))
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	defer prov.Shutdown(context.Background())
+
+	results := make(chan CanaryResult, 4)
+	stop, err := prov.StartCanary(10*time.Millisecond, func(r CanaryResult) { results <- r })
+	if err != nil {
+		t.Fatalf("start canary: %v", err)
+	}
+	result := <-results
+	stop()
+
+	if result.Err == nil {
+		t.Fatalf("expected canary failure when a pipeline rejects spans")
+	}
+	if !slices.Contains(rec.Entries(), "warn:otelx.canary.failed") {
+		t.Fatalf("expected otelx.canary.failed warning, got %v", rec.Entries())
+	}
+}
+
+func TestStartCanaryRequiresOwnedPipeline(t *testing.T) {
+	tp := sdktrace.NewTracerProvider()
+	defer tp.Shutdown(context.Background())
+	prov, err := Setup(context.Background(), Config{ServiceName: "svc"}, nil, WithTracerProvider(tp))
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	if _, err := prov.StartCanary(time.Second, nil); err == nil {
+		t.Fatalf("expected error for external tracer provider")
+	}
+}
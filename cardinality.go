@@ -0,0 +1,139 @@
+package otelx
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"sync"
+	"time"
+
+	logx "github.com/bionicotaku/lingo-utils-logx"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// DefaultCardinalityWindow is the tracking window used when CardinalityLimits.Window is unset.
+const DefaultCardinalityWindow = time.Minute
+
+// CardinalityLimits configures the attribute cardinality guard.
+type CardinalityLimits struct {
+	// MaxValues is the number of distinct values a key may take within Window before it is guarded.
+	MaxValues int
+	// Window is how long distinct values are tracked before counters reset. Defaults to one minute.
+	Window time.Duration
+	// Buckets maps guarded values onto "bucket-N" placeholders; zero replaces them with a short hash.
+	Buckets int
+	// Keys restricts the guard to these attribute keys; empty guards every span attribute.
+	Keys []string
+}
+
+// cardinalityGuard tracks distinct attribute values per key and, once a key exceeds its budget,
+// replaces that key's values with hashes or buckets for the rest of the window.
+type cardinalityGuard struct {
+	limits CardinalityLimits
+	keys   map[attribute.Key]bool
+	logger logx.Logger
+	now    func() time.Time
+
+	mu          sync.Mutex
+	windowStart time.Time
+	seen        map[attribute.Key]map[uint64]struct{}
+	guarded     map[attribute.Key]bool
+}
+
+func newCardinalityGuard(limits CardinalityLimits, logger logx.Logger) *cardinalityGuard {
+	if limits.Window <= 0 {
+		limits.Window = DefaultCardinalityWindow
+	}
+	g := &cardinalityGuard{
+		limits:  limits,
+		logger:  logger,
+		now:     time.Now,
+		seen:    map[attribute.Key]map[uint64]struct{}{},
+		guarded: map[attribute.Key]bool{},
+	}
+	if len(limits.Keys) > 0 {
+		g.keys = make(map[attribute.Key]bool, len(limits.Keys))
+		for _, k := range limits.Keys {
+			g.keys[attribute.Key(k)] = true
+		}
+	}
+	return g
+}
+
+func (g *cardinalityGuard) guard(span sdktrace.ReadOnlySpan) sdktrace.ReadOnlySpan {
+	attrs := span.Attributes()
+	var out []attribute.KeyValue
+
+	g.mu.Lock()
+	g.rotate()
+	for i, kv := range attrs {
+		if g.keys != nil && !g.keys[kv.Key] {
+			continue
+		}
+		sum := hashValue(kv.Value)
+		if !g.guarded[kv.Key] && !g.observe(kv.Key, sum) {
+			continue
+		}
+		if out == nil {
+			out = append(make([]attribute.KeyValue, 0, len(attrs)), attrs...)
+		}
+		out[i] = attribute.String(string(kv.Key), g.placeholder(sum))
+	}
+	g.mu.Unlock()
+
+	if out == nil {
+		return span
+	}
+	o := override(span)
+	o.setAttributes(out...)
+	return o
+}
+
+// observe records value sum for key and reports whether the key just crossed its budget.
+// Callers hold g.mu.
+func (g *cardinalityGuard) observe(key attribute.Key, sum uint64) bool {
+	values := g.seen[key]
+	if values == nil {
+		values = map[uint64]struct{}{}
+		g.seen[key] = values
+	}
+	values[sum] = struct{}{}
+	if len(values) <= g.limits.MaxValues {
+		return false
+	}
+	g.guarded[key] = true
+	delete(g.seen, key)
+	if g.logger != nil {
+		g.logger.Warn(context.Background(), "otelx.cardinality.guarded",
+			logx.String("key", string(key)),
+			logx.Int("maxValues", g.limits.MaxValues),
+			logx.String("window", g.limits.Window.String()),
+		)
+	}
+	return true
+}
+
+// rotate resets tracking once the window elapsed. Callers hold g.mu.
+func (g *cardinalityGuard) rotate() {
+	now := g.now()
+	if now.Sub(g.windowStart) < g.limits.Window {
+		return
+	}
+	g.windowStart = now
+	clear(g.seen)
+	clear(g.guarded)
+}
+
+func (g *cardinalityGuard) placeholder(sum uint64) string {
+	if g.limits.Buckets > 0 {
+		return fmt.Sprintf("bucket-%d", sum%uint64(g.limits.Buckets))
+	}
+	return fmt.Sprintf("hash-%08x", uint32(sum))
+}
+
+func hashValue(v attribute.Value) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(v.Emit()))
+	return h.Sum64()
+}
@@ -0,0 +1,77 @@
+package otelx
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestCardinalityGuardHashesKeyPastThreshold(t *testing.T) {
+	rec := &recordingLogger{}
+	guard := newCardinalityGuard(CardinalityLimits{MaxValues: 3, Keys: []string{"user.id"}}, rec)
+	now := time.Unix(100, 0)
+	guard.now = func() time.Time { return now }
+
+	mem := tracetest.NewInMemoryExporter()
+	exporter := newTransformExporter(mem, guard.guard)
+	var spans []sdktrace.ReadOnlySpan
+	for i := 0; i < 5; i++ {
+		spans = append(spans, spanWithAttributes(t,
+			attribute.String("user.id", fmt.Sprintf("u-%d", i)),
+			attribute.String("http.method", fmt.Sprintf("M%d", i)),
+		))
+	}
+	if err := exporter.ExportSpans(context.Background(), spans); err != nil {
+		t.Fatalf("export failed: %v", err)
+	}
+
+	got := mem.GetSpans()
+	if !spanHasAttribute(got[2].Attributes, "user.id", "u-2") {
+		t.Fatalf("expected values within budget to pass through, got %v", got[2].Attributes)
+	}
+	for _, span := range got[3:] {
+		value := attributeValue(span.Attributes, "user.id")
+		if !strings.HasPrefix(value, "hash-") {
+			t.Fatalf("expected guarded value to be hashed, got %q", value)
+		}
+	}
+	if !spanHasAttribute(got[4].Attributes, "http.method", "M4") {
+		t.Fatalf("expected keys outside Keys to be untouched")
+	}
+	if entries := rec.Entries(); len(entries) != 1 || entries[0] != "warn:otelx.cardinality.guarded" {
+		t.Fatalf("expected a single guard warning, got %v", entries)
+	}
+
+	now = now.Add(DefaultCardinalityWindow)
+	mem.Reset()
+	if err := exporter.ExportSpans(context.Background(), spans[:1]); err != nil {
+		t.Fatalf("export failed: %v", err)
+	}
+	if !spanHasAttribute(mem.GetSpans()[0].Attributes, "user.id", "u-0") {
+		t.Fatalf("expected guard to reset after the window")
+	}
+}
+
+func TestCardinalityGuardBuckets(t *testing.T) {
+	guard := newCardinalityGuard(CardinalityLimits{MaxValues: 1, Buckets: 4}, nil)
+	guard.guard(spanWithAttributes(t, attribute.String("k", "a")))
+	out := guard.guard(spanWithAttributes(t, attribute.String("k", "b")))
+	if value := attributeValue(out.Attributes(), "k"); !strings.HasPrefix(value, "bucket-") {
+		t.Fatalf("expected bucketed value, got %q", value)
+	}
+}
+
+func attributeValue(attrs []attribute.KeyValue, key attribute.Key) string {
+	for _, kv := range attrs {
+		if kv.Key == key {
+			return kv.Value.Emit()
+		}
+	}
+	return ""
+}
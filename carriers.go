@@ -0,0 +1,199 @@
+package otelx
+
+import (
+	"sort"
+
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// Carrier adapters for transports other than HTTP headers. Pass them to Provider.Propagator's
+// Inject and Extract, e.g. prov.Propagator.Inject(ctx, otelx.KafkaHeaders(&msg.Headers)).
+
+var (
+	_ propagation.TextMapCarrier = ValuesCarrier(nil)
+	_ propagation.TextMapCarrier = KafkaHeadersCarrier[kafkaHeader]{}
+	_ propagation.TextMapCarrier = AMQPHeadersCarrier[map[string]any]{}
+	_ propagation.TextMapCarrier = PubSubAttributesCarrier{}
+)
+
+// ValuesCarrier adapts a map[string][]string with case-sensitive keys, such as url.Values
+// (otelx.ValuesCarrier(r.URL.Query())) or gRPC-style metadata. The map must not be nil on Inject.
+// Use propagation.HeaderCarrier for http.Header, whose keys are canonicalised.
+type ValuesCarrier map[string][]string
+
+// Get returns the first value stored under key.
+func (c ValuesCarrier) Get(key string) string {
+	if values := c[key]; len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// Set replaces the values stored under key.
+func (c ValuesCarrier) Set(key, value string) {
+	c[key] = []string{value}
+}
+
+// Keys lists the stored keys in sorted order.
+func (c ValuesCarrier) Keys() []string {
+	return sortedKeys(c)
+}
+
+// kafkaHeader is the layout shared by the Kafka header types of segmentio/kafka-go and
+// confluent-kafka-go, so both work with KafkaHeaders without otelx importing either client.
+type kafkaHeader = struct {
+	Key   string
+	Value []byte
+}
+
+// KafkaHeadersCarrier adapts a Kafka message's header slice. Create it with KafkaHeaders.
+type KafkaHeadersCarrier[H ~struct {
+	Key   string
+	Value []byte
+}] struct {
+	headers *[]H
+}
+
+// KafkaHeaders returns a carrier over headers, typically &msg.Headers. Set replaces an existing
+// header with the same key instead of appending a duplicate.
+func KafkaHeaders[H ~struct {
+	Key   string
+	Value []byte
+}](headers *[]H) KafkaHeadersCarrier[H] {
+	return KafkaHeadersCarrier[H]{headers: headers}
+}
+
+// Get returns the value of the last header named key.
+func (c KafkaHeadersCarrier[H]) Get(key string) string {
+	if c.headers == nil {
+		return ""
+	}
+	headers := *c.headers
+	for i := len(headers) - 1; i >= 0; i-- {
+		if h := kafkaHeader(headers[i]); h.Key == key {
+			return string(h.Value)
+		}
+	}
+	return ""
+}
+
+// Set stores value under key.
+func (c KafkaHeadersCarrier[H]) Set(key, value string) {
+	if c.headers == nil {
+		return
+	}
+	header := H(kafkaHeader{Key: key, Value: []byte(value)})
+	for i, h := range *c.headers {
+		if kafkaHeader(h).Key == key {
+			(*c.headers)[i] = header
+			return
+		}
+	}
+	*c.headers = append(*c.headers, header)
+}
+
+// Keys lists the header keys in message order.
+func (c KafkaHeadersCarrier[H]) Keys() []string {
+	if c.headers == nil {
+		return nil
+	}
+	keys := make([]string, 0, len(*c.headers))
+	for _, h := range *c.headers {
+		keys = append(keys, kafkaHeader(h).Key)
+	}
+	return keys
+}
+
+// AMQPHeadersCarrier adapts an AMQP header table such as amqp091-go's amqp.Table. Create it with
+// AMQPHeaders.
+type AMQPHeadersCarrier[T ~map[string]any] struct {
+	table *T
+}
+
+// AMQPHeaders returns a carrier over table, typically &publishing.Headers or &delivery.Headers. A
+// nil table is allocated on the first Set.
+func AMQPHeaders[T ~map[string]any](table *T) AMQPHeadersCarrier[T] {
+	return AMQPHeadersCarrier[T]{table: table}
+}
+
+// Get returns the string or []byte value stored under key.
+func (c AMQPHeadersCarrier[T]) Get(key string) string {
+	if c.table == nil {
+		return ""
+	}
+	switch v := (*c.table)[key].(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	default:
+		return ""
+	}
+}
+
+// Set stores value under key as a string.
+func (c AMQPHeadersCarrier[T]) Set(key, value string) {
+	if c.table == nil {
+		return
+	}
+	if *c.table == nil {
+		*c.table = T{}
+	}
+	(*c.table)[key] = value
+}
+
+// Keys lists the table keys in sorted order.
+func (c AMQPHeadersCarrier[T]) Keys() []string {
+	if c.table == nil {
+		return nil
+	}
+	return sortedKeys(*c.table)
+}
+
+// PubSubAttributesCarrier adapts Google Cloud Pub/Sub message attributes. Create it with
+// PubSubAttributes.
+type PubSubAttributesCarrier struct {
+	attrs *map[string]string
+}
+
+// PubSubAttributes returns a carrier over attrs, typically &msg.Attributes. A nil map is allocated
+// on the first Set.
+func PubSubAttributes(attrs *map[string]string) PubSubAttributesCarrier {
+	return PubSubAttributesCarrier{attrs: attrs}
+}
+
+// Get returns the attribute stored under key.
+func (c PubSubAttributesCarrier) Get(key string) string {
+	if c.attrs == nil {
+		return ""
+	}
+	return (*c.attrs)[key]
+}
+
+// Set stores value under key.
+func (c PubSubAttributesCarrier) Set(key, value string) {
+	if c.attrs == nil {
+		return
+	}
+	if *c.attrs == nil {
+		*c.attrs = map[string]string{}
+	}
+	(*c.attrs)[key] = value
+}
+
+// Keys lists the attribute keys in sorted order.
+func (c PubSubAttributesCarrier) Keys() []string {
+	if c.attrs == nil {
+		return nil
+	}
+	return sortedKeys(*c.attrs)
+}
+
+func sortedKeys[M ~map[string]V, V any](m M) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,72 @@
+package otelx
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Stand-ins for the client library types the carriers are meant for.
+type testKafkaHeader struct {
+	Key   string
+	Value []byte
+}
+
+type testAMQPTable map[string]interface{}
+
+func TestCarriersRoundTripTraceContext(t *testing.T) {
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{1, 2, 3},
+		SpanID:     trace.SpanID{4, 5, 6},
+		TraceFlags: trace.FlagsSampled,
+	})
+	ctx := trace.ContextWithSpanContext(context.Background(), sc)
+	prop := propagation.TraceContext{}
+
+	query := url.Values{"page": {"2"}}
+	var kafka []testKafkaHeader
+	var amqp testAMQPTable
+	var pubsub map[string]string
+
+	carriers := map[string]propagation.TextMapCarrier{
+		"values": ValuesCarrier(query),
+		"kafka":  KafkaHeaders(&kafka),
+		"amqp":   AMQPHeaders(&amqp),
+		"pubsub": PubSubAttributes(&pubsub),
+	}
+	for name, carrier := range carriers {
+		prop.Inject(ctx, carrier)
+		got := trace.SpanContextFromContext(prop.Extract(context.Background(), carrier))
+		if got.TraceID() != sc.TraceID() || got.SpanID() != sc.SpanID() {
+			t.Errorf("%s: extracted %v, want %v", name, got, sc)
+		}
+	}
+
+	if query.Get("traceparent") == "" || query.Get("page") != "2" {
+		t.Fatalf("expected traceparent added to query, got %v", query)
+	}
+	prop.Inject(ctx, KafkaHeaders(&kafka))
+	if len(kafka) != 1 || kafka[0].Key != "traceparent" {
+		t.Fatalf("expected a single traceparent header after re-inject, got %+v", kafka)
+	}
+	if _, ok := amqp["traceparent"].(string); !ok {
+		t.Fatalf("expected traceparent in amqp table, got %v", amqp)
+	}
+	if pubsub["traceparent"] == "" {
+		t.Fatalf("expected traceparent in pubsub attributes, got %v", pubsub)
+	}
+}
+
+func TestAMQPHeadersReadsBytes(t *testing.T) {
+	table := testAMQPTable{"traceparent": []byte("value"), "count": 3}
+	carrier := AMQPHeaders(&table)
+	if got := carrier.Get("traceparent"); got != "value" {
+		t.Fatalf("expected []byte value to be read, got %q", got)
+	}
+	if got := carrier.Get("count"); got != "" {
+		t.Fatalf("expected non-string value to be ignored, got %q", got)
+	}
+}
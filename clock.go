@@ -0,0 +1,89 @@
+package otelx
+
+import (
+	"context"
+	"time"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// Clock supplies span timestamps, see WithClock.
+type Clock interface {
+	Now() time.Time
+}
+
+// ClockFunc adapts a function to Clock.
+type ClockFunc func() time.Time
+
+// Now implements Clock.
+func (f ClockFunc) Now() time.Time { return f() }
+
+// OffsetClock returns the system clock shifted by offset, for hosts with a known clock skew.
+func OffsetClock(offset time.Duration) Clock {
+	return ClockFunc(func() time.Time { return time.Now().Add(offset) })
+}
+
+// clockProcessor re-stamps spans with clock before handing them to the export pipelines it wraps.
+// The SDK always stamps spans with the system clock, so OnEnd shifts the start, end and event times
+// by the difference between clock and the system clock at that moment. Explicit timestamps passed
+// at start or end keep their relative meaning, and no per-span state is kept.
+type clockProcessor struct {
+	clock Clock
+	next  []sdktrace.SpanProcessor
+}
+
+func newClockProcessor(clock Clock, next []sdktrace.SpanProcessor) *clockProcessor {
+	return &clockProcessor{clock: clock, next: next}
+}
+
+func (p *clockProcessor) OnStart(ctx context.Context, s sdktrace.ReadWriteSpan) {
+	for _, next := range p.next {
+		next.OnStart(ctx, s)
+	}
+}
+
+func (p *clockProcessor) OnEnd(s sdktrace.ReadOnlySpan) {
+	span := shiftTimestamps(s, p.clock.Now().Sub(time.Now()))
+	for _, next := range p.next {
+		next.OnEnd(span)
+	}
+}
+
+func (p *clockProcessor) Shutdown(ctx context.Context) error {
+	var firstErr error
+	for _, next := range p.next {
+		if err := next.Shutdown(ctx); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func (p *clockProcessor) ForceFlush(ctx context.Context) error {
+	var firstErr error
+	for _, next := range p.next {
+		if err := next.ForceFlush(ctx); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// shiftTimestamps moves span's start, end and events by shift.
+func shiftTimestamps(span sdktrace.ReadOnlySpan, shift time.Duration) sdktrace.ReadOnlySpan {
+	if shift == 0 {
+		return span
+	}
+	o := override(span)
+	o.startTime = span.StartTime().Add(shift)
+	o.endTime = span.EndTime().Add(shift)
+	if events := span.Events(); len(events) > 0 {
+		shifted := make([]sdktrace.Event, len(events))
+		for i, e := range events {
+			e.Time = e.Time.Add(shift)
+			shifted[i] = e
+		}
+		o.events = shifted
+	}
+	return o
+}
@@ -0,0 +1,68 @@
+package otelx
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestSetupWithClockStampsSpans(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	clock := ClockFunc(func() time.Time { return now })
+
+	cfg := Config{ServiceName: "svc", Exporter: ExporterMemory, SamplingRatio: Float64(1)}
+	prov, err := Setup(context.Background(), cfg, noopLogger{}, WithClock(clock))
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	defer prov.Shutdown(context.Background())
+
+	_, span := prov.TP.Tracer("test").Start(context.Background(), "op")
+	span.AddEvent("checkpoint")
+	span.End()
+
+	spans := prov.RecordedSpans()
+	if len(spans) != 1 {
+		t.Fatalf("expected one span, got %d", len(spans))
+	}
+	got := spans[0]
+	if got.EndTime.After(now) || now.Sub(got.EndTime) > time.Second || got.StartTime.After(got.EndTime) {
+		t.Fatalf("expected clock timestamps, got %v - %v", got.StartTime, got.EndTime)
+	}
+	if len(got.Events) != 1 {
+		t.Fatalf("expected event to be kept, got %d", len(got.Events))
+	}
+	if offset := got.Events[0].Time.Sub(got.StartTime); offset < 0 || offset > time.Second {
+		t.Fatalf("expected event to keep its offset from the span start, got %v", offset)
+	}
+}
+
+func TestSetupWithClockKeepsExplicitTimestamps(t *testing.T) {
+	cfg := Config{ServiceName: "svc", Exporter: ExporterMemory, SamplingRatio: Float64(1)}
+	prov, err := Setup(context.Background(), cfg, noopLogger{}, WithClock(OffsetClock(-time.Hour)))
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	defer prov.Shutdown(context.Background())
+
+	start := time.Now().Add(-time.Minute)
+	_, span := prov.TP.Tracer("test").Start(context.Background(), "op", trace.WithTimestamp(start))
+	span.End(trace.WithTimestamp(start.Add(5 * time.Second)))
+
+	got := prov.RecordedSpans()[0]
+	if d := got.EndTime.Sub(got.StartTime); d != 5*time.Second {
+		t.Fatalf("expected the explicit 5s duration to be kept, got %v", d)
+	}
+	if skew := got.StartTime.Sub(start); skew > -59*time.Minute || skew < -61*time.Minute {
+		t.Fatalf("expected the explicit start shifted by the clock offset, got %v", skew)
+	}
+}
+
+func TestOffsetClock(t *testing.T) {
+	skew := OffsetClock(-time.Hour).Now().Sub(time.Now())
+	if skew > -59*time.Minute || skew < -61*time.Minute {
+		t.Fatalf("expected clock shifted by an hour, got %v", skew)
+	}
+}
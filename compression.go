@@ -0,0 +1,20 @@
+package otelx
+
+import "fmt"
+
+// Values accepted by Config.Compression.
+const (
+	// CompressionGzip gzips every OTLP request.
+	CompressionGzip = "gzip"
+	// CompressionNone sends OTLP requests uncompressed.
+	CompressionNone = "none"
+)
+
+func validateCompression(compression string) error {
+	switch compression {
+	case "", CompressionGzip, CompressionNone:
+		return nil
+	default:
+		return fmt.Errorf("unsupported compression %q", compression)
+	}
+}
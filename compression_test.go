@@ -0,0 +1,60 @@
+package otelx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestCompression(t *testing.T) {
+	encodings := make(chan string, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		encodings <- r.Header.Get("Content-Encoding")
+	}))
+	defer srv.Close()
+
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: trace.TraceID{1}, SpanID: trace.SpanID{1}, TraceFlags: trace.FlagsSampled,
+	})
+	spans := []sdktrace.ReadOnlySpan{tracetest.SpanStub{Name: "op", SpanContext: sc}.Snapshot()}
+	for compression, want := range map[string]string{CompressionGzip: "gzip", CompressionNone: ""} {
+		cfg := Config{Exporter: ExporterOTLPHTTP, Endpoint: strings.TrimPrefix(srv.URL, "http://"), Compression: compression}
+		exporter, err := buildExporter(context.Background(), cfg.primaryExporter(), nil)
+		if err != nil {
+			t.Fatalf("build exporter: %v", err)
+		}
+		if err := exporter.ExportSpans(context.Background(), spans); err != nil {
+			t.Fatalf("export: %v", err)
+		}
+		_ = exporter.Shutdown(context.Background())
+		if got := <-encodings; got != want {
+			t.Fatalf("%s: expected Content-Encoding %q, got %q", compression, want, got)
+		}
+	}
+}
+
+func TestCompressionValidation(t *testing.T) {
+	cases := map[string]Config{
+		"unknown":   {ServiceName: "svc", Exporter: ExporterOTLP, Compression: "zstd"},
+		"non-otlp":  {ServiceName: "svc", Exporter: ExporterStdout, Compression: CompressionGzip},
+		"exporters": {ServiceName: "svc", Exporters: []ExporterConfig{{Exporter: ExporterZipkin, Compression: CompressionGzip}}},
+	}
+	for name, cfg := range cases {
+		if _, err := Setup(context.Background(), cfg, nil); err == nil {
+			t.Fatalf("%s: expected validation error", name)
+		}
+	}
+
+	cfg := Config{ServiceName: "svc", Exporter: ExporterOTLP, Endpoint: "localhost:4317", Compression: " GZIP "}
+	prov, err := Setup(context.Background(), cfg, nil)
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	_ = prov.Shutdown(context.Background())
+}
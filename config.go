@@ -3,6 +3,7 @@ package otelx
 import (
 	"fmt"
 	"strings"
+	"time"
 )
 
 // ExporterType enumerates supported OpenTelemetry exporters.
@@ -11,25 +12,221 @@ type ExporterType string
 const (
 	ExporterStdout     ExporterType = "stdout"
 	ExporterOTLP       ExporterType = "otlp"
+	ExporterOTLPHTTP   ExporterType = "otlphttp"
 	ExporterCloudTrace ExporterType = "cloudtrace"
+	ExporterZipkin     ExporterType = "zipkin"
+	// ExporterMemory keeps spans in memory for tests; read them back with Provider.RecordedSpans.
+	ExporterMemory ExporterType = "memory"
+	// ExporterXRay sends segments straight to the AWS X-Ray API using the default AWS credential chain.
+	ExporterXRay ExporterType = "xray"
+	// ExporterAzureMonitor sends traces to Application Insights using AzureConnectionString.
+	ExporterAzureMonitor ExporterType = "azuremonitor"
+	// ExporterFile appends OTLP-JSON batches to FilePath, one request per line, for log agents to ship.
+	ExporterFile ExporterType = "file"
 )
 
 // DefaultSamplingRatio defines the fallback trace sampling ratio when none is provided.
 const DefaultSamplingRatio = 0.1
 
+// DefaultExporterTimeout bounds one export request of the otlp, otlphttp and cloudtrace exporters
+// when ExporterTimeout is unset.
+const DefaultExporterTimeout = 10 * time.Second
+
 // Config controls how otelx initializes tracing.
 type Config struct {
+	// ServiceName is required unless OTEL_SERVICE_NAME or service.name in OTEL_RESOURCE_ATTRIBUTES
+	// provides it.
 	ServiceName    string `json:"serviceName"`
 	ServiceVersion string `json:"serviceVersion"`
 	Environment    string `json:"environment"`
 
-	Exporter      ExporterType      `json:"exporter"`
-	SamplingRatio *float64          `json:"samplingRatio"`
-	Endpoint      string            `json:"endpoint"`
-	Insecure      bool              `json:"insecure"`
-	GCPProjectID  string            `json:"gcpProjectId"`
+	// Enabled=false makes Setup return a Provider whose spans are never recorded or exported, so
+	// tracing can be switched off per environment without branching at call sites. Nil means enabled.
+	Enabled *bool `json:"enabled"`
+
+	// Metrics also builds Provider.MP, a MeterProvider sharing the trace resource and exporting to
+	// the first otlp, otlphttp or stdout pipeline's endpoint and headers.
+	Metrics bool `json:"metrics"`
+	// MetricsInterval is the metrics export period (default 60s). MetricsEndpoint sends metrics to a
+	// different OTLP endpoint than traces while keeping their headers and TLS settings.
+	// MetricsTemporality is "cumulative" (default), "delta" or "lowmemory".
+	MetricsInterval    time.Duration `json:"metricsInterval"`
+	MetricsEndpoint    string        `json:"metricsEndpoint"`
+	MetricsTemporality string        `json:"metricsTemporality"`
+	// MetricsExemplars attaches the trace and span ids of sampled spans to measurements made in
+	// their context, so latency histogram buckets link to example traces. Nil keeps the SDK default
+	// (enabled, overridable with OTEL_METRICS_EXEMPLAR_FILTER); false turns exemplars off.
+	MetricsExemplars *bool `json:"metricsExemplars"`
+	// Logs also builds Provider.LP, a LoggerProvider sharing the trace resource and exporting to the
+	// same pipeline as Metrics.
+	Logs bool `json:"logs"`
+
+	Exporter ExporterType `json:"exporter"`
+	// Preset fills in a vendor's OTLP exporter, endpoint, TLS and auth header from APIKey:
+	// honeycomb, grafana-cloud, datadog-agent, signoz or newrelic. Explicit fields override it.
+	Preset string `json:"preset"`
+	APIKey string `json:"apiKey"`
+	// DebugTee additionally pretty-prints every exported span to stdout, after otelx's export-time
+	// transforms, to diagnose spans that never reach the backend.
+	DebugTee bool `json:"debugTee"`
+	// DryRun runs the full pipeline but discards spans at the exporter boundary,
+	// only counting what would have been sent (see Provider.DryRunStats).
+	DryRun        bool     `json:"dryRun"`
+	SamplingRatio *float64 `json:"samplingRatio"`
+	Endpoint      string   `json:"endpoint"`
+	URLPath       string   `json:"urlPath"`
+	Insecure      bool     `json:"insecure"`
+	GCPProjectID  string   `json:"gcpProjectId"`
+	// AWSRegion selects the X-Ray region for exporter=xray; empty uses the AWS default chain
+	// (AWS_REGION, shared config, ...).
+	AWSRegion string `json:"awsRegion"`
+	// AzureConnectionString is the Application Insights connection string for exporter=azuremonitor
+	// ("InstrumentationKey=...;IngestionEndpoint=https://...").
+	AzureConnectionString string `json:"azureConnectionString"`
+
+	// SamplingRules sample matching root spans at their own ratio, e.g. 0 for "GET /healthz" and
+	// 1 for "POST /checkout"; the first matching rule wins and other spans use SamplingRatio.
+	SamplingRules []SamplingRule `json:"samplingRules"`
+	// ControlPlane samples infrastructure traffic (health, metrics, debug, admin) at its own
+	// near-zero ratio; business traffic follows SamplingRules, SamplingRatio or RemoteSampling.
+	ControlPlane *ControlPlaneConfig `json:"controlPlane"`
+	// SamplingTargetPerMinute adapts the SamplingRatio sampler every 10s so that about this many
+	// spans are exported per minute; SamplingRatio is the starting point. See
+	// WithSamplingRatioObserver and SamplingRatioMetricName to follow the chosen ratio.
+	SamplingTargetPerMinute float64 `json:"samplingTargetPerMinute"`
+	// RemoteSampling fetches sampling strategies from a Jaeger-compatible endpoint and replaces
+	// SamplingRules and SamplingRatio once the first fetch succeeds.
+	RemoteSampling *RemoteSamplingConfig `json:"remoteSampling"`
+	// TailSampling exports only traces whose local root span is slow or that contain errors,
+	// deciding after the root ends.
+	TailSampling *TailSamplingConfig `json:"tailSampling"`
+
+	// MaxSpansPerRequest caps the child spans started under one local root span, e.g. a server
+	// request, protecting against span explosions in loops. Overflow spans are dropped and counted
+	// in one otelx.span_budget.exceeded event on the root. Zero means unlimited.
+	MaxSpansPerRequest int `json:"maxSpansPerRequest"`
+	// DropSpans discards ended spans matching any rule before they reach the batcher, e.g. noisy
+	// "GET /healthz" or "grpc.health.v1.Health/*" spans.
+	DropSpans []SpanDropRule `json:"dropSpans"`
+
+	// Batch tunes the span batchers' queue, batch size and timeouts; nil keeps the defaults
+	// (5s batch timeout, 512 spans per batch).
+	Batch *BatchConfig `json:"batch"`
+	// DiskBuffer writes batches that fail to export to disk and replays them when the exporter
+	// recovers, bounded by size and age.
+	DiskBuffer *DiskBufferConfig `json:"diskBuffer"`
+	// DeferredExport holds spans back and exports them in large chunks at an interval or at
+	// shutdown, for batch jobs that should not share their network with telemetry.
+	DeferredExport *DeferredExportConfig `json:"deferredExport"`
+
+	// FilePath, FileMaxBytes and FileMaxBackups configure exporter=file: the file is rotated to
+	// FilePath.1, FilePath.2, ... once it would exceed FileMaxBytes (default 100 MiB), keeping at
+	// most FileMaxBackups rotated files (default 5).
+	FilePath       string `json:"filePath"`
+	FileMaxBytes   int64  `json:"fileMaxBytes"`
+	FileMaxBackups int    `json:"fileMaxBackups"`
+
+	// ExportRatio forwards only this fraction of the sampled traces to the exporter, decided
+	// deterministically by trace id at export time. Nil exports everything. Useful per pipeline in
+	// Exporters, e.g. 100% to the internal collector and 5% to a SaaS backend.
+	ExportRatio *float64 `json:"exportRatio"`
+
 	Headers       map[string]string `json:"headers"`
 	ResourceAttrs map[string]string `json:"resourceAttrs"`
+
+	// OTLPRetry tunes the otlp/otlphttp exporters' retries of failed batches, e.g. disabled in
+	// tests or given a longer budget where the collector restarts often; nil keeps the defaults.
+	OTLPRetry *OTLPRetryConfig `json:"otlpRetry"`
+	// Compression is "gzip" or "none" for the otlp/otlphttp exporters, including the metrics and
+	// logs sharing their pipeline. Empty keeps the exporter default (uncompressed).
+	Compression string `json:"compression"`
+	// TLS verifies the otlp/otlphttp collector with a private CA and presents a client certificate
+	// for mutual TLS; mutually exclusive with Insecure.
+	TLS *TLSConfig `json:"tls"`
+	// Failover switches the otlp/otlphttp exporter to a secondary endpoint after repeated export
+	// failures and back once the primary recovers. Metrics and logs keep using the primary.
+	Failover *FailoverConfig `json:"failover"`
+	// ExporterTimeout bounds one export request of the otlp, otlphttp and cloudtrace exporters
+	// (default DefaultExporterTimeout), so a slow backend cannot stall flushes and shutdown.
+	ExporterTimeout time.Duration `json:"exporterTimeout"`
+	// httpsEndpoint records that Endpoint was an https URL, which keeps TLS on for loopback hosts.
+	httpsEndpoint bool
+
+	// SpanKindAttributes adds default attributes to every span of a kind (internal, server, client,
+	// producer, consumer) when it starts, e.g. {"client": {"net.transport": "ip_tcp"}}. Attributes
+	// passed when starting the span take precedence.
+	SpanKindAttributes map[string]map[string]string `json:"spanKindAttributes"`
+
+	// SDKLogLevel routes the OpenTelemetry SDK's internal logs (error|warn|info|debug) through the
+	// logx.Logger passed to Setup. Empty keeps the SDK default of printing errors to stderr.
+	SDKLogLevel string `json:"sdkLogLevel"`
+
+	// SpanLimits caps the attributes, events and links each span keeps and the length of
+	// attribute values; nil keeps the SDK defaults.
+	SpanLimits *SpanLimitsConfig `json:"spanLimits"`
+
+	// BaggageMaxMembers and BaggageMaxBytes cap the W3C Baggage header emitted by the default
+	// propagator. Zero selects the W3C limits (64 members, 8192 bytes).
+	BaggageMaxMembers int `json:"baggageMaxMembers"`
+	BaggageMaxBytes   int `json:"baggageMaxBytes"`
+
+	// SpanNameRules rewrite span names when spans start, in order and before
+	// WithSpanNameNormalizers, e.g. pattern `/users/[0-9a-f-]{36}` with replacement "/users/{id}".
+	SpanNameRules []SpanNameRule `json:"spanNameRules"`
+
+	// RedactionRules replace or hash the parts of string attribute values matching a pattern
+	// (emails, tokens, card numbers, ...) on every span before export.
+	RedactionRules []RedactionRule `json:"redactionRules"`
+
+	// ScrubPII masks well-known sensitive semantic-convention attributes on every span, whichever
+	// instrumentation set them: enduser.id is hashed, credential headers replaced, db statement
+	// literals turned into "?" and URL userinfo removed. Applied before RedactionRules.
+	ScrubPII bool `json:"scrubPII"`
+
+	// Truncation cuts long string attribute values before export and marks them; see
+	// WithAttributeTruncation.
+	Truncation *TruncationConfig `json:"truncation"`
+
+	// SchemaValidation checks spans against AttributeSchemas (plus schemas registered with
+	// WithAttributeSchemas) when they end: "log" reports violations, "fail" also panics. Meant for
+	// dev and test environments.
+	SchemaValidation SchemaMode        `json:"schemaValidation"`
+	AttributeSchemas []AttributeSchema `json:"attributeSchemas"`
+
+	// Environments overrides exporter, endpoint and sampling settings for the environment named by
+	// Environment, e.g. {"prod": {"exporter": "otlp", "samplingRatio": 0.05}}. Other entries are
+	// ignored.
+	Environments map[string]EnvironmentOverride `json:"environments"`
+
+	// Exporters fans spans out to several backends at once, each with its own batcher
+	// (e.g. OTLP + Cloud Trace during a migration). It replaces the single-exporter fields
+	// above, which must be left unset when Exporters is used.
+	Exporters []ExporterConfig `json:"exporters"`
+}
+
+// ExporterConfig describes one export pipeline in Config.Exporters. Fields mirror their
+// single-exporter counterparts on Config.
+type ExporterConfig struct {
+	Exporter              ExporterType      `json:"exporter"`
+	Preset                string            `json:"preset"`
+	APIKey                string            `json:"apiKey"`
+	Endpoint              string            `json:"endpoint"`
+	URLPath               string            `json:"urlPath"`
+	Insecure              bool              `json:"insecure"`
+	GCPProjectID          string            `json:"gcpProjectId"`
+	AWSRegion             string            `json:"awsRegion"`
+	AzureConnectionString string            `json:"azureConnectionString"`
+	FilePath              string            `json:"filePath"`
+	FileMaxBytes          int64             `json:"fileMaxBytes"`
+	FileMaxBackups        int               `json:"fileMaxBackups"`
+	ExportRatio           *float64          `json:"exportRatio"`
+	Headers               map[string]string `json:"headers"`
+	OTLPRetry             *OTLPRetryConfig  `json:"otlpRetry"`
+	Compression           string            `json:"compression"`
+	TLS                   *TLSConfig        `json:"tls"`
+	Failover              *FailoverConfig   `json:"failover"`
+	ExporterTimeout       time.Duration     `json:"exporterTimeout"`
+	httpsEndpoint         bool
 }
 
 // sanitize trims spaces from string fields and normalises exporter value.
@@ -37,39 +234,400 @@ func (cfg Config) sanitize() Config {
 	cfg.ServiceName = strings.TrimSpace(cfg.ServiceName)
 	cfg.ServiceVersion = strings.TrimSpace(cfg.ServiceVersion)
 	cfg.Environment = strings.TrimSpace(cfg.Environment)
+	cfg = cfg.resolveEnvironment()
 	cfg.Endpoint = strings.TrimSpace(cfg.Endpoint)
+	cfg.URLPath = strings.TrimSpace(cfg.URLPath)
 	cfg.GCPProjectID = strings.TrimSpace(cfg.GCPProjectID)
+	cfg.AWSRegion = strings.TrimSpace(cfg.AWSRegion)
+	cfg.AzureConnectionString = strings.TrimSpace(cfg.AzureConnectionString)
+	cfg.FilePath = strings.TrimSpace(cfg.FilePath)
 	cfg.Exporter = ExporterType(strings.ToLower(string(cfg.Exporter)))
+	cfg.Preset = strings.ToLower(strings.TrimSpace(cfg.Preset))
+	cfg.APIKey = strings.TrimSpace(cfg.APIKey)
+	cfg.Compression = strings.ToLower(strings.TrimSpace(cfg.Compression))
+	if cfg.TLS != nil {
+		tlsCfg := cfg.TLS.sanitize()
+		cfg.TLS = &tlsCfg
+	}
+	if cfg.Preset != "" {
+		ec := cfg.primaryExporter().applyPreset()
+		cfg.Exporter, cfg.Endpoint, cfg.URLPath, cfg.Insecure, cfg.Headers = ec.Exporter, ec.Endpoint, ec.URLPath, ec.Insecure, ec.Headers
+	}
+	if ec := cfg.primaryExporter().resolveEndpointURL(); ec.Endpoint != cfg.Endpoint {
+		cfg.Exporter, cfg.Endpoint, cfg.URLPath, cfg.Insecure = ec.Exporter, ec.Endpoint, ec.URLPath, ec.Insecure
+		cfg.httpsEndpoint = ec.httpsEndpoint
+	}
+	cfg.MetricsEndpoint = strings.TrimSpace(cfg.MetricsEndpoint)
+	cfg.MetricsTemporality = strings.ToLower(strings.TrimSpace(cfg.MetricsTemporality))
+	cfg.SDKLogLevel = strings.ToLower(strings.TrimSpace(cfg.SDKLogLevel))
+	cfg.SchemaValidation = SchemaMode(strings.ToLower(strings.TrimSpace(string(cfg.SchemaValidation))))
+	if len(cfg.Exporters) > 0 {
+		exporters := make([]ExporterConfig, len(cfg.Exporters))
+		for i, ec := range cfg.Exporters {
+			exporters[i] = ec.sanitize()
+		}
+		cfg.Exporters = exporters
+	}
 	return cfg
 }
 
 // validate performs semantic validation of the config.
 func (cfg Config) validate() error {
 	if cfg.ServiceName == "" {
-		return fmt.Errorf("otelx: serviceName is required")
+		return fmt.Errorf("otelx: serviceName is required (or set OTEL_SERVICE_NAME)")
+	}
+
+	if cfg.SamplingRatio != nil {
+		if ratio := *cfg.SamplingRatio; ratio < 0 || ratio > 1 {
+			return fmt.Errorf("otelx: samplingRatio must be within [0,1], got %v", ratio)
+		}
+	}
+
+	for i, rule := range cfg.SamplingRules {
+		if err := rule.validate(); err != nil {
+			return fmt.Errorf("otelx: samplingRules[%d]: %w", i, err)
+		}
+	}
+
+	if cfg.ControlPlane != nil {
+		if err := cfg.ControlPlane.validate(); err != nil {
+			return fmt.Errorf("otelx: controlPlane: %w", err)
+		}
+	}
+
+	if cfg.SamplingTargetPerMinute < 0 {
+		return fmt.Errorf("otelx: samplingTargetPerMinute must not be negative")
+	}
+	if cfg.SamplingTargetPerMinute > 0 && cfg.RemoteSampling != nil {
+		return fmt.Errorf("otelx: samplingTargetPerMinute and remoteSampling are mutually exclusive")
+	}
+
+	if cfg.RemoteSampling != nil {
+		if err := cfg.RemoteSampling.validate(); err != nil {
+			return fmt.Errorf("otelx: remoteSampling: %w", err)
+		}
+	}
+
+	if cfg.MaxSpansPerRequest < 0 {
+		return fmt.Errorf("otelx: maxSpansPerRequest must not be negative")
+	}
+
+	if cfg.TailSampling != nil {
+		if err := cfg.TailSampling.validate(); err != nil {
+			return fmt.Errorf("otelx: tailSampling: %w", err)
+		}
+	}
+
+	if cfg.Batch != nil {
+		if err := cfg.Batch.validate(); err != nil {
+			return fmt.Errorf("otelx: batch: %w", err)
+		}
+		if cfg.DeferredExport != nil {
+			return fmt.Errorf("otelx: batch and deferredExport are mutually exclusive")
+		}
+	}
+
+	if cfg.DiskBuffer != nil {
+		if err := cfg.DiskBuffer.validate(); err != nil {
+			return fmt.Errorf("otelx: diskBuffer: %w", err)
+		}
+	}
+
+	if cfg.DeferredExport != nil {
+		if err := cfg.DeferredExport.validate(); err != nil {
+			return fmt.Errorf("otelx: deferredExport: %w", err)
+		}
+	}
+
+	for i, rule := range cfg.DropSpans {
+		if err := rule.validate(); err != nil {
+			return fmt.Errorf("otelx: dropSpans[%d]: %w", i, err)
+		}
+	}
+
+	for i, rule := range cfg.SpanNameRules {
+		if err := rule.validate(); err != nil {
+			return fmt.Errorf("otelx: spanNameRules[%d]: %w", i, err)
+		}
+	}
+
+	if cfg.Truncation != nil {
+		if err := cfg.Truncation.validate(); err != nil {
+			return fmt.Errorf("otelx: truncation: %w", err)
+		}
+	}
+
+	for i, rule := range cfg.RedactionRules {
+		if err := rule.validate(); err != nil {
+			return fmt.Errorf("otelx: redactionRules[%d]: %w", i, err)
+		}
+	}
+
+	if cfg.SDKLogLevel != "" && !validSDKLogLevel(cfg.SDKLogLevel) {
+		return fmt.Errorf("otelx: unsupported sdkLogLevel %q", cfg.SDKLogLevel)
+	}
+
+	if cfg.BaggageMaxMembers < 0 || cfg.BaggageMaxBytes < 0 {
+		return fmt.Errorf("otelx: baggage limits must not be negative")
+	}
+
+	if err := cfg.validateMetrics(); err != nil {
+		return fmt.Errorf("otelx: %w", err)
+	}
+
+	if _, err := parseSpanKindAttributes(cfg.SpanKindAttributes); err != nil {
+		return fmt.Errorf("otelx: spanKindAttributes: %w", err)
+	}
+
+	switch cfg.SchemaValidation {
+	case SchemaOff, SchemaLog, SchemaFail:
+	default:
+		return fmt.Errorf("otelx: unsupported schemaValidation %q", cfg.SchemaValidation)
+	}
+	for i, schema := range cfg.AttributeSchemas {
+		if err := schema.validate(); err != nil {
+			return fmt.Errorf("otelx: attributeSchemas[%d]: %w", i, err)
+		}
+	}
+
+	if len(cfg.Exporters) == 0 {
+		if err := cfg.primaryExporter().validate(); err != nil {
+			return fmt.Errorf("otelx: %w", err)
+		}
+		return nil
+	}
+
+	if cfg.primaryExporter().isSet() {
+		return fmt.Errorf("otelx: exporter settings and exporters are mutually exclusive")
+	}
+	for i, ec := range cfg.Exporters {
+		if err := ec.validate(); err != nil {
+			return fmt.Errorf("otelx: exporters[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// exporterConfigs returns the export pipelines described by cfg: the Exporters list when set,
+// otherwise the single exporter configured by the top-level fields.
+func (cfg Config) exporterConfigs() []ExporterConfig {
+	if len(cfg.Exporters) > 0 {
+		return cfg.Exporters
+	}
+	return []ExporterConfig{cfg.primaryExporter()}
+}
+
+// usesExporter reports whether any configured pipeline exports with exporter.
+func (cfg Config) usesExporter(exporter ExporterType) bool {
+	for _, ec := range cfg.exporterConfigs() {
+		if ec.Exporter == exporter {
+			return true
+		}
+	}
+	return false
+}
+
+// primaryExporter collects the top-level exporter fields into an ExporterConfig.
+func (cfg Config) primaryExporter() ExporterConfig {
+	return ExporterConfig{
+		Exporter:              cfg.Exporter,
+		Preset:                cfg.Preset,
+		APIKey:                cfg.APIKey,
+		Endpoint:              cfg.Endpoint,
+		URLPath:               cfg.URLPath,
+		Insecure:              cfg.Insecure,
+		GCPProjectID:          cfg.GCPProjectID,
+		AWSRegion:             cfg.AWSRegion,
+		AzureConnectionString: cfg.AzureConnectionString,
+		FilePath:              cfg.FilePath,
+		FileMaxBytes:          cfg.FileMaxBytes,
+		FileMaxBackups:        cfg.FileMaxBackups,
+		ExportRatio:           cfg.ExportRatio,
+		Headers:               cfg.Headers,
+		OTLPRetry:             cfg.OTLPRetry,
+		Compression:           cfg.Compression,
+		TLS:                   cfg.TLS,
+		Failover:              cfg.Failover,
+		ExporterTimeout:       cfg.ExporterTimeout,
+		httpsEndpoint:         cfg.httpsEndpoint,
 	}
+}
+
+// sanitize trims spaces from string fields and normalises exporter value.
+func (ec ExporterConfig) sanitize() ExporterConfig {
+	ec.Endpoint = strings.TrimSpace(ec.Endpoint)
+	ec.URLPath = strings.TrimSpace(ec.URLPath)
+	ec.GCPProjectID = strings.TrimSpace(ec.GCPProjectID)
+	ec.AWSRegion = strings.TrimSpace(ec.AWSRegion)
+	ec.AzureConnectionString = strings.TrimSpace(ec.AzureConnectionString)
+	ec.FilePath = strings.TrimSpace(ec.FilePath)
+	ec.Exporter = ExporterType(strings.ToLower(string(ec.Exporter)))
+	ec.Preset = strings.ToLower(strings.TrimSpace(ec.Preset))
+	ec.APIKey = strings.TrimSpace(ec.APIKey)
+	ec.Compression = strings.ToLower(strings.TrimSpace(ec.Compression))
+	if ec.TLS != nil {
+		tlsCfg := ec.TLS.sanitize()
+		ec.TLS = &tlsCfg
+	}
+	return ec.applyPreset().resolveEndpointURL()
+}
 
-	switch cfg.Exporter {
-	case "", ExporterStdout, ExporterOTLP, ExporterCloudTrace:
+// remote reports whether ec exports over the network, as opposed to stdout, a file or memory.
+func (ec ExporterConfig) remote() bool {
+	switch ec.Exporter {
+	case "", ExporterStdout, ExporterFile, ExporterMemory:
+		return false
+	}
+	return true
+}
+
+// isSet reports whether any exporter setting deviates from the zero value.
+func (ec ExporterConfig) isSet() bool {
+	return ec.Exporter != "" || ec.Preset != "" || ec.APIKey != "" || ec.Endpoint != "" ||
+		ec.URLPath != "" || ec.Insecure ||
+		ec.GCPProjectID != "" || ec.AWSRegion != "" || ec.AzureConnectionString != "" ||
+		ec.FilePath != "" || ec.FileMaxBytes != 0 || ec.FileMaxBackups != 0 || ec.ExportRatio != nil || len(ec.Headers) > 0 ||
+		ec.OTLPRetry != nil || ec.Compression != "" || ec.TLS != nil || ec.Failover != nil ||
+		ec.ExporterTimeout != 0
+}
+
+// validate performs semantic validation of a single exporter pipeline.
+func (ec ExporterConfig) validate() error {
+	if err := ec.validatePreset(); err != nil {
+		return err
+	}
+
+	switch ec.Exporter {
+	case "", ExporterStdout, ExporterOTLP, ExporterOTLPHTTP, ExporterCloudTrace, ExporterZipkin, ExporterMemory, ExporterFile, ExporterXRay, ExporterAzureMonitor:
 		// ok
+	case "jaeger":
+		return fmt.Errorf("exporter=jaeger is not supported: Jaeger ingests OTLP, use exporter=otlp (port 4317) or otlphttp (port 4318)")
 	default:
-		return fmt.Errorf("otelx: unsupported exporter %q", cfg.Exporter)
+		return fmt.Errorf("unsupported exporter %q", ec.Exporter)
 	}
 
-	if cfg.SamplingRatio != nil {
-		if ratio := *cfg.SamplingRatio; ratio < 0 || ratio > 1 {
-			return fmt.Errorf("otelx: samplingRatio must be within [0,1], got %v", ratio)
+	if err := ec.validateEndpoint(); err != nil {
+		return err
+	}
+
+	if err := validateHeaderRefs(ec.Headers); err != nil {
+		return err
+	}
+
+	if ec.URLPath != "" && ec.Exporter != ExporterOTLPHTTP {
+		return fmt.Errorf("urlPath is only supported when exporter=otlphttp")
+	}
+
+	if ec.Exporter == ExporterFile {
+		if ec.FilePath == "" {
+			return fmt.Errorf("filePath is required when exporter=file")
+		}
+		if ec.FileMaxBytes < 0 || ec.FileMaxBackups < 0 {
+			return fmt.Errorf("fileMaxBytes/fileMaxBackups must not be negative")
+		}
+	} else if ec.FilePath != "" || ec.FileMaxBytes != 0 || ec.FileMaxBackups != 0 {
+		return fmt.Errorf("filePath/fileMaxBytes/fileMaxBackups are only supported when exporter=file")
+	}
+
+	if ec.ExportRatio != nil {
+		if ratio := *ec.ExportRatio; ratio < 0 || ratio > 1 {
+			return fmt.Errorf("exportRatio must be within [0,1], got %v", ratio)
 		}
 	}
 
-	if cfg.Exporter == ExporterCloudTrace && cfg.GCPProjectID == "" {
-		return fmt.Errorf("otelx: gcpProjectId is required when exporter=cloudtrace")
+	if ec.OTLPRetry != nil {
+		if ec.Exporter != ExporterOTLP && ec.Exporter != ExporterOTLPHTTP {
+			return fmt.Errorf("otlpRetry is only supported when exporter=otlp or exporter=otlphttp")
+		}
+		if err := ec.OTLPRetry.validate(); err != nil {
+			return fmt.Errorf("otlpRetry: %w", err)
+		}
+	}
+
+	if ec.Compression != "" {
+		if ec.Exporter != ExporterOTLP && ec.Exporter != ExporterOTLPHTTP {
+			return fmt.Errorf("compression is only supported when exporter=otlp or exporter=otlphttp")
+		}
+		if err := validateCompression(ec.Compression); err != nil {
+			return err
+		}
+	}
+
+	if _, ok := unixSocketPath(ec.Endpoint); ok {
+		if ec.Exporter != ExporterOTLP && ec.Exporter != ExporterOTLPHTTP {
+			return fmt.Errorf("unix socket endpoints are only supported when exporter=otlp or exporter=otlphttp")
+		}
+		if ec.TLS != nil {
+			return fmt.Errorf("tls is not supported with unix socket endpoints")
+		}
+	}
+
+	if ec.TLS != nil {
+		if ec.Exporter != ExporterOTLP && ec.Exporter != ExporterOTLPHTTP {
+			return fmt.Errorf("tls is only supported when exporter=otlp or exporter=otlphttp")
+		}
+		if ec.Insecure {
+			return fmt.Errorf("tls and insecure are mutually exclusive")
+		}
+		if err := ec.TLS.validate(); err != nil {
+			return fmt.Errorf("tls: %w", err)
+		}
+	}
+
+	if ec.Failover != nil {
+		if ec.Exporter != ExporterOTLP && ec.Exporter != ExporterOTLPHTTP {
+			return fmt.Errorf("failover is only supported when exporter=otlp or exporter=otlphttp")
+		}
+		if err := ec.Failover.validate(); err != nil {
+			return fmt.Errorf("failover: %w", err)
+		}
+		if strings.TrimSpace(ec.Failover.Endpoint) == ec.Endpoint {
+			return fmt.Errorf("failover: endpoint must differ from the primary endpoint")
+		}
+	}
+
+	if ec.ExporterTimeout != 0 {
+		if ec.Exporter != ExporterOTLP && ec.Exporter != ExporterOTLPHTTP && ec.Exporter != ExporterCloudTrace {
+			return fmt.Errorf("exporterTimeout is only supported when exporter=otlp, otlphttp or cloudtrace")
+		}
+		if ec.ExporterTimeout < 0 {
+			return fmt.Errorf("exporterTimeout must not be negative")
+		}
+	}
+
+	if ec.AWSRegion != "" && ec.Exporter != ExporterXRay {
+		return fmt.Errorf("awsRegion is only supported when exporter=xray")
+	}
+
+	if ec.Exporter == ExporterAzureMonitor {
+		if _, err := parseAzureConnectionString(ec.AzureConnectionString); err != nil {
+			return fmt.Errorf("azureConnectionString is required when exporter=azuremonitor: %w", err)
+		}
+	} else if ec.AzureConnectionString != "" {
+		return fmt.Errorf("azureConnectionString is only supported when exporter=azuremonitor")
+	}
+
+	if ec.Exporter == ExporterCloudTrace && ec.GCPProjectID == "" {
+		return fmt.Errorf("gcpProjectId is required when exporter=cloudtrace")
 	}
 
 	return nil
 }
 
+// timeout returns the export request timeout of the pipeline.
+func (ec ExporterConfig) timeout() time.Duration {
+	if ec.ExporterTimeout > 0 {
+		return ec.ExporterTimeout
+	}
+	return DefaultExporterTimeout
+}
+
 // Float64 is a helper that returns a pointer to the provided float64.
 func Float64(v float64) *float64 {
 	return &v
 }
+
+// Bool is a helper that returns a pointer to the provided bool.
+func Bool(v bool) *bool {
+	return &v
+}
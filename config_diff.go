@@ -0,0 +1,191 @@
+package otelx
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	logx "github.com/bionicotaku/lingo-utils-logx"
+)
+
+// redactedValue replaces secret values in config diffs.
+const redactedValue = "[REDACTED]"
+
+// secretConfigFields lists Config fields (by JSON name) whose values must never be logged.
+var secretConfigFields = map[string]bool{
+	"headers":               true,
+	"azureConnectionString": true,
+	"apiKey":                true,
+}
+
+// liveConfigFields lists Config fields (by JSON name) that only affect the sampler or propagator
+// and can be applied without recreating the exporter pipeline.
+var liveConfigFields = map[string]bool{
+	"samplingRatio":     true,
+	"baggageMaxMembers": true,
+	"baggageMaxBytes":   true,
+}
+
+// ConfigChange describes one telemetry setting that differs between two configs.
+type ConfigChange struct {
+	// Field is the JSON name of the setting; map entries use "field.key".
+	Field string `json:"field"`
+	Old   string `json:"old"`
+	New   string `json:"new"`
+	// Rebuild reports whether applying the change requires recreating the exporter pipeline
+	// rather than updating the running sampler/propagator.
+	Rebuild bool `json:"rebuild"`
+}
+
+// DiffConfig returns the settings that differ between old and updated, sorted by field.
+// Secret values (headers, API keys, connection strings) are redacted.
+func DiffConfig(old, updated Config) []ConfigChange {
+	before := configFields(redactExporterSecrets(old.sanitize()))
+	after := configFields(redactExporterSecrets(updated.sanitize()))
+
+	var changes []ConfigChange
+	for _, field := range unionKeys(before, after) {
+		oldVal, newVal := before[field], after[field]
+		if reflect.DeepEqual(oldVal, newVal) {
+			continue
+		}
+		oldMap, oldIsMap := oldVal.(map[string]any)
+		newMap, newIsMap := newVal.(map[string]any)
+		if (oldIsMap || oldVal == nil) && (newIsMap || newVal == nil) {
+			for _, key := range unionKeys(oldMap, newMap) {
+				if reflect.DeepEqual(oldMap[key], newMap[key]) {
+					continue
+				}
+				changes = append(changes, newConfigChange(field, field+"."+key, oldMap[key], newMap[key]))
+			}
+			continue
+		}
+		changes = append(changes, newConfigChange(field, field, oldVal, newVal))
+	}
+	return changes
+}
+
+// LogConfigDiff logs the difference between old and updated as a single structured
+// otelx.config.changed entry and returns the changes. Nothing is logged when the configs match.
+func LogConfigDiff(ctx context.Context, logger logx.Logger, old, updated Config) []ConfigChange {
+	changes := DiffConfig(old, updated)
+	if logger == nil || len(changes) == 0 {
+		return changes
+	}
+	rebuild := false
+	for _, c := range changes {
+		rebuild = rebuild || c.Rebuild
+	}
+	logger.Info(ctx, "otelx.config.changed",
+		logx.Any("changes", changes),
+		logx.Any("rebuild", rebuild),
+	)
+	return changes
+}
+
+func newConfigChange(field, name string, oldVal, newVal any) ConfigChange {
+	change := ConfigChange{
+		Field:   name,
+		Old:     formatConfigValue(oldVal),
+		New:     formatConfigValue(newVal),
+		Rebuild: !liveConfigFields[field],
+	}
+	if secretConfigFields[field] {
+		if change.Old != "" {
+			change.Old = redactedValue
+		}
+		if change.New != "" {
+			change.New = redactedValue
+		}
+	}
+	return change
+}
+
+// redactExporterSecrets replaces header values, API keys, connection strings and TLS private keys
+// inside cfg.Exporters, and the top-level TLS private key, with a short fingerprint, so changes
+// remain detectable without the secrets reaching the diff.
+func redactExporterSecrets(cfg Config) Config {
+	cfg.TLS = redactTLSKey(cfg.TLS)
+	if len(cfg.Exporters) == 0 {
+		return cfg
+	}
+	exporters := make([]ExporterConfig, len(cfg.Exporters))
+	for i, ec := range cfg.Exporters {
+		if len(ec.Headers) > 0 {
+			headers := make(map[string]string, len(ec.Headers))
+			for k, v := range ec.Headers {
+				headers[k] = secretFingerprint(v)
+			}
+			ec.Headers = headers
+		}
+		if ec.APIKey != "" {
+			ec.APIKey = secretFingerprint(ec.APIKey)
+		}
+		if ec.AzureConnectionString != "" {
+			ec.AzureConnectionString = secretFingerprint(ec.AzureConnectionString)
+		}
+		ec.TLS = redactTLSKey(ec.TLS)
+		exporters[i] = ec
+	}
+	cfg.Exporters = exporters
+	return cfg
+}
+
+func redactTLSKey(c *TLSConfig) *TLSConfig {
+	if c == nil || c.KeyPEM == "" {
+		return c
+	}
+	redacted := *c
+	redacted.KeyPEM = secretFingerprint(c.KeyPEM)
+	return &redacted
+}
+
+func secretFingerprint(v string) string {
+	sum := sha256.Sum256([]byte(v))
+	return fmt.Sprintf("%s:%x", redactedValue, sum[:4])
+}
+
+// configFields flattens cfg into its JSON representation keyed by field name.
+func configFields(cfg Config) map[string]any {
+	raw, err := json.Marshal(cfg)
+	if err != nil {
+		return nil
+	}
+	fields := map[string]any{}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil
+	}
+	return fields
+}
+
+func formatConfigValue(v any) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return val
+	default:
+		raw, err := json.Marshal(val)
+		if err != nil {
+			return ""
+		}
+		return string(raw)
+	}
+}
+
+func unionKeys(a, b map[string]any) []string {
+	keys := make([]string, 0, len(a)+len(b))
+	for k := range a {
+		keys = append(keys, k)
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
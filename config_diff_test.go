@@ -0,0 +1,66 @@
+package otelx
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDiffConfig(t *testing.T) {
+	old := Config{
+		ServiceName:   "svc",
+		Exporter:      ExporterOTLP,
+		Endpoint:      "collector:4317",
+		SamplingRatio: Float64(0.1),
+		Headers:       map[string]string{"x-api-key": "old-secret", "x-team": "payments"},
+	}
+	updated := old
+	updated.Endpoint = "collector-2:4317"
+	updated.SamplingRatio = Float64(0.5)
+	updated.Headers = map[string]string{"x-api-key": "new-secret", "x-team": "payments"}
+
+	got := DiffConfig(old, updated)
+	want := []ConfigChange{
+		{Field: "endpoint", Old: "collector:4317", New: "collector-2:4317", Rebuild: true},
+		{Field: "headers.x-api-key", Old: redactedValue, New: redactedValue, Rebuild: true},
+		{Field: "samplingRatio", Old: "0.1", New: "0.5", Rebuild: false},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected diff:\n got %+v\nwant %+v", got, want)
+	}
+}
+
+func TestDiffConfigIgnoresWhitespaceOnlyChanges(t *testing.T) {
+	if changes := DiffConfig(Config{ServiceName: "svc"}, Config{ServiceName: " svc "}); len(changes) != 0 {
+		t.Fatalf("expected no changes, got %+v", changes)
+	}
+}
+
+func TestDiffConfigRedactsExporterHeaders(t *testing.T) {
+	old := Config{ServiceName: "svc", Exporters: []ExporterConfig{
+		{Exporter: ExporterOTLP, Headers: map[string]string{"x-api-key": "old-secret"}},
+	}}
+	updated := Config{ServiceName: "svc", Exporters: []ExporterConfig{
+		{Exporter: ExporterOTLP, Headers: map[string]string{"x-api-key": "new-secret"}},
+	}}
+
+	changes := DiffConfig(old, updated)
+	if len(changes) != 1 || changes[0].Field != "exporters" {
+		t.Fatalf("expected exporters change, got %+v", changes)
+	}
+	for _, v := range []string{changes[0].Old, changes[0].New} {
+		if strings.Contains(v, "secret") || !strings.Contains(v, redactedValue) {
+			t.Fatalf("expected header values to be redacted, got %s", v)
+		}
+	}
+}
+
+func TestDiffConfigRedactsAzureConnectionString(t *testing.T) {
+	old := Config{ServiceName: "svc", Exporter: ExporterAzureMonitor, AzureConnectionString: "InstrumentationKey=old-key"}
+	updated := Config{ServiceName: "svc", Exporter: ExporterAzureMonitor, AzureConnectionString: "InstrumentationKey=new-key"}
+
+	changes := DiffConfig(old, updated)
+	if len(changes) != 1 || changes[0].Old != redactedValue || changes[0].New != redactedValue {
+		t.Fatalf("expected redacted connection string change, got %+v", changes)
+	}
+}
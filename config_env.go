@@ -0,0 +1,124 @@
+package otelx
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// ConfigFromEnv builds a Config from environment variables named after its JSON fields in upper
+// snake case behind prefix, e.g. OTELX_SERVICE_NAME, OTELX_SAMPLING_RATIO or
+// OTELX_EXPORTER_TIMEOUT for prefix "OTELX", so twelve-factor services need no config file.
+// Scalars use their Go syntax (durations like "5s"), string maps the OTEL_RESOURCE_ATTRIBUTES
+// format ("k=v,k2=v2", values URL encoded) and nested structs and lists their JSON form, e.g.
+// OTELX_EXPORTERS='[{"exporter":"stdout"}]'. Unset and empty variables leave fields zero. The
+// result is validated by Setup as usual.
+func ConfigFromEnv(prefix string) (Config, error) {
+	return configFromEnv(prefix, os.Getenv)
+}
+
+func configFromEnv(prefix string, getenv func(string) string) (Config, error) {
+	if prefix != "" && !strings.HasSuffix(prefix, "_") {
+		prefix += "_"
+	}
+	var cfg Config
+	var errs []error
+	v := reflect.ValueOf(&cfg).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		tag, _, _ := strings.Cut(v.Type().Field(i).Tag.Get("json"), ",")
+		if tag == "" || tag == "-" {
+			continue
+		}
+		name := prefix + envName(tag)
+		raw := strings.TrimSpace(getenv(name))
+		if raw == "" {
+			continue
+		}
+		if err := setFromEnv(v.Field(i), raw); err != nil {
+			errs = append(errs, fmt.Errorf("otelx: %s: %w", name, err))
+		}
+	}
+	return cfg, errors.Join(errs...)
+}
+
+// envName turns a JSON field name into an environment variable suffix: serviceName becomes
+// SERVICE_NAME and scrubPII becomes SCRUB_PII.
+func envName(field string) string {
+	runes := []rune(field)
+	var b strings.Builder
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				b.WriteByte('_')
+			}
+		}
+		b.WriteRune(unicode.ToUpper(r))
+	}
+	return b.String()
+}
+
+// setFromEnv parses raw into field according to the field's type.
+func setFromEnv(field reflect.Value, raw string) error {
+	if field.Kind() == reflect.Pointer && field.Type().Elem().Kind() != reflect.Struct {
+		elem := reflect.New(field.Type().Elem())
+		if err := setFromEnv(elem.Elem(), raw); err != nil {
+			return err
+		}
+		field.Set(elem)
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	case reflect.Int, reflect.Int64:
+		if field.Type() == durationType {
+			d, err := time.ParseDuration(raw)
+			if err != nil {
+				return err
+			}
+			field.SetInt(int64(d))
+			return nil
+		}
+		n, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return err
+		}
+		field.SetInt(n)
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return err
+		}
+		field.SetFloat(f)
+	case reflect.Map:
+		if field.Type().Elem().Kind() == reflect.String {
+			values, err := parseKeyValueList(raw)
+			if err != nil {
+				return err
+			}
+			field.Set(reflect.ValueOf(values))
+			return nil
+		}
+		return json.Unmarshal([]byte(raw), field.Addr().Interface())
+	default:
+		return json.Unmarshal([]byte(raw), field.Addr().Interface())
+	}
+	return nil
+}
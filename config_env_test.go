@@ -0,0 +1,75 @@
+package otelx
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestConfigFromEnv(t *testing.T) {
+	cfg, err := configFromEnv("OTELX", envMap(map[string]string{
+		"OTELX_SERVICE_NAME":     " checkout ",
+		"OTELX_ENABLED":          "true",
+		"OTELX_EXPORTER":         "otlphttp",
+		"OTELX_SAMPLING_RATIO":   "0.25",
+		"OTELX_EXPORTER_TIMEOUT": "3s",
+		"OTELX_FILE_MAX_BYTES":   "1024",
+		"OTELX_GCP_PROJECT_ID":   "proj",
+		"OTELX_SCRUB_PII":        "1",
+		"OTELX_HEADERS":          "x-api-key=abc%20def,x-team=core",
+		"OTELX_BATCH":            `{"maxExportBatchSize": 128}`,
+		"OTELX_DROP_SPANS":       `[{"name": "GET /healthz"}]`,
+		"OTHER_SERVICE_NAME":     "ignored",
+	}))
+	if err != nil {
+		t.Fatalf("config from env: %v", err)
+	}
+	want := Config{
+		ServiceName:     "checkout",
+		Enabled:         Bool(true),
+		Exporter:        ExporterOTLPHTTP,
+		SamplingRatio:   Float64(0.25),
+		ExporterTimeout: 3 * time.Second,
+		FileMaxBytes:    1024,
+		GCPProjectID:    "proj",
+		ScrubPII:        true,
+		Headers:         map[string]string{"x-api-key": "abc def", "x-team": "core"},
+		Batch:           &BatchConfig{MaxExportBatchSize: 128},
+		DropSpans:       []SpanDropRule{{Name: "GET /healthz"}},
+	}
+	if !reflect.DeepEqual(cfg, want) {
+		t.Fatalf("got %+v, want %+v", cfg, want)
+	}
+}
+
+func TestConfigFromEnvErrors(t *testing.T) {
+	_, err := configFromEnv("APP_", envMap(map[string]string{
+		"APP_SAMPLING_RATIO":   "half",
+		"APP_METRICS_INTERVAL": "60",
+		"APP_TLS":              "{",
+	}))
+	if err == nil {
+		t.Fatalf("expected errors")
+	}
+	for _, name := range []string{"APP_SAMPLING_RATIO", "APP_METRICS_INTERVAL", "APP_TLS"} {
+		if !strings.Contains(err.Error(), name) {
+			t.Fatalf("error %q does not mention %s", err, name)
+		}
+	}
+}
+
+func TestEnvName(t *testing.T) {
+	for field, want := range map[string]string{
+		"serviceName":       "SERVICE_NAME",
+		"gcpProjectId":      "GCP_PROJECT_ID",
+		"scrubPII":          "SCRUB_PII",
+		"otlpRetry":         "OTLP_RETRY",
+		"baggageMaxMembers": "BAGGAGE_MAX_MEMBERS",
+		"tls":               "TLS",
+	} {
+		if got := envName(field); got != want {
+			t.Fatalf("envName(%q) = %q, want %q", field, got, want)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package otelx
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// ContextAttributeExtractor derives span attributes from the context a span is started with.
+// Typical implementations read request-scoped values placed by auth middleware (user id, org id).
+type ContextAttributeExtractor func(ctx context.Context) []attribute.KeyValue
+
+// contextAttributeProcessor applies ContextAttributeExtractors to every span on start.
+type contextAttributeProcessor struct {
+	extractors []ContextAttributeExtractor
+}
+
+func newContextAttributeProcessor(extractors []ContextAttributeExtractor) sdktrace.SpanProcessor {
+	return &contextAttributeProcessor{extractors: extractors}
+}
+
+func (p *contextAttributeProcessor) OnStart(ctx context.Context, span sdktrace.ReadWriteSpan) {
+	for _, extract := range p.extractors {
+		if attrs := extract(ctx); len(attrs) > 0 {
+			span.SetAttributes(attrs...)
+		}
+	}
+}
+
+func (p *contextAttributeProcessor) OnEnd(sdktrace.ReadOnlySpan) {}
+
+func (p *contextAttributeProcessor) Shutdown(context.Context) error { return nil }
+
+func (p *contextAttributeProcessor) ForceFlush(context.Context) error { return nil }
@@ -0,0 +1,46 @@
+package otelx
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+type userIDKey struct{}
+
+func TestContextAttributeExtractorAddsAttributes(t *testing.T) {
+	extractor := func(ctx context.Context) []attribute.KeyValue {
+		if id, ok := ctx.Value(userIDKey{}).(string); ok {
+			return []attribute.KeyValue{attribute.String("enduser.id", id)}
+		}
+		return nil
+	}
+	prov, err := Setup(context.Background(), Config{ServiceName: "svc", SamplingRatio: Float64(1)}, nil,
+		WithContextAttributeExtractor(extractor))
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	defer prov.Shutdown(context.Background())
+
+	recorder := tracetest.NewSpanRecorder()
+	prov.TP.RegisterSpanProcessor(recorder)
+
+	ctx := context.WithValue(context.Background(), userIDKey{}, "u-42")
+	_, span := prov.TP.Tracer("test").Start(ctx, "with-user")
+	span.End()
+	_, span = prov.TP.Tracer("test").Start(context.Background(), "anonymous")
+	span.End()
+
+	ended := recorder.Ended()
+	if len(ended) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(ended))
+	}
+	if !spanHasAttribute(ended[0].Attributes(), "enduser.id", "u-42") {
+		t.Fatalf("expected enduser.id attribute on span, got %v", ended[0].Attributes())
+	}
+	if spanHasAttribute(ended[1].Attributes(), "enduser.id", "u-42") {
+		t.Fatalf("did not expect enduser.id attribute on anonymous span")
+	}
+}
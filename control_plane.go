@@ -0,0 +1,142 @@
+package otelx
+
+import (
+	"fmt"
+	"strings"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// DefaultControlPlaneRatio samples control-plane root spans when ControlPlaneConfig.Ratio is nil.
+const DefaultControlPlaneRatio = 0.001
+
+var (
+	defaultControlPlanePaths = []string{
+		"/healthz", "/livez", "/readyz", "/health", "/ping", "/metrics", "/debug/", "/admin/",
+	}
+	defaultControlPlaneMethods = []string{
+		"/grpc.health.v1.Health/",
+		"/grpc.reflection.v1.ServerReflection/",
+		"/grpc.reflection.v1alpha.ServerReflection/",
+		"/grpc.channelz.v1.Channelz/",
+	}
+)
+
+// ControlPlaneConfig classifies infrastructure traffic (health checks, metrics scrapes, debug and
+// admin endpoints) and samples it at its own near-zero Ratio, keeping it out of the business
+// sampling budget.
+type ControlPlaneConfig struct {
+	// Paths are HTTP paths, matched exactly or, when ending in "/", as a prefix. Empty uses
+	// /healthz, /livez, /readyz, /health, /ping, /metrics, /debug/ and /admin/.
+	Paths []string `json:"paths"`
+	// Methods are gRPC full methods ("/pkg.Service/Method") matched the same way. Empty uses the
+	// grpc.health.v1, grpc.reflection and grpc.channelz services.
+	Methods []string `json:"methods"`
+	// Ratio samples control-plane root spans; nil selects DefaultControlPlaneRatio.
+	Ratio *float64 `json:"ratio"`
+}
+
+func (c ControlPlaneConfig) validate() error {
+	for i, p := range c.Paths {
+		if !strings.HasPrefix(p, "/") {
+			return fmt.Errorf("paths[%d] must start with /, got %q", i, p)
+		}
+	}
+	for i, m := range c.Methods {
+		if !strings.HasPrefix(m, "/") {
+			return fmt.Errorf("methods[%d] must start with /, got %q", i, m)
+		}
+	}
+	if c.Ratio != nil && (*c.Ratio < 0 || *c.Ratio > 1) {
+		return fmt.Errorf("ratio must be within [0,1], got %v", *c.Ratio)
+	}
+	return nil
+}
+
+// ControlPlaneSampler samples root spans of control-plane requests, classified from the HTTP path
+// (url.path, http.target or the "METHOD /path" span name) or the gRPC method (rpc.service and
+// rpc.method or the span name), at cfg.Ratio and hands every other span to dataPlane. Setup
+// installs it for Config.ControlPlane; use it directly with WithSampler or hand-built providers.
+func ControlPlaneSampler(cfg ControlPlaneConfig, dataPlane sdktrace.Sampler) sdktrace.Sampler {
+	s := &controlPlaneSampler{
+		paths:     cfg.Paths,
+		methods:   cfg.Methods,
+		dataPlane: dataPlane,
+	}
+	if len(s.paths) == 0 {
+		s.paths = defaultControlPlanePaths
+	}
+	if len(s.methods) == 0 {
+		s.methods = defaultControlPlaneMethods
+	}
+	s.ratio = DefaultControlPlaneRatio
+	if cfg.Ratio != nil {
+		s.ratio = *cfg.Ratio
+	}
+	s.controlPlane = sdktrace.TraceIDRatioBased(s.ratio)
+	return s
+}
+
+type controlPlaneSampler struct {
+	paths        []string
+	methods      []string
+	ratio        float64
+	controlPlane sdktrace.Sampler
+	dataPlane    sdktrace.Sampler
+}
+
+func (s *controlPlaneSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	if s.isControlPlane(p) {
+		return s.controlPlane.ShouldSample(p)
+	}
+	return s.dataPlane.ShouldSample(p)
+}
+
+func (s *controlPlaneSampler) Description() string {
+	return fmt.Sprintf("ControlPlane{ratio=%g,dataPlane=%s}", s.ratio, s.dataPlane.Description())
+}
+
+func (s *controlPlaneSampler) isControlPlane(p sdktrace.SamplingParameters) bool {
+	var path, service, method string
+	for _, kv := range p.Attributes {
+		switch kv.Key {
+		case "url.path":
+			path = kv.Value.AsString()
+		case "http.target":
+			if path == "" {
+				path, _, _ = strings.Cut(kv.Value.AsString(), "?")
+			}
+		case "rpc.service":
+			service = kv.Value.AsString()
+		case "rpc.method":
+			method = kv.Value.AsString()
+		}
+	}
+	if path != "" {
+		return matchControlPlane(s.paths, path)
+	}
+	if service != "" && method != "" {
+		return matchControlPlane(s.methods, "/"+service+"/"+method)
+	}
+	name := p.Name
+	if _, rest, ok := strings.Cut(name, " "); ok {
+		// "GET /healthz" style HTTP server span names.
+		return strings.HasPrefix(rest, "/") && matchControlPlane(s.paths, rest)
+	}
+	// otelgrpc names spans after the full method without its leading slash.
+	return strings.Contains(name, "/") && matchControlPlane(s.methods, "/"+strings.TrimPrefix(name, "/"))
+}
+
+// matchControlPlane reports whether value equals an entry or, for entries ending in "/", starts
+// with it ("/debug/" also matches "/debug").
+func matchControlPlane(entries []string, value string) bool {
+	for _, e := range entries {
+		if value == e {
+			return true
+		}
+		if strings.HasSuffix(e, "/") && (strings.HasPrefix(value, e) || value == e[:len(e)-1]) {
+			return true
+		}
+	}
+	return false
+}
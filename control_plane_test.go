@@ -0,0 +1,86 @@
+package otelx
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestControlPlaneSampling(t *testing.T) {
+	cfg := Config{
+		ServiceName:   "svc",
+		Exporter:      ExporterMemory,
+		SamplingRatio: Float64(1),
+		ControlPlane:  &ControlPlaneConfig{Ratio: Float64(0)},
+	}
+	prov, err := Setup(context.Background(), cfg, nil)
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	defer prov.Shutdown(context.Background())
+
+	tracer := prov.TP.Tracer("test")
+	start := func(name string, attrs ...attribute.KeyValue) {
+		ctx, span := tracer.Start(context.Background(), name, trace.WithSpanKind(trace.SpanKindServer), trace.WithAttributes(attrs...))
+		_, child := tracer.Start(ctx, "child of "+name)
+		child.End()
+		span.End()
+	}
+	start("GET", attribute.String("url.path", "/healthz"))
+	start("GET /debug/pprof/heap")
+	start("grpc.health.v1.Health/Check")
+	start("video.v1.VideoService/Get", attribute.String("rpc.service", "video.v1.VideoService"), attribute.String("rpc.method", "Get"))
+	start("GET", attribute.String("url.path", "/orders"))
+
+	var names []string
+	for _, s := range prov.RecordedSpans() {
+		names = append(names, s.Name)
+	}
+	want := "child of video.v1.VideoService/Get,video.v1.VideoService/Get,child of GET,GET"
+	if got := strings.Join(names, ","); got != want {
+		t.Fatalf("expected only data-plane spans %q, got %q", want, got)
+	}
+}
+
+func TestControlPlaneSamplerCustomLists(t *testing.T) {
+	s := ControlPlaneSampler(ControlPlaneConfig{
+		Paths:   []string{"/internal/"},
+		Methods: []string{"/ops.Admin/Drain"},
+		Ratio:   Float64(0),
+	}, sdktrace.AlwaysSample())
+	cases := map[string]bool{
+		"GET /internal":     false,
+		"GET /internal/gc":  false,
+		"GET /healthz":      true,
+		"ops.Admin/Drain":   false,
+		"ops.Admin/Restart": true,
+	}
+	for name, sampled := range cases {
+		got := s.ShouldSample(sdktrace.SamplingParameters{ParentContext: context.Background(), Name: name, TraceID: trace.TraceID{1}})
+		if (got.Decision == sdktrace.RecordAndSample) != sampled {
+			t.Fatalf("%s: expected sampled=%v, got %v", name, sampled, got.Decision)
+		}
+	}
+	if !strings.HasPrefix(s.Description(), "ControlPlane{ratio=0,dataPlane=AlwaysOnSampler") {
+		t.Fatalf("unexpected description %q", s.Description())
+	}
+}
+
+func TestControlPlaneValidation(t *testing.T) {
+	cases := map[string]ControlPlaneConfig{
+		"paths[0] must start with /":   {Paths: []string{"healthz"}},
+		"methods[0] must start with /": {Methods: []string{"grpc.health.v1.Health/"}},
+		"ratio must be within [0,1]":   {Ratio: Float64(2)},
+	}
+	for want, cp := range cases {
+		cfg := Config{ServiceName: "svc", Exporter: ExporterMemory, ControlPlane: &cp}
+		_, err := Setup(context.Background(), cfg, nil)
+		if err == nil || !strings.Contains(err.Error(), "otelx: controlPlane: "+want) {
+			t.Fatalf("expected %q error, got %v", want, err)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package otelx
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestSetupDebugTeePrintsSpans(t *testing.T) {
+	var out bytes.Buffer
+	defer func(prev io.Writer) { debugTeeOutput = prev }(debugTeeOutput)
+	debugTeeOutput = &out
+
+	cfg := Config{ServiceName: "svc", Exporter: ExporterMemory, SamplingRatio: Float64(1), DebugTee: true}
+	prov, err := Setup(context.Background(), cfg, noopLogger{})
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	_, span := prov.TP.Tracer("test").Start(context.Background(), "diagnose-me")
+	span.End()
+	defer prov.Shutdown(context.Background())
+
+	if len(prov.RecordedSpans()) != 1 {
+		t.Fatalf("expected the primary exporter to still receive the span")
+	}
+	if !strings.Contains(out.String(), `"Name": "diagnose-me"`) {
+		t.Fatalf("expected pretty-printed span on the debug tee, got %q", out.String())
+	}
+}
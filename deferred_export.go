@@ -0,0 +1,54 @@
+package otelx
+
+import (
+	"errors"
+	"time"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+const (
+	// DefaultDeferredExportInterval is the export period of DeferredExportConfig when Interval is unset.
+	DefaultDeferredExportInterval = 5 * time.Minute
+	// DefaultDeferredExportMaxSpans is the buffer size of DeferredExportConfig when MaxSpans is unset.
+	DefaultDeferredExportMaxSpans = 20000
+)
+
+// DeferredExportConfig switches the span batchers to deferred export for batch jobs: spans
+// accumulate in a bounded buffer and leave in one large chunk every Interval, when the buffer is
+// full, or at Provider.ForceFlush/Shutdown (job end), instead of trickling out every few seconds
+// next to the job's own traffic.
+type DeferredExportConfig struct {
+	// Interval between exports (default DefaultDeferredExportInterval).
+	Interval time.Duration `json:"interval"`
+	// MaxSpans bounds the buffer and so the chunk size (default DefaultDeferredExportMaxSpans).
+	// A full buffer is exported early; spans arriving while it is being exported queue up to the
+	// same bound and are dropped beyond it.
+	MaxSpans int `json:"maxSpans"`
+}
+
+func (c DeferredExportConfig) validate() error {
+	if c.Interval < 0 {
+		return errors.New("interval must not be negative")
+	}
+	if c.MaxSpans < 0 {
+		return errors.New("maxSpans must not be negative")
+	}
+	return nil
+}
+
+// batchOptions configures a BatchSpanProcessor so its batch is the deferred buffer.
+func (c DeferredExportConfig) batchOptions() []sdktrace.BatchSpanProcessorOption {
+	interval, maxSpans := c.Interval, c.MaxSpans
+	if interval == 0 {
+		interval = DefaultDeferredExportInterval
+	}
+	if maxSpans == 0 {
+		maxSpans = DefaultDeferredExportMaxSpans
+	}
+	return []sdktrace.BatchSpanProcessorOption{
+		sdktrace.WithBatchTimeout(interval),
+		sdktrace.WithMaxQueueSize(maxSpans),
+		sdktrace.WithMaxExportBatchSize(maxSpans),
+	}
+}
@@ -0,0 +1,107 @@
+package otelx
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+type chunkExporter struct {
+	mu     sync.Mutex
+	chunks []int
+}
+
+func (e *chunkExporter) ExportSpans(_ context.Context, spans []sdktrace.ReadOnlySpan) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.chunks = append(e.chunks, len(spans))
+	return nil
+}
+
+func (e *chunkExporter) Shutdown(context.Context) error { return nil }
+
+func (e *chunkExporter) Chunks() []int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]int(nil), e.chunks...)
+}
+
+func TestDeferredExportHoldsSpansUntilFlush(t *testing.T) {
+	exp := &chunkExporter{}
+	cfg := Config{
+		ServiceName:    "svc",
+		SamplingRatio:  Float64(1),
+		DeferredExport: &DeferredExportConfig{Interval: time.Hour, MaxSpans: 1000},
+	}
+	prov, err := Setup(context.Background(), cfg, nil, WithSpanExporter(exp))
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+
+	tracer := prov.TP.Tracer("job")
+	for range 600 {
+		_, span := tracer.Start(context.Background(), "item")
+		span.End()
+	}
+	time.Sleep(50 * time.Millisecond)
+	if got := exp.Chunks(); len(got) != 0 {
+		t.Fatalf("expected spans to be held back, exported %v", got)
+	}
+
+	if err := prov.Shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown failed: %v", err)
+	}
+	if got := exp.Chunks(); len(got) != 1 || got[0] != 600 {
+		t.Fatalf("expected one chunk of 600 spans at shutdown, got %v", got)
+	}
+
+	var found bool
+	for _, p := range prov.Describe().Processors {
+		found = found || p.Name == "deferredExport"
+	}
+	if !found {
+		t.Fatalf("expected deferredExport in the description")
+	}
+}
+
+func TestDeferredExportFlushesFullBuffer(t *testing.T) {
+	exp := &chunkExporter{}
+	cfg := Config{
+		ServiceName:    "svc",
+		SamplingRatio:  Float64(1),
+		DeferredExport: &DeferredExportConfig{Interval: time.Hour, MaxSpans: 100},
+	}
+	prov, err := Setup(context.Background(), cfg, nil, WithSpanExporter(exp))
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	defer prov.Shutdown(context.Background())
+
+	tracer := prov.TP.Tracer("job")
+	for range 100 {
+		_, span := tracer.Start(context.Background(), "item")
+		span.End()
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for len(exp.Chunks()) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected a full buffer to be exported early")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if got := exp.Chunks(); got[0] != 100 {
+		t.Fatalf("expected a chunk of 100 spans, got %v", got)
+	}
+}
+
+func TestDeferredExportValidation(t *testing.T) {
+	cfg := Config{ServiceName: "svc", Exporter: ExporterMemory, DeferredExport: &DeferredExportConfig{MaxSpans: -1}}
+	_, err := Setup(context.Background(), cfg, nil)
+	if err == nil || !strings.Contains(err.Error(), "otelx: deferredExport: maxSpans must not be negative") {
+		t.Fatalf("expected maxSpans error, got %v", err)
+	}
+}
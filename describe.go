@@ -0,0 +1,172 @@
+package otelx
+
+import (
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// Description is a snapshot of the pipeline a Provider runs, for debug endpoints and for tests
+// asserting that Setup built what they expect. It never contains headers or credentials.
+type Description struct {
+	Enabled     bool   `json:"enabled"`
+	ServiceName string `json:"serviceName,omitempty"`
+	Environment string `json:"environment,omitempty"`
+	// External is set when the TracerProvider came from WithTracerProvider; sampler, exporters and
+	// resource are then unknown to otelx.
+	External   bool                   `json:"external,omitempty"`
+	Sampler    string                 `json:"sampler,omitempty"`
+	Exporters  []ExporterDescription  `json:"exporters,omitempty"`
+	Processors []ProcessorDescription `json:"processors,omitempty"`
+	Resource   map[string]string      `json:"resource,omitempty"`
+	Metrics    bool                   `json:"metrics"`
+	Logs       bool                   `json:"logs"`
+}
+
+// ExporterDescription describes one export pipeline.
+type ExporterDescription struct {
+	// Exporter is the configured exporter type, or "custom" for WithSpanExporter.
+	Exporter    ExporterType `json:"exporter"`
+	Endpoint    string       `json:"endpoint,omitempty"`
+	URLPath     string       `json:"urlPath,omitempty"`
+	Insecure    bool         `json:"insecure,omitempty"`
+	ExportRatio *float64     `json:"exportRatio,omitempty"`
+	DryRun      bool         `json:"dryRun,omitempty"`
+	DebugTee    bool         `json:"debugTee,omitempty"`
+}
+
+// ProcessorDescription names one span processing step and the phase it runs in.
+type ProcessorDescription struct {
+	Name  string `json:"name"`
+	Phase string `json:"phase"`
+}
+
+// describer keeps what Describe needs; sampler and resource are read live so runtime changes show.
+type describer struct {
+	static   Description
+	sampler  sdktrace.Sampler
+	resource func() *resource.Resource
+}
+
+// Describe returns a snapshot of the active configuration: exporters (including ones replaced by
+// ApplyRemoteConfig), the sampler with its current ratio, processors in execution order and the
+// resource attributes.
+func (p *Provider) Describe() Description {
+	if p == nil || p.desc == nil {
+		return Description{}
+	}
+	d := p.desc.static
+	d.Exporters = append([]ExporterDescription(nil), d.Exporters...)
+	for i, pipeline := range p.pipelines {
+		d.Exporters[i] = describeExporter(pipeline.config(), false)
+	}
+	d.Processors = append([]ProcessorDescription(nil), d.Processors...)
+	if p.desc.sampler != nil {
+		d.Sampler = p.desc.sampler.Description()
+	}
+	if p.desc.resource != nil {
+		if res := p.desc.resource(); res != nil {
+			d.Resource = make(map[string]string, res.Len())
+			for _, kv := range res.Attributes() {
+				d.Resource[string(kv.Key)] = kv.Value.Emit()
+			}
+		}
+	}
+	return d
+}
+
+func describeExporter(ec ExporterConfig, dryRun bool) ExporterDescription {
+	if ec.Exporter == "" {
+		ec.Exporter = ExporterStdout
+	}
+	// Mirrors buildExporter, which turns TLS off for loopback collectors.
+	if ec.autoInsecure() {
+		ec.Insecure = true
+	}
+	return ExporterDescription{
+		Exporter:    ec.Exporter,
+		Endpoint:    ec.Endpoint,
+		URLPath:     ec.URLPath,
+		Insecure:    ec.Insecure,
+		ExportRatio: ec.ExportRatio,
+		DryRun:      dryRun,
+	}
+}
+
+// describeProcessors lists the span processing steps Setup installs, in execution order. Steps that
+// only observe ended spans are reported in the "observe" phase.
+func (o *setupOptions) describeProcessors(cfg Config, order []SpanStage, spanMetrics, adaptive bool) []ProcessorDescription {
+	var out []ProcessorDescription
+	add := func(name string, phase string) {
+		out = append(out, ProcessorDescription{Name: name, Phase: phase})
+	}
+	if len(o.attrExtractors) > 0 {
+		add("contextAttributes", PhaseEnrich.String())
+	}
+	if len(o.spanNames) > 0 {
+		add("spanNames", PhaseEnrich.String())
+	}
+	if len(cfg.SpanKindAttributes) > 0 {
+		add("spanKindAttributes", PhaseEnrich.String())
+	}
+	if cfg.SchemaValidation != SchemaOff && (len(cfg.AttributeSchemas) > 0 || len(o.attrSchemas) > 0) {
+		add("schemaValidation", "observe")
+	}
+	if spanMetrics {
+		add("spanMetrics", "observe")
+	}
+	if adaptive {
+		add("adaptiveSampling", "observe")
+	}
+	for range o.spanProcessors {
+		add("spanProcessor", PhaseEnrich.String())
+	}
+	for _, stage := range order {
+		if o.stageEnabled(stage) {
+			add(string(stage), stagePhases[stage].String())
+		}
+	}
+	if o.clock != nil {
+		add("clock", PhaseExport.String())
+	}
+	if o.refreshEvery > 0 {
+		add("resourceRefresh", PhaseExport.String())
+	}
+	if o.sampleErrors {
+		add("alwaysSampleErrors", PhaseExport.String())
+	}
+	if cfg.MaxSpansPerRequest > 0 {
+		add("spanBudget", PhaseFilter.String())
+	}
+	if len(cfg.DropSpans) > 0 {
+		add("dropSpans", PhaseFilter.String())
+	}
+	if cfg.TailSampling != nil {
+		add("tailSampling", PhaseFilter.String())
+	}
+	if o.syncExport {
+		add("syncExport", PhaseExport.String())
+	} else if cfg.DeferredExport != nil {
+		add("deferredExport", PhaseExport.String())
+	} else {
+		add("batch", PhaseExport.String())
+	}
+	return out
+}
+
+// describeExporters lists the pipelines Setup builds from cfg and options.
+func describeExporters(cfg Config, options *setupOptions) []ExporterDescription {
+	var out []ExporterDescription
+	if len(options.spanExporters) > 0 {
+		for range options.spanExporters {
+			out = append(out, ExporterDescription{Exporter: "custom"})
+		}
+	} else {
+		for _, ec := range cfg.exporterConfigs() {
+			out = append(out, describeExporter(ec, cfg.DryRun))
+		}
+	}
+	if cfg.DebugTee {
+		out = append(out, ExporterDescription{Exporter: ExporterStdout, DebugTee: true})
+	}
+	return out
+}
@@ -0,0 +1,88 @@
+package otelx
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func TestDescribeReportsPipeline(t *testing.T) {
+	cfg := Config{
+		ServiceName:   "svc",
+		Environment:   "staging",
+		SamplingRatio: Float64(0.25),
+		Exporters: []ExporterConfig{
+			{Exporter: ExporterMemory},
+			{Exporter: ExporterOTLP, Endpoint: "localhost:4317", Headers: map[string]string{"x-api-key": "secret"}},
+		},
+	}
+	prov, err := Setup(context.Background(), cfg, nil,
+		WithSpanNameNormalizers(NumericIDNormalizer),
+		WithAttributeTruncation(64),
+	)
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	defer prov.Shutdown(ctx)
+
+	d := prov.Describe()
+	if !d.Enabled || d.ServiceName != "svc" || d.Environment != "staging" {
+		t.Fatalf("unexpected identity: %+v", d)
+	}
+	if len(d.Exporters) != 2 || d.Exporters[0].Exporter != ExporterMemory ||
+		d.Exporters[1].Exporter != ExporterOTLP || d.Exporters[1].Endpoint != "localhost:4317" || !d.Exporters[1].Insecure {
+		t.Fatalf("unexpected exporters: %+v", d.Exporters)
+	}
+	if !strings.Contains(d.Sampler, "TraceIDRatioBased{0.25}") {
+		t.Fatalf("unexpected sampler %q", d.Sampler)
+	}
+	var names []string
+	for _, p := range d.Processors {
+		names = append(names, p.Phase+":"+p.Name)
+	}
+	want := []string{"enrich:spanNames", "redact:attributeDrop", "redact:truncation", "export:batch"}
+	if !reflect.DeepEqual(names, want) {
+		t.Fatalf("expected processors %v, got %v", want, names)
+	}
+	if d.Resource["service.name"] != "svc" || d.Resource["deployment.environment.name"] != "staging" {
+		t.Fatalf("unexpected resource: %v", d.Resource)
+	}
+
+	raw, _ := json.Marshal(d)
+	if strings.Contains(string(raw), "secret") {
+		t.Fatalf("description must not contain headers: %s", raw)
+	}
+
+	if err := prov.SetSamplingRatio(1); err != nil {
+		t.Fatalf("set ratio: %v", err)
+	}
+	if s := prov.Describe().Sampler; !strings.Contains(s, "root:AlwaysOnSampler") {
+		t.Fatalf("expected sampler to reflect the new ratio, got %q", s)
+	}
+}
+
+func TestDescribeDisabledAndExternal(t *testing.T) {
+	disabled, err := Setup(context.Background(), Config{ServiceName: "svc", Enabled: Bool(false)}, nil)
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	if d := disabled.Describe(); d.Enabled || d.Sampler != "AlwaysOffSampler" {
+		t.Fatalf("unexpected disabled description: %+v", d)
+	}
+
+	tp := sdktrace.NewTracerProvider()
+	defer tp.Shutdown(context.Background())
+	external, err := Setup(context.Background(), Config{ServiceName: "svc"}, nil, WithTracerProvider(tp))
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	if d := external.Describe(); !d.External || d.Sampler != "" || len(d.Exporters) != 0 {
+		t.Fatalf("unexpected external description: %+v", d)
+	}
+}
@@ -0,0 +1,139 @@
+package otelx
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	logx "github.com/bionicotaku/lingo-utils-logx"
+)
+
+// WithBlockingDial makes Setup connect to every otlp/otlphttp endpoint, including the TLS
+// handshake, before building the exporters, and fail when one cannot be reached within timeout.
+// Without it the exporters connect lazily and an unreachable collector only shows up as failed
+// exports later. A pipeline with Failover passes when either endpoint is reachable.
+func WithBlockingDial(timeout time.Duration) Option {
+	return func(o *setupOptions) {
+		o.dialTimeout = timeout
+		o.dialWarnOnly = false
+	}
+}
+
+// WithBlockingDialWarnOnly runs the same check as WithBlockingDial but only logs unreachable
+// endpoints as otelx.exporter.unreachable warnings, so Setup still succeeds.
+func WithBlockingDialWarnOnly(timeout time.Duration) Option {
+	return func(o *setupOptions) {
+		o.dialTimeout = timeout
+		o.dialWarnOnly = true
+	}
+}
+
+// checkConnectivity dials the OTLP endpoints of cfg's pipelines as configured by options.
+func checkConnectivity(ctx context.Context, cfg Config, options *setupOptions, logger logx.Logger) error {
+	for _, ec := range cfg.exporterConfigs() {
+		if ec.Exporter != ExporterOTLP && ec.Exporter != ExporterOTLPHTTP {
+			continue
+		}
+		endpoints := []string{ec.Endpoint}
+		if ec.Failover != nil {
+			endpoints = append(endpoints, strings.TrimSpace(ec.Failover.Endpoint))
+		}
+		var errs []error
+		for _, endpoint := range endpoints {
+			ec.Endpoint = endpoint
+			dialCtx, cancel := context.WithTimeout(ctx, options.dialTimeout)
+			err := dialEndpoint(dialCtx, ec)
+			cancel()
+			if err == nil {
+				errs = nil
+				if logger != nil {
+					logger.Debug(ctx, "otelx.exporter.reachable", logx.String("endpoint", endpoint))
+				}
+				break
+			}
+			if endpoint == "" {
+				endpoint = otlpAddress(ec)
+			}
+			errs = append(errs, fmt.Errorf("%s: %w", endpoint, err))
+		}
+		if len(errs) == 0 {
+			continue
+		}
+		err := errors.Join(errs...)
+		if !options.dialWarnOnly {
+			return fmt.Errorf("otelx: connect to %s endpoint: %w", ec.Exporter, err)
+		}
+		if logger != nil {
+			logger.Warn(ctx, "otelx.exporter.unreachable", logx.String("exporter", string(ec.Exporter)), logx.String("error", err.Error()))
+		}
+	}
+	return nil
+}
+
+// dialEndpoint opens a connection to ec's endpoint and completes the TLS handshake unless the
+// exporter would connect in plaintext.
+func dialEndpoint(ctx context.Context, ec ExporterConfig) error {
+	var d net.Dialer
+	if path, ok := unixSocketPath(ec.Endpoint); ok {
+		conn, err := d.DialContext(ctx, "unix", path)
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	}
+
+	addr := otlpAddress(ec)
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	insecure := ec.Insecure || strings.HasPrefix(ec.Endpoint, "http://") || ec.autoInsecure()
+	if insecure {
+		return nil
+	}
+
+	tlsCfg := &tls.Config{MinVersion: tls.VersionTLS12}
+	if ec.TLS != nil {
+		if tlsCfg, err = ec.TLS.tlsConfig(); err != nil {
+			return err
+		}
+	}
+	if tlsCfg.ServerName == "" {
+		tlsCfg.ServerName, _, _ = net.SplitHostPort(addr)
+	}
+	if ec.Exporter == ExporterOTLP {
+		tlsCfg.NextProtos = []string{"h2"}
+	}
+	return tls.Client(conn, tlsCfg).HandshakeContext(ctx)
+}
+
+// otlpAddress returns the host:port the exporter for ec connects to, using the OTLP default port
+// of its protocol when the endpoint omits one.
+func otlpAddress(ec ExporterConfig) string {
+	port := "4317"
+	if ec.Exporter == ExporterOTLPHTTP {
+		port = "4318"
+	}
+	host := ec.Endpoint
+	if strings.Contains(host, "://") {
+		if u, err := url.Parse(host); err == nil {
+			host = u.Host
+			if u.Port() == "" && u.Scheme == "https" && ec.Exporter == ExporterOTLPHTTP {
+				port = "443"
+			}
+		}
+	}
+	if host == "" {
+		host = "localhost"
+	}
+	if _, _, err := net.SplitHostPort(host); err == nil {
+		return host
+	}
+	return net.JoinHostPort(strings.Trim(host, "[]"), port)
+}
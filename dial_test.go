@@ -0,0 +1,97 @@
+package otelx
+
+import (
+	"context"
+	"encoding/pem"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func closedAddress(t *testing.T) string {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	addr := lis.Addr().String()
+	_ = lis.Close()
+	return addr
+}
+
+func TestBlockingDial(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer lis.Close()
+	ctx := context.Background()
+
+	cfg := Config{ServiceName: "svc", Exporter: ExporterOTLP, Endpoint: lis.Addr().String()}
+	prov, err := Setup(ctx, cfg, nil, WithBlockingDial(time.Second))
+	if err != nil {
+		t.Fatalf("expected a reachable endpoint to pass, got %v", err)
+	}
+	_ = prov.Shutdown(ctx)
+
+	cfg.Endpoint = closedAddress(t)
+	if _, err := Setup(ctx, cfg, nil, WithBlockingDial(time.Second)); err == nil || !strings.Contains(err.Error(), cfg.Endpoint) {
+		t.Fatalf("expected an unreachable endpoint to fail setup, got %v", err)
+	}
+
+	logger := &recordingLogger{}
+	prov, err = Setup(ctx, cfg, logger, WithBlockingDialWarnOnly(time.Second))
+	if err != nil {
+		t.Fatalf("expected warn-only to keep setup working, got %v", err)
+	}
+	_ = prov.Shutdown(ctx)
+	if !slices.Contains(logger.Entries(), "warn:otelx.exporter.unreachable") {
+		t.Fatalf("expected an unreachable warning, got %v", logger.Entries())
+	}
+
+	cfg.Failover = &FailoverConfig{Endpoint: lis.Addr().String()}
+	prov, err = Setup(ctx, cfg, nil, WithBlockingDial(time.Second))
+	if err != nil {
+		t.Fatalf("expected a reachable failover endpoint to pass, got %v", err)
+	}
+	_ = prov.Shutdown(ctx)
+}
+
+func TestBlockingDialTLS(t *testing.T) {
+	srv := httptest.NewTLSServer(http.NotFoundHandler())
+	defer srv.Close()
+	ca := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw}))
+	endpoint := strings.TrimPrefix(srv.URL, "https://")
+
+	ec := ExporterConfig{Exporter: ExporterOTLPHTTP, Endpoint: endpoint, TLS: &TLSConfig{CAPEM: ca}}
+	if err := dialEndpoint(context.Background(), ec); err != nil {
+		t.Fatalf("expected the handshake to succeed, got %v", err)
+	}
+	ec.TLS = &TLSConfig{ServerName: "collector.internal", CAPEM: ca}
+	if err := dialEndpoint(context.Background(), ec); err == nil {
+		t.Fatal("expected a server name mismatch to fail the handshake")
+	}
+}
+
+func TestOTLPAddress(t *testing.T) {
+	cases := []struct {
+		ec   ExporterConfig
+		want string
+	}{
+		{ExporterConfig{Exporter: ExporterOTLP}, "localhost:4317"},
+		{ExporterConfig{Exporter: ExporterOTLPHTTP}, "localhost:4318"},
+		{ExporterConfig{Exporter: ExporterOTLP, Endpoint: "collector:14317"}, "collector:14317"},
+		{ExporterConfig{Exporter: ExporterOTLP, Endpoint: "collector"}, "collector:4317"},
+		{ExporterConfig{Exporter: ExporterOTLPHTTP, Endpoint: "https://collector.example.com"}, "collector.example.com:443"},
+		{ExporterConfig{Exporter: ExporterOTLPHTTP, Endpoint: "http://collector:4318"}, "collector:4318"},
+	}
+	for _, c := range cases {
+		if got := otlpAddress(c.ec); got != c.want {
+			t.Errorf("otlpAddress(%+v) = %q, want %q", c.ec, got, c.want)
+		}
+	}
+}
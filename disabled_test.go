@@ -0,0 +1,55 @@
+package otelx
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestSetupDisabledReturnsNoopProvider(t *testing.T) {
+	defer saveGlobal()()
+	// Exporter settings are ignored while disabled, even invalid ones.
+	cfg := Config{ServiceName: "svc", Enabled: Bool(false), Exporter: ExporterCloudTrace, SamplingRatio: Float64(1)}
+	prov, err := Setup(context.Background(), cfg, noopLogger{}, WithGlobal())
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	if otel.GetTracerProvider() != prov.TP {
+		t.Fatalf("expected disabled provider to be installed globally")
+	}
+
+	parent := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{1},
+		SpanID:     trace.SpanID{2},
+		TraceFlags: trace.FlagsSampled,
+		Remote:     true,
+	})
+	ctx, span := prov.TP.Tracer("test").Start(trace.ContextWithSpanContext(context.Background(), parent), "op")
+	if span.IsRecording() || span.SpanContext().IsSampled() {
+		t.Fatalf("expected spans not to be recorded")
+	}
+	span.End()
+
+	carrier := propagation.MapCarrier{}
+	prov.Propagator.Inject(ctx, carrier)
+	if carrier.Get("traceparent") == "" {
+		t.Fatalf("expected trace context to still propagate")
+	}
+	if err := prov.Shutdown(context.Background()); err != nil {
+		t.Fatalf("expected no-op shutdown, got %v", err)
+	}
+}
+
+func TestSetupEnabledExplicitly(t *testing.T) {
+	prov, err := Setup(context.Background(), Config{ServiceName: "svc", Enabled: Bool(true), Exporter: ExporterMemory, SamplingRatio: Float64(1)}, noopLogger{})
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	defer prov.Shutdown(context.Background())
+	if _, span := prov.TP.Tracer("test").Start(context.Background(), "op"); !span.IsRecording() {
+		t.Fatalf("expected spans to be recorded when enabled")
+	}
+}
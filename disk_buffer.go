@@ -0,0 +1,273 @@
+package otelx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	logx "github.com/bionicotaku/lingo-utils-logx"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// DefaultDiskBufferMaxBytes bounds a pipeline's buffer directory when MaxBytes is unset.
+	DefaultDiskBufferMaxBytes int64 = 256 << 20
+	// DefaultDiskBufferMaxAge discards buffered spans older than this when MaxAge is unset.
+	DefaultDiskBufferMaxAge = 24 * time.Hour
+	// DefaultDiskBufferReplayInterval is how often buffered spans are retried when
+	// ReplayInterval is unset.
+	DefaultDiskBufferReplayInterval = 30 * time.Second
+
+	diskBufferExt = ".otlp"
+)
+
+// DiskBufferConfig persists spans the exporter could not deliver, e.g. while the OTLP endpoint is
+// unreachable on a spotty edge link, and replays them once exports succeed again. Each file holds
+// one failed batch as an OTLP protobuf ExportTraceServiceRequest; files left by a previous run are
+// replayed too.
+type DiskBufferConfig struct {
+	// Dir holds the buffer; with several exporters each pipeline uses a numbered subdirectory.
+	Dir string `json:"dir"`
+	// MaxBytes bounds the buffer (default DefaultDiskBufferMaxBytes); the oldest files are
+	// removed to make room.
+	MaxBytes int64 `json:"maxBytes"`
+	// MaxAge discards buffered spans older than this instead of replaying them (default
+	// DefaultDiskBufferMaxAge).
+	MaxAge time.Duration `json:"maxAge"`
+	// ReplayInterval is the period between replay attempts (default DefaultDiskBufferReplayInterval).
+	ReplayInterval time.Duration `json:"replayInterval"`
+}
+
+func (c DiskBufferConfig) validate() error {
+	if strings.TrimSpace(c.Dir) == "" {
+		return errors.New("dir is required")
+	}
+	if c.MaxBytes < 0 {
+		return errors.New("maxBytes must not be negative")
+	}
+	if c.MaxAge < 0 || c.ReplayInterval < 0 {
+		return errors.New("maxAge and replayInterval must not be negative")
+	}
+	return nil
+}
+
+func (c DiskBufferConfig) withDefaults() DiskBufferConfig {
+	if c.MaxBytes == 0 {
+		c.MaxBytes = DefaultDiskBufferMaxBytes
+	}
+	if c.MaxAge == 0 {
+		c.MaxAge = DefaultDiskBufferMaxAge
+	}
+	if c.ReplayInterval == 0 {
+		c.ReplayInterval = DefaultDiskBufferReplayInterval
+	}
+	return c
+}
+
+// diskBufferExporter writes batches next fails to export into dir and periodically replays them,
+// oldest first. A batch that reached the disk counts as exported for the batcher.
+type diskBufferExporter struct {
+	next   sdktrace.SpanExporter
+	dir    string
+	cfg    DiskBufferConfig
+	logger logx.Logger
+	now    func() time.Time
+
+	mu       sync.Mutex // serializes buffer directory changes
+	exportMu sync.Mutex // serializes next.ExportSpans between the batcher and replay
+	seq      atomic.Uint64
+
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+func newDiskBufferExporter(next sdktrace.SpanExporter, dir string, cfg DiskBufferConfig, logger logx.Logger) (*diskBufferExporter, error) {
+	// Spans may carry PII or tokens, so the buffer is private to the process owner.
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	e := &diskBufferExporter{
+		next:   next,
+		dir:    dir,
+		cfg:    cfg.withDefaults(),
+		logger: logger,
+		now:    time.Now,
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	e.start()
+	return e, nil
+}
+
+func (e *diskBufferExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	err := e.export(ctx, spans)
+	if err == nil || len(spans) == 0 {
+		return err
+	}
+	if bufErr := e.write(spans); bufErr != nil {
+		return errors.Join(err, fmt.Errorf("otelx: disk buffer: %w", bufErr))
+	}
+	if e.logger != nil {
+		e.logger.Warn(ctx, "otelx.exporter.buffered", logx.Int("spans", len(spans)), logx.String("error", err.Error()))
+	}
+	return nil
+}
+
+// export calls next, which SpanExporter implementations need not make safe for concurrent use.
+func (e *diskBufferExporter) export(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	e.exportMu.Lock()
+	defer e.exportMu.Unlock()
+	return e.next.ExportSpans(ctx, spans)
+}
+
+// start replays buffered batches, including those of a previous run, every ReplayInterval until
+// Shutdown.
+func (e *diskBufferExporter) start() {
+	go func() {
+		defer close(e.done)
+		ticker := time.NewTicker(e.cfg.ReplayInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-e.stop:
+				return
+			case <-ticker.C:
+				e.replay(context.Background())
+			}
+		}
+	}()
+}
+
+func (e *diskBufferExporter) Shutdown(ctx context.Context) error {
+	e.stopOnce.Do(func() {
+		close(e.stop)
+	})
+	select {
+	case <-e.done:
+	case <-ctx.Done():
+	}
+	return e.next.Shutdown(ctx)
+}
+
+// write stores spans as a new file named after the current time, then trims the buffer.
+func (e *diskBufferExporter) write(spans []sdktrace.ReadOnlySpan) error {
+	data, err := proto.Marshal(&coltracepb.ExportTraceServiceRequest{ResourceSpans: spansToProto(spans)})
+	if err != nil {
+		return err
+	}
+	name := fmt.Sprintf("%020d-%06d%s", e.now().UnixNano(), e.seq.Add(1)%1_000_000, diskBufferExt)
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	tmp := filepath.Join(e.dir, name+".tmp")
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, filepath.Join(e.dir, name)); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	e.trim()
+	return nil
+}
+
+// trim removes the oldest files until the buffer fits MaxBytes. Callers hold e.mu.
+func (e *diskBufferExporter) trim() {
+	files := e.files()
+	var total int64
+	for _, f := range files {
+		total += f.size
+	}
+	removed := 0
+	for _, f := range files {
+		if total <= e.cfg.MaxBytes {
+			break
+		}
+		if os.Remove(f.path) == nil {
+			total -= f.size
+			removed++
+		}
+	}
+	if removed > 0 && e.logger != nil {
+		e.logger.Warn(context.Background(), "otelx.exporter.buffer.full", logx.Int("droppedFiles", removed))
+	}
+}
+
+type bufferedFile struct {
+	path    string
+	size    int64
+	written time.Time
+}
+
+// files lists the buffered batches, oldest first.
+func (e *diskBufferExporter) files() []bufferedFile {
+	entries, err := os.ReadDir(e.dir)
+	if err != nil {
+		return nil
+	}
+	var files []bufferedFile
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), diskBufferExt) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, bufferedFile{path: filepath.Join(e.dir, entry.Name()), size: info.Size(), written: info.ModTime()})
+	}
+	slices.SortFunc(files, func(a, b bufferedFile) int { return strings.Compare(a.path, b.path) })
+	return files
+}
+
+// replay re-exports buffered batches oldest first and stops at the first failure, leaving the
+// rest for the next attempt. Expired or unreadable files are removed.
+func (e *diskBufferExporter) replay(ctx context.Context) {
+	e.mu.Lock()
+	files := e.files()
+	e.mu.Unlock()
+
+	var replayed, spans, expired int
+	for _, f := range files {
+		if e.now().Sub(f.written) > e.cfg.MaxAge {
+			if os.Remove(f.path) == nil {
+				expired++
+			}
+			continue
+		}
+		data, err := os.ReadFile(f.path)
+		if err != nil {
+			continue
+		}
+		var req coltracepb.ExportTraceServiceRequest
+		if err := proto.Unmarshal(data, &req); err != nil {
+			_ = os.Remove(f.path)
+			continue
+		}
+		batch := protoToSpans(req.GetResourceSpans())
+		if err := e.export(ctx, batch); err != nil {
+			break
+		}
+		_ = os.Remove(f.path)
+		replayed++
+		spans += len(batch)
+	}
+	if e.logger == nil {
+		return
+	}
+	if replayed > 0 {
+		e.logger.Info(ctx, "otelx.exporter.replayed", logx.Int("batches", replayed), logx.Int("spans", spans))
+	}
+	if expired > 0 {
+		e.logger.Warn(ctx, "otelx.exporter.buffer.expired", logx.Int("batches", expired))
+	}
+}
@@ -0,0 +1,198 @@
+package otelx
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// flakyExporter fails while down is set and otherwise forwards to an in-memory exporter.
+type flakyExporter struct {
+	*tracetest.InMemoryExporter
+	down atomic.Bool
+}
+
+func (e *flakyExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	if e.down.Load() {
+		return errors.New("connection refused")
+	}
+	return e.InMemoryExporter.ExportSpans(ctx, spans)
+}
+
+func endedSpans(t *testing.T) []sdktrace.ReadOnlySpan {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	ctx, parent := tp.Tracer("test").Start(context.Background(), "parent", trace.WithSpanKind(trace.SpanKindServer))
+	_, child := tp.Tracer("test").Start(ctx, "child", trace.WithAttributes(
+		attribute.String("s", "v"), attribute.Int64Slice("ids", []int64{1, 2}), attribute.Bool("b", true),
+	))
+	child.AddEvent("retry", trace.WithAttributes(attribute.Float64("delay", 1.5)))
+	child.SetStatus(codes.Error, "boom")
+	child.End()
+	parent.End()
+	return recorder.Ended()
+}
+
+func TestDiskBufferReplaysAfterOutage(t *testing.T) {
+	next := &flakyExporter{InMemoryExporter: tracetest.NewInMemoryExporter()}
+	next.down.Store(true)
+	buffer, err := newDiskBufferExporter(next, t.TempDir(), DiskBufferConfig{ReplayInterval: time.Hour}, nil)
+	if err != nil {
+		t.Fatalf("create buffer: %v", err)
+	}
+	defer buffer.Shutdown(context.Background())
+
+	spans := endedSpans(t)
+	if err := buffer.ExportSpans(context.Background(), spans); err != nil {
+		t.Fatalf("expected the failed batch to be buffered, got %v", err)
+	}
+	buffer.replay(context.Background())
+	if got := len(buffer.files()); got != 1 {
+		t.Fatalf("expected the batch to stay buffered while the exporter is down, got %d files", got)
+	}
+
+	next.down.Store(false)
+	buffer.replay(context.Background())
+	if got := len(buffer.files()); got != 0 {
+		t.Fatalf("expected the buffer to be drained, got %d files", got)
+	}
+	got := next.GetSpans()
+	if len(got) != 2 {
+		t.Fatalf("expected 2 replayed spans, got %d", len(got))
+	}
+	child := got[0]
+	if child.Name != "child" || child.Parent.SpanID() != spans[1].SpanContext().SpanID() || !child.SpanContext.Equal(spans[0].SpanContext()) {
+		t.Fatalf("expected span identity to survive replay, got %+v", child)
+	}
+	if child.Status.Code != codes.Error || child.Status.Description != "boom" || got[1].SpanKind != trace.SpanKindServer {
+		t.Fatalf("expected status and kind to survive replay, got %+v", child)
+	}
+	set := attribute.NewSet(child.Attributes...)
+	if ids, _ := set.Value("ids"); len(ids.AsInt64Slice()) != 2 || !spanHasAttribute(child.Attributes, "s", "v") || !spanHasAttribute(child.Attributes, "b", "true") {
+		t.Fatalf("expected attributes to survive replay, got %v", child.Attributes)
+	}
+	if len(child.Events) != 1 || child.Events[0].Attributes[0].Value.AsFloat64() != 1.5 || !child.StartTime.Equal(spans[0].StartTime()) {
+		t.Fatalf("expected events and timestamps to survive replay, got %+v", child)
+	}
+}
+
+func TestDiskBufferLimits(t *testing.T) {
+	next := &flakyExporter{InMemoryExporter: tracetest.NewInMemoryExporter()}
+	next.down.Store(true)
+	dir := t.TempDir()
+	buffer, err := newDiskBufferExporter(next, dir, DiskBufferConfig{MaxBytes: 1, MaxAge: time.Minute, ReplayInterval: time.Hour}, nil)
+	if err != nil {
+		t.Fatalf("create buffer: %v", err)
+	}
+	defer buffer.Shutdown(context.Background())
+
+	spans := endedSpans(t)
+	_ = buffer.ExportSpans(context.Background(), spans)
+	if got := len(buffer.files()); got != 0 {
+		t.Fatalf("expected files beyond maxBytes to be removed, got %d", got)
+	}
+
+	buffer.cfg.MaxBytes = DefaultDiskBufferMaxBytes
+	_ = buffer.ExportSpans(context.Background(), spans)
+	next.down.Store(false)
+	buffer.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+	buffer.replay(context.Background())
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 0 || len(next.GetSpans()) != 0 {
+		t.Fatalf("expected expired batches to be discarded, got %d files and %d spans", len(entries), len(next.GetSpans()))
+	}
+}
+
+func TestDiskBufferValidation(t *testing.T) {
+	cfg := Config{ServiceName: "svc", Exporter: ExporterMemory, DiskBuffer: &DiskBufferConfig{}}
+	if _, err := Setup(context.Background(), cfg, nil); err == nil || !strings.Contains(err.Error(), "otelx: diskBuffer: dir is required") {
+		t.Fatalf("expected a validation error, got %v", err)
+	}
+}
+
+// exclusiveExporter fails when ExportSpans is entered concurrently.
+type exclusiveExporter struct {
+	*flakyExporter
+	busy, overlapped atomic.Bool
+}
+
+func (e *exclusiveExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	if !e.busy.CompareAndSwap(false, true) {
+		e.overlapped.Store(true)
+	}
+	defer e.busy.Store(false)
+	time.Sleep(time.Millisecond)
+	return e.flakyExporter.ExportSpans(ctx, spans)
+}
+
+func TestDiskBufferSerializesExports(t *testing.T) {
+	next := &exclusiveExporter{flakyExporter: &flakyExporter{InMemoryExporter: tracetest.NewInMemoryExporter()}}
+	next.down.Store(true)
+	dir := t.TempDir()
+	buffer, err := newDiskBufferExporter(next, dir+"/buffer", DiskBufferConfig{ReplayInterval: time.Hour}, nil)
+	if err != nil {
+		t.Fatalf("create buffer: %v", err)
+	}
+	defer buffer.Shutdown(context.Background())
+	spans := endedSpans(t)
+	for i := 0; i < 5; i++ {
+		_ = buffer.ExportSpans(context.Background(), spans)
+	}
+	files := buffer.files()
+	if len(files) != 5 {
+		t.Fatalf("expected 5 buffered batches, got %d", len(files))
+	}
+	for _, path := range []string{dir + "/buffer", files[0].path} {
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if perm := info.Mode().Perm(); perm&0o077 != 0 {
+			t.Fatalf("expected %s to be private, got %v", path, perm)
+		}
+	}
+
+	next.down.Store(false)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		buffer.replay(context.Background())
+	}()
+	for i := 0; i < 5; i++ {
+		_ = buffer.ExportSpans(context.Background(), spans)
+	}
+	<-done
+	if next.overlapped.Load() {
+		t.Fatal("expected replay and live exports not to call the exporter concurrently")
+	}
+}
+
+func TestSetupDiskBufferSkipsLocalExporters(t *testing.T) {
+	restore := saveGlobal()
+	defer restore()
+	dir := t.TempDir()
+	cfg := Config{ServiceName: "svc", DebugTee: true, DiskBuffer: &DiskBufferConfig{Dir: dir}, Exporters: []ExporterConfig{
+		{Exporter: ExporterMemory},
+		{Exporter: ExporterOTLP, Endpoint: "localhost:4317"},
+	}}
+	prov, err := Setup(context.Background(), cfg, nil)
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	defer prov.Shutdown(context.Background())
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 || entries[0].Name() != "1" {
+		t.Fatalf("expected only the otlp pipeline to be buffered, got %v", entries)
+	}
+}
@@ -0,0 +1,41 @@
+package otelx
+
+import (
+	"context"
+
+	"github.com/shirou/gopsutil/v4/disk"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// Attribute keys of the system.disk.io metric, following the OpenTelemetry system semantic
+// conventions.
+const (
+	diskDeviceKey    = attribute.Key("system.device")
+	diskDirectionKey = attribute.Key("disk.io.direction")
+)
+
+// startDiskMetrics adds system.disk.io, which the contrib host instrumentation does not cover.
+// Devices that cannot be read are skipped.
+func startDiskMetrics(mp metric.MeterProvider) error {
+	meter := mp.Meter(instrumentationName)
+	diskIO, err := meter.Int64ObservableCounter("system.disk.io",
+		metric.WithDescription("Disk bytes transferred."),
+		metric.WithUnit("By"),
+	)
+	if err != nil {
+		return err
+	}
+	_, err = meter.RegisterCallback(func(ctx context.Context, o metric.Observer) error {
+		counters, err := disk.IOCountersWithContext(ctx)
+		if err != nil {
+			return nil
+		}
+		for device, c := range counters {
+			o.ObserveInt64(diskIO, int64(c.ReadBytes), metric.WithAttributes(diskDeviceKey.String(device), diskDirectionKey.String("read")))
+			o.ObserveInt64(diskIO, int64(c.WriteBytes), metric.WithAttributes(diskDeviceKey.String(device), diskDirectionKey.String("write")))
+		}
+		return nil
+	}, diskIO)
+	return err
+}
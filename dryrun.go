@@ -0,0 +1,57 @@
+package otelx
+
+import (
+	"context"
+	"sync/atomic"
+
+	logx "github.com/bionicotaku/lingo-utils-logx"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// DryRunStats reports what a Config.DryRun pipeline would have sent to the exporter.
+type DryRunStats struct {
+	Batches int64 `json:"batches"`
+	Spans   int64 `json:"spans"`
+	// Bytes is the OTLP protobuf payload size, before any transport compression.
+	Bytes int64 `json:"bytes"`
+}
+
+// dryRunExporter serializes spans like an OTLP exporter would, counts them and discards them.
+type dryRunExporter struct {
+	target  ExporterType
+	logger  logx.Logger
+	batches atomic.Int64
+	spans   atomic.Int64
+	bytes   atomic.Int64
+}
+
+func newDryRunExporter(target ExporterType, logger logx.Logger) *dryRunExporter {
+	return &dryRunExporter{target: target, logger: logger}
+}
+
+func (e *dryRunExporter) ExportSpans(_ context.Context, spans []sdktrace.ReadOnlySpan) error {
+	req := &coltracepb.ExportTraceServiceRequest{ResourceSpans: spansToProto(spans)}
+	e.batches.Add(1)
+	e.spans.Add(int64(len(spans)))
+	e.bytes.Add(int64(proto.Size(req)))
+	return nil
+}
+
+func (e *dryRunExporter) Shutdown(ctx context.Context) error {
+	if e.logger != nil {
+		stats := e.stats()
+		e.logger.Info(ctx, "otelx.exporter.dryrun.summary",
+			logx.String("exporter", string(e.target)),
+			logx.Any("batches", stats.Batches),
+			logx.Any("spans", stats.Spans),
+			logx.Any("bytes", stats.Bytes),
+		)
+	}
+	return nil
+}
+
+func (e *dryRunExporter) stats() DryRunStats {
+	return DryRunStats{Batches: e.batches.Load(), Spans: e.spans.Load(), Bytes: e.bytes.Load()}
+}
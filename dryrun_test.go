@@ -0,0 +1,48 @@
+package otelx
+
+import (
+	"context"
+	"testing"
+)
+
+func TestSetupDryRunCountsWithoutExporting(t *testing.T) {
+	cfg := Config{
+		ServiceName:   "svc",
+		Exporter:      ExporterCloudTrace,
+		GCPProjectID:  "project",
+		DryRun:        true,
+		SamplingRatio: Float64(1),
+	}
+	prov, err := Setup(context.Background(), cfg, noopLogger{})
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+
+	tracer := prov.TP.Tracer("test")
+	for i := 0; i < 3; i++ {
+		_, span := tracer.Start(context.Background(), "dry")
+		span.End()
+	}
+	if err := prov.TP.ForceFlush(context.Background()); err != nil {
+		t.Fatalf("force flush failed: %v", err)
+	}
+
+	stats := prov.DryRunStats()
+	if stats.Spans != 3 || stats.Batches == 0 || stats.Bytes == 0 {
+		t.Fatalf("unexpected dry-run stats: %+v", stats)
+	}
+	if err := prov.Shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown failed: %v", err)
+	}
+}
+
+func TestDryRunStatsZeroWhenDisabled(t *testing.T) {
+	prov, err := Setup(context.Background(), Config{ServiceName: "svc"}, nil)
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	defer prov.Shutdown(context.Background())
+	if stats := prov.DryRunStats(); stats != (DryRunStats{}) {
+		t.Fatalf("expected zero stats, got %+v", stats)
+	}
+}
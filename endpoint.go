@@ -0,0 +1,131 @@
+package otelx
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// isLoopbackEndpoint reports whether endpoint points at the local machine: localhost, a loopback
+// IP or a unix domain socket. Empty endpoints are not considered local because the exporters may
+// still pick one up from the environment.
+func isLoopbackEndpoint(endpoint string) bool {
+	endpoint = strings.TrimSpace(endpoint)
+	if endpoint == "" {
+		return false
+	}
+	if strings.HasPrefix(endpoint, "unix:") {
+		return true
+	}
+
+	host := endpoint
+	if strings.Contains(endpoint, "://") {
+		u, err := url.Parse(endpoint)
+		if err != nil {
+			return false
+		}
+		host = u.Host
+	}
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.Trim(host, "[]")
+
+	if strings.EqualFold(host, "localhost") || strings.HasSuffix(strings.ToLower(host), ".localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// unixSocketPath returns the socket path of a unix domain socket endpoint such as
+// unix:///var/run/otel/collector.sock or unix:/var/run/otel/collector.sock.
+func unixSocketPath(endpoint string) (string, bool) {
+	rest, ok := strings.CutPrefix(endpoint, "unix:")
+	if !ok {
+		return "", false
+	}
+	if p, ok := strings.CutPrefix(rest, "//"); ok {
+		rest = p
+	}
+	return rest, rest != ""
+}
+
+// unixSocketClient returns an HTTP client that sends every request over the socket at path, each
+// bounded by timeout. The OTLP/HTTP exporters then address the collector as http://localhost.
+func unixSocketClient(path string, timeout time.Duration) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+		var d net.Dialer
+		return d.DialContext(ctx, "unix", path)
+	}
+	return &http.Client{Transport: transport, Timeout: timeout}
+}
+
+// autoInsecure reports whether ec's OTLP exporter falls back to plaintext because it targets a
+// loopback collector without TLS settings. An https endpoint URL keeps TLS on.
+func (ec ExporterConfig) autoInsecure() bool {
+	return (ec.Exporter == ExporterOTLP || ec.Exporter == ExporterOTLPHTTP) && ec.TLS == nil &&
+		!ec.httpsEndpoint && isLoopbackEndpoint(ec.Endpoint)
+}
+
+// resolveEndpointURL turns an http(s) URL in the Endpoint of an OTLP pipeline into the host:port,
+// Insecure flag and, for OTLP/HTTP, URLPath the exporters expect, e.g.
+// "http://collector:4318/v1/traces". An unset Exporter becomes otlphttp, or otlp for port 4317.
+// URLs that contradict the other settings are left unchanged for validateEndpoint to report.
+func (ec ExporterConfig) resolveEndpointURL() ExporterConfig {
+	if ec.Exporter != "" && ec.Exporter != ExporterOTLP && ec.Exporter != ExporterOTLPHTTP {
+		return ec
+	}
+	u, err := url.Parse(ec.Endpoint)
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return ec
+	}
+	if u.Scheme == "https" && ec.Insecure {
+		return ec
+	}
+	path := strings.TrimSuffix(u.Path, "/")
+	exporter := ec.Exporter
+	if exporter == "" {
+		exporter = ExporterOTLPHTTP
+		if u.Port() == "4317" && path == "" {
+			exporter = ExporterOTLP
+		}
+	}
+	if path != "" && exporter != ExporterOTLPHTTP {
+		return ec
+	}
+	ec.Exporter, ec.Endpoint, ec.Insecure = exporter, u.Host, u.Scheme == "http"
+	ec.httpsEndpoint = u.Scheme == "https"
+	if ec.URLPath == "" {
+		ec.URLPath = path
+	}
+	return ec
+}
+
+// validateEndpoint reports URLs in the Endpoint of an OTLP pipeline that resolveEndpointURL could
+// not turn into exporter settings.
+func (ec ExporterConfig) validateEndpoint() error {
+	if (ec.Exporter != ExporterOTLP && ec.Exporter != ExporterOTLPHTTP) || !strings.Contains(ec.Endpoint, "://") {
+		return nil
+	}
+	if _, ok := unixSocketPath(ec.Endpoint); ok {
+		return nil
+	}
+	u, err := url.Parse(ec.Endpoint)
+	switch {
+	case err != nil || u.Host == "":
+		return fmt.Errorf("invalid endpoint %q", ec.Endpoint)
+	case u.Scheme != "http" && u.Scheme != "https":
+		return fmt.Errorf("unsupported endpoint scheme %q", u.Scheme)
+	case u.Scheme == "https" && ec.Insecure:
+		return fmt.Errorf("insecure conflicts with https endpoint %q", ec.Endpoint)
+	default:
+		return fmt.Errorf("endpoint path %q requires exporter=otlphttp", u.Path)
+	}
+}
@@ -0,0 +1,197 @@
+package otelx
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"google.golang.org/grpc"
+)
+
+func TestIsLoopbackEndpoint(t *testing.T) {
+	cases := map[string]bool{
+		"":                               false,
+		"localhost:4317":                 true,
+		"LOCALHOST:4317":                 true,
+		"127.0.0.1:4317":                 true,
+		"127.0.0.53:4317":                true,
+		"[::1]:4317":                     true,
+		"http://localhost:4318":          true,
+		"unix:///var/run/otel/otlp.sock": true,
+		"otel-collector:4317":            false,
+		"10.0.0.1:4317":                  false,
+		"https://collector.example.com":  false,
+		"localhost.example.com:4317":     false,
+	}
+	for endpoint, want := range cases {
+		if got := isLoopbackEndpoint(endpoint); got != want {
+			t.Errorf("isLoopbackEndpoint(%q) = %v, want %v", endpoint, got, want)
+		}
+	}
+}
+
+func TestUnixSocketPath(t *testing.T) {
+	cases := map[string]string{
+		"unix:///var/run/otel/collector.sock": "/var/run/otel/collector.sock",
+		"unix:/var/run/otel/collector.sock":   "/var/run/otel/collector.sock",
+		"unix://":                             "",
+		"localhost:4317":                      "",
+	}
+	for endpoint, want := range cases {
+		got, ok := unixSocketPath(endpoint)
+		if got != want || ok != (want != "") {
+			t.Errorf("unixSocketPath(%q) = %q, %v, want %q", endpoint, got, ok, want)
+		}
+	}
+}
+
+type traceCollector struct {
+	coltracepb.UnimplementedTraceServiceServer
+	received chan int
+}
+
+func (c *traceCollector) Export(_ context.Context, req *coltracepb.ExportTraceServiceRequest) (*coltracepb.ExportTraceServiceResponse, error) {
+	c.received <- len(req.GetResourceSpans())
+	return &coltracepb.ExportTraceServiceResponse{}, nil
+}
+
+func TestUnixSocketExporters(t *testing.T) {
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: trace.TraceID{1}, SpanID: trace.SpanID{1}, TraceFlags: trace.FlagsSampled,
+	})
+	spans := []sdktrace.ReadOnlySpan{tracetest.SpanStub{Name: "op", SpanContext: sc}.Snapshot()}
+	export := func(exporter ExporterType, sock string) {
+		t.Helper()
+		cfg := Config{ServiceName: "svc", Exporter: exporter, Endpoint: "unix://" + sock}
+		if err := cfg.sanitize().validate(); err != nil {
+			t.Fatalf("%s: validate: %v", exporter, err)
+		}
+		e, err := buildExporter(context.Background(), cfg.primaryExporter(), nil)
+		if err != nil {
+			t.Fatalf("%s: build exporter: %v", exporter, err)
+		}
+		defer e.Shutdown(context.Background())
+		if err := e.ExportSpans(context.Background(), spans); err != nil {
+			t.Fatalf("%s: export over unix socket failed: %v", exporter, err)
+		}
+	}
+
+	dir := t.TempDir()
+	grpcSock := filepath.Join(dir, "grpc.sock")
+	lis, err := net.Listen("unix", grpcSock)
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	collector := &traceCollector{received: make(chan int, 1)}
+	srv := grpc.NewServer()
+	coltracepb.RegisterTraceServiceServer(srv, collector)
+	go srv.Serve(lis)
+	defer srv.Stop()
+	export(ExporterOTLP, grpcSock)
+	if n := <-collector.received; n != 1 {
+		t.Fatalf("expected one resource span over grpc, got %d", n)
+	}
+
+	httpSock := filepath.Join(dir, "http.sock")
+	lis, err = net.Listen("unix", httpSock)
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	paths := make(chan string, 1)
+	httpSrv := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths <- r.URL.Path
+	})}
+	go httpSrv.Serve(lis)
+	defer httpSrv.Close()
+	export(ExporterOTLPHTTP, httpSock)
+	if p := <-paths; p != "/v1/traces" {
+		t.Fatalf("expected /v1/traces over http, got %q", p)
+	}
+}
+
+func TestUnixSocketValidation(t *testing.T) {
+	cases := map[string]Config{
+		"zipkin": {ServiceName: "svc", Exporter: ExporterZipkin, Endpoint: "unix:///tmp/c.sock"},
+		"tls":    {ServiceName: "svc", Exporter: ExporterOTLP, Endpoint: "unix:///tmp/c.sock", TLS: &TLSConfig{ServerName: "x"}},
+	}
+	for name, cfg := range cases {
+		if _, err := Setup(context.Background(), cfg, nil); err == nil {
+			t.Fatalf("%s: expected validation error", name)
+		}
+	}
+}
+
+func TestResolveEndpointURL(t *testing.T) {
+	cases := []struct {
+		in   ExporterConfig
+		want ExporterConfig
+	}{
+		{
+			ExporterConfig{Exporter: ExporterOTLP, Endpoint: "https://collector.example.com:4317"},
+			ExporterConfig{Exporter: ExporterOTLP, Endpoint: "collector.example.com:4317", httpsEndpoint: true},
+		},
+		{
+			ExporterConfig{Endpoint: "http://collector:4318/v1/traces"},
+			ExporterConfig{Exporter: ExporterOTLPHTTP, Endpoint: "collector:4318", URLPath: "/v1/traces", Insecure: true},
+		},
+		{
+			ExporterConfig{Endpoint: "http://collector:4317"},
+			ExporterConfig{Exporter: ExporterOTLP, Endpoint: "collector:4317", Insecure: true},
+		},
+		{
+			ExporterConfig{Exporter: ExporterOTLPHTTP, Endpoint: "https://collector/otlp/v1/traces", URLPath: "/custom"},
+			ExporterConfig{Exporter: ExporterOTLPHTTP, Endpoint: "collector", URLPath: "/custom", httpsEndpoint: true},
+		},
+		// Left unchanged: validateEndpoint reports the conflict.
+		{
+			ExporterConfig{Exporter: ExporterOTLP, Endpoint: "http://collector:4318/v1/traces"},
+			ExporterConfig{Exporter: ExporterOTLP, Endpoint: "http://collector:4318/v1/traces"},
+		},
+		// Other exporters take full URLs as they are.
+		{
+			ExporterConfig{Exporter: ExporterZipkin, Endpoint: "http://zipkin:9411/api/v2/spans"},
+			ExporterConfig{Exporter: ExporterZipkin, Endpoint: "http://zipkin:9411/api/v2/spans"},
+		},
+	}
+	for _, c := range cases {
+		if got := c.in.resolveEndpointURL(); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("resolveEndpointURL(%+v) = %+v, want %+v", c.in, got, c.want)
+		}
+	}
+
+	cfg := Config{ServiceName: "svc", Endpoint: " http://localhost:4318/v1/traces "}.sanitize()
+	if cfg.Exporter != ExporterOTLPHTTP || cfg.Endpoint != "localhost:4318" || cfg.URLPath != "/v1/traces" || !cfg.Insecure {
+		t.Fatalf("unexpected top-level resolution %+v", cfg.primaryExporter())
+	}
+}
+
+func TestHTTPSLoopbackEndpointKeepsTLS(t *testing.T) {
+	cfg := Config{ServiceName: "svc", Exporter: ExporterOTLP, Endpoint: "https://localhost:4317"}.sanitize()
+	if desc := describeExporter(cfg.primaryExporter(), false); desc.Insecure {
+		t.Fatalf("expected an https URL to keep TLS on for localhost, got %+v", desc)
+	}
+	cfg = Config{ServiceName: "svc", Exporter: ExporterOTLP, Endpoint: "localhost:4317"}.sanitize()
+	if desc := describeExporter(cfg.primaryExporter(), false); !desc.Insecure {
+		t.Fatalf("expected a bare loopback endpoint to default to plaintext, got %+v", desc)
+	}
+}
+
+func TestEndpointURLValidation(t *testing.T) {
+	cases := map[string]Config{
+		"path-grpc":      {ServiceName: "svc", Exporter: ExporterOTLP, Endpoint: "http://collector:4318/v1/traces"},
+		"https-insecure": {ServiceName: "svc", Exporter: ExporterOTLP, Endpoint: "https://collector:4317", Insecure: true},
+		"scheme":         {ServiceName: "svc", Exporter: ExporterOTLPHTTP, Endpoint: "ftp://collector:4318"},
+	}
+	for name, cfg := range cases {
+		if _, err := Setup(context.Background(), cfg, nil); err == nil {
+			t.Fatalf("%s: expected validation error", name)
+		}
+	}
+}
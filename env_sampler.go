@@ -0,0 +1,93 @@
+package otelx
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Standard OpenTelemetry sampler environment variables, honoured when Config.SamplingRatio is unset.
+const (
+	envTracesSampler    = "OTEL_TRACES_SAMPLER"
+	envTracesSamplerArg = "OTEL_TRACES_SAMPLER_ARG"
+)
+
+// applySamplerEnv fills SamplingRatio (and RemoteSampling for jaeger_remote) from
+// OTEL_TRACES_SAMPLER / OTEL_TRACES_SAMPLER_ARG when SamplingRatio is unset. ignoreParent reports
+// a sampler without the parentbased_ prefix, which decides on every span regardless of its
+// parent. Invalid values leave cfg unchanged and are returned for Setup to log.
+func (cfg Config) applySamplerEnv(getenv func(string) string) (_ Config, ignoreParent bool, _ error) {
+	name := strings.ToLower(strings.TrimSpace(getenv(envTracesSampler)))
+	if name == "" || cfg.SamplingRatio != nil {
+		return cfg, false, nil
+	}
+	arg := strings.TrimSpace(getenv(envTracesSamplerArg))
+	base, parentBased := strings.CutPrefix(name, "parentbased_")
+
+	switch base {
+	case "always_on":
+		cfg.SamplingRatio = Float64(1)
+	case "always_off":
+		cfg.SamplingRatio = Float64(0)
+	case "traceidratio":
+		ratio := 1.0
+		if arg != "" {
+			var err error
+			if ratio, err = strconv.ParseFloat(arg, 64); err != nil || ratio < 0 || ratio > 1 {
+				return cfg, false, fmt.Errorf("%s=%q must be a ratio within [0,1]", envTracesSamplerArg, arg)
+			}
+		}
+		cfg.SamplingRatio = Float64(ratio)
+	case "jaeger_remote":
+		if cfg.RemoteSampling != nil || cfg.SamplingTargetPerMinute > 0 {
+			return cfg, false, fmt.Errorf("%s=%s conflicts with remoteSampling or samplingTargetPerMinute", envTracesSampler, name)
+		}
+		remote, ratio, err := parseJaegerRemoteArg(arg)
+		if err != nil {
+			return cfg, false, fmt.Errorf("%s: %w", envTracesSamplerArg, err)
+		}
+		cfg.RemoteSampling = &remote
+		if ratio != nil {
+			cfg.SamplingRatio = ratio
+		}
+	default:
+		return cfg, false, fmt.Errorf("unsupported %s %q", envTracesSampler, name)
+	}
+	return cfg, !parentBased, nil
+}
+
+// parseJaegerRemoteArg parses "endpoint=...,pollingIntervalMs=...,initialSamplingRate=...".
+func parseJaegerRemoteArg(arg string) (RemoteSamplingConfig, *float64, error) {
+	var (
+		remote RemoteSamplingConfig
+		ratio  *float64
+	)
+	for _, part := range strings.Split(arg, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		value = strings.TrimSpace(value)
+		switch strings.TrimSpace(key) {
+		case "":
+		case "endpoint":
+			remote.Endpoint = value
+		case "pollingIntervalMs":
+			ms, err := strconv.Atoi(value)
+			if err != nil || ms <= 0 {
+				return remote, nil, fmt.Errorf("pollingIntervalMs must be a positive integer, got %q", value)
+			}
+			remote.RefreshInterval = time.Duration(ms) * time.Millisecond
+		case "initialSamplingRate":
+			r, err := strconv.ParseFloat(value, 64)
+			if err != nil || r < 0 || r > 1 {
+				return remote, nil, fmt.Errorf("initialSamplingRate must be within [0,1], got %q", value)
+			}
+			ratio = Float64(r)
+		default:
+			return remote, nil, fmt.Errorf("unknown key %q", key)
+		}
+	}
+	if err := remote.validate(); err != nil {
+		return remote, nil, err
+	}
+	return remote, ratio, nil
+}
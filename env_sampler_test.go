@@ -0,0 +1,94 @@
+package otelx
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestApplySamplerEnv(t *testing.T) {
+	cases := []struct {
+		sampler, arg string
+		ratio        *float64
+		ignoreParent bool
+		err          string
+	}{
+		{sampler: "always_on", ratio: Float64(1), ignoreParent: true},
+		{sampler: "parentbased_always_off", ratio: Float64(0)},
+		{sampler: "traceidratio", arg: "0.25", ratio: Float64(0.25), ignoreParent: true},
+		{sampler: "ParentBased_TraceIDRatio", ratio: Float64(1)},
+		{sampler: "parentbased_traceidratio", arg: "2", err: "must be a ratio within [0,1]"},
+		{sampler: "xray", err: `unsupported OTEL_TRACES_SAMPLER "xray"`},
+	}
+	for _, tc := range cases {
+		env := map[string]string{envTracesSampler: tc.sampler, envTracesSamplerArg: tc.arg}
+		cfg, ignoreParent, err := Config{}.applySamplerEnv(func(k string) string { return env[k] })
+		if tc.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tc.err) || cfg.SamplingRatio != nil {
+				t.Fatalf("%s: expected %q error and no ratio, got %v", tc.sampler, tc.err, err)
+			}
+			continue
+		}
+		if err != nil || cfg.SamplingRatio == nil || *cfg.SamplingRatio != *tc.ratio || ignoreParent != tc.ignoreParent {
+			t.Fatalf("%s: unexpected result ratio=%v ignoreParent=%v err=%v", tc.sampler, cfg.SamplingRatio, ignoreParent, err)
+		}
+	}
+
+	env := map[string]string{envTracesSampler: "always_on"}
+	cfg, _, _ := Config{SamplingRatio: Float64(0.5)}.applySamplerEnv(func(k string) string { return env[k] })
+	if *cfg.SamplingRatio != 0.5 {
+		t.Fatalf("expected an explicit SamplingRatio to win, got %v", *cfg.SamplingRatio)
+	}
+}
+
+func TestApplySamplerEnvJaegerRemote(t *testing.T) {
+	env := map[string]string{
+		envTracesSampler:    "parentbased_jaeger_remote",
+		envTracesSamplerArg: "endpoint=http://jaeger:5778/sampling, pollingIntervalMs=5000, initialSamplingRate=0.25",
+	}
+	cfg, ignoreParent, err := Config{}.applySamplerEnv(func(k string) string { return env[k] })
+	if err != nil || ignoreParent {
+		t.Fatalf("unexpected result ignoreParent=%v err=%v", ignoreParent, err)
+	}
+	want := RemoteSamplingConfig{Endpoint: "http://jaeger:5778/sampling", RefreshInterval: 5 * time.Second}
+	if cfg.RemoteSampling == nil || *cfg.RemoteSampling != want || *cfg.SamplingRatio != 0.25 {
+		t.Fatalf("unexpected remote sampling %+v ratio %v", cfg.RemoteSampling, cfg.SamplingRatio)
+	}
+
+	env[envTracesSamplerArg] = "pollingIntervalMs=5000"
+	if _, _, err := (Config{}).applySamplerEnv(func(k string) string { return env[k] }); err == nil || !strings.Contains(err.Error(), "endpoint is required") {
+		t.Fatalf("expected missing endpoint error, got %v", err)
+	}
+}
+
+func TestSetupHonoursSamplerEnv(t *testing.T) {
+	t.Setenv(envTracesSampler, "traceidratio")
+	t.Setenv(envTracesSamplerArg, "0.5")
+	logger := &recordingLogger{}
+	prov, err := Setup(context.Background(), Config{ServiceName: "svc", Exporter: ExporterMemory}, logger)
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	defer prov.Shutdown(context.Background())
+	if got := prov.Describe().Sampler; strings.Contains(got, "ParentBased") || !strings.Contains(got, "TraceIDRatioBased{0.5}") {
+		t.Fatalf("expected a non parent-based 0.5 ratio sampler, got %q", got)
+	}
+
+	t.Setenv(envTracesSampler, "bogus")
+	prov, err = Setup(context.Background(), Config{ServiceName: "svc", Exporter: ExporterMemory}, logger)
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	defer prov.Shutdown(context.Background())
+	if got := prov.Describe().Sampler; !strings.Contains(got, "ParentBased") {
+		t.Fatalf("expected the default sampler, got %q", got)
+	}
+	found := false
+	for _, e := range logger.Entries() {
+		found = found || e == "warn:otelx.sampler.env.invalid"
+	}
+	if !found {
+		t.Fatalf("expected otelx.sampler.env.invalid to be logged")
+	}
+}
@@ -0,0 +1,66 @@
+package otelx
+
+import "strings"
+
+// EnvironmentOverride replaces parts of a Config when Config.Environment selects it, so one
+// committed config file can serve dev, staging and prod. Unset fields keep the base value.
+type EnvironmentOverride struct {
+	Exporter      ExporterType      `json:"exporter"`
+	Endpoint      string            `json:"endpoint"`
+	URLPath       string            `json:"urlPath"`
+	Insecure      *bool             `json:"insecure"`
+	Headers       map[string]string `json:"headers"`
+	SamplingRatio *float64          `json:"samplingRatio"`
+	// Exporters replaces the base pipelines, including a single top-level exporter. Setting
+	// Exporter instead switches back to a single exporter and drops the base Exporters.
+	Exporters []ExporterConfig `json:"exporters"`
+}
+
+// resolveEnvironment applies the override for cfg.Environment (matched case-insensitively) and
+// drops Config.Environments, so later steps only see the effective settings.
+func (cfg Config) resolveEnvironment() Config {
+	overrides := cfg.Environments
+	cfg.Environments = nil
+	for env, o := range overrides {
+		if !strings.EqualFold(strings.TrimSpace(env), cfg.Environment) {
+			continue
+		}
+		if o.Exporter != "" {
+			cfg.Exporter = o.Exporter
+			cfg.Exporters = nil
+		}
+		if o.Endpoint != "" {
+			cfg.Endpoint = o.Endpoint
+		}
+		if o.URLPath != "" {
+			cfg.URLPath = o.URLPath
+		}
+		if o.Insecure != nil {
+			cfg.Insecure = *o.Insecure
+		}
+		if o.Headers != nil {
+			cfg.Headers = o.Headers
+		}
+		if o.SamplingRatio != nil {
+			cfg.SamplingRatio = o.SamplingRatio
+		}
+		if len(o.Exporters) > 0 {
+			cfg = cfg.withoutPrimaryExporter()
+			cfg.Exporters = o.Exporters
+		}
+		break
+	}
+	return cfg
+}
+
+// withoutPrimaryExporter clears the top-level exporter fields listed by primaryExporter.
+func (cfg Config) withoutPrimaryExporter() Config {
+	cfg.Exporter, cfg.Preset, cfg.APIKey = "", "", ""
+	cfg.Endpoint, cfg.URLPath, cfg.Insecure = "", "", false
+	cfg.GCPProjectID, cfg.AWSRegion, cfg.AzureConnectionString = "", "", ""
+	cfg.FilePath, cfg.FileMaxBytes, cfg.FileMaxBackups = "", 0, 0
+	cfg.ExportRatio, cfg.Headers = nil, nil
+	cfg.OTLPRetry, cfg.Compression, cfg.TLS, cfg.Failover, cfg.ExporterTimeout = nil, "", nil, nil, 0
+	cfg.httpsEndpoint = false
+	return cfg
+}
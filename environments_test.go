@@ -0,0 +1,69 @@
+package otelx
+
+import (
+	"context"
+	"testing"
+)
+
+func TestEnvironmentOverrides(t *testing.T) {
+	base := Config{
+		ServiceName:   "svc",
+		Exporter:      ExporterStdout,
+		SamplingRatio: Float64(1),
+		Environments: map[string]EnvironmentOverride{
+			"staging": {Exporter: ExporterOTLP, Endpoint: "collector.staging:4317", Insecure: Bool(true)},
+			"Prod": {
+				SamplingRatio: Float64(0.05),
+				Exporters: []ExporterConfig{
+					{Exporter: ExporterOTLP, Endpoint: "collector.prod:4317"},
+					{Exporter: ExporterCloudTrace, GCPProjectID: "lingo-prod"},
+				},
+			},
+		},
+	}
+
+	dev := base
+	dev.Environment = "dev"
+	if got := dev.sanitize(); got.Exporter != ExporterStdout || *got.SamplingRatio != 1 || got.Environments != nil {
+		t.Fatalf("expected base settings for dev, got %+v", got)
+	}
+
+	staging := base
+	staging.Environment = "staging"
+	got := staging.sanitize()
+	if got.Exporter != ExporterOTLP || got.Endpoint != "collector.staging:4317" || !got.Insecure || *got.SamplingRatio != 1 {
+		t.Fatalf("expected staging override, got %+v", got)
+	}
+
+	prod := base
+	prod.Environment = "prod"
+	got = prod.sanitize()
+	if got.Exporter != "" || len(got.Exporters) != 2 || *got.SamplingRatio != 0.05 {
+		t.Fatalf("expected prod override, got %+v", got)
+	}
+	if err := got.validate(); err != nil {
+		t.Fatalf("expected prod override to validate, got %v", err)
+	}
+}
+
+func TestSetupAppliesEnvironmentOverride(t *testing.T) {
+	cfg := Config{
+		ServiceName: "svc",
+		Environment: "test",
+		Exporter:    ExporterStdout,
+		Environments: map[string]EnvironmentOverride{
+			"test": {Exporter: ExporterMemory, SamplingRatio: Float64(1)},
+		},
+	}
+	prov, err := Setup(context.Background(), cfg, nil)
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	defer prov.Shutdown(context.Background())
+
+	_, span := prov.TP.Tracer("test").Start(context.Background(), "op")
+	span.End()
+	if spans := prov.RecordedSpans(); len(spans) != 1 {
+		t.Fatalf("expected memory exporter from override, got %d spans", len(spans))
+	}
+}
@@ -0,0 +1,106 @@
+package otelx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	logx "github.com/bionicotaku/lingo-utils-logx"
+)
+
+// sdkErrorInterval suppresses repeats of the same SDK error, e.g. every batch failing while the
+// collector is down, so they cannot flood the logs; the next report carries the suppressed count.
+const sdkErrorInterval = 10 * time.Second
+
+// sdkErrorMaxKeys bounds the distinct errors remembered for suppression.
+const sdkErrorMaxKeys = 64
+
+// WithSDKErrorLogging installs a global otel.ErrorHandler that reports errors raised by the SDK
+// and exporters (failed exports, dropped telemetry, ...) through the logx.Logger passed to Setup as
+// otelx.sdk.error entries, instead of the default stderr output. Repeats of the same error are
+// reported at most every 10s with a suppressed count. Without a logger the option has no effect.
+func WithSDKErrorLogging() Option {
+	return func(o *setupOptions) {
+		o.sdkErrors = true
+	}
+}
+
+type sdkErrorSeen struct {
+	last       time.Time
+	suppressed int
+}
+
+// logxErrorHandler implements otel.ErrorHandler on top of a logx.Logger.
+type logxErrorHandler struct {
+	logger logx.Logger
+	now    func() time.Time
+
+	mu   sync.Mutex
+	seen map[string]*sdkErrorSeen
+}
+
+func newLogxErrorHandler(logger logx.Logger) *logxErrorHandler {
+	return &logxErrorHandler{logger: logger, now: time.Now, seen: map[string]*sdkErrorSeen{}}
+}
+
+func (h *logxErrorHandler) Handle(err error) {
+	if err == nil {
+		return
+	}
+	msg := err.Error()
+	now := h.now()
+	h.mu.Lock()
+	seen, ok := h.seen[msg]
+	if ok && now.Sub(seen.last) < sdkErrorInterval {
+		seen.suppressed++
+		h.mu.Unlock()
+		return
+	}
+	suppressed := 0
+	if ok {
+		suppressed = seen.suppressed
+	} else if len(h.seen) >= sdkErrorMaxKeys {
+		clear(h.seen)
+	}
+	h.seen[msg] = &sdkErrorSeen{last: now}
+	h.mu.Unlock()
+
+	attrs := []logx.Attr{
+		logx.String("signal", errorSignal(msg)),
+		logx.String("error.type", errorType(err)),
+	}
+	if suppressed > 0 {
+		attrs = append(attrs, logx.Int("suppressed", suppressed))
+	}
+	h.logger.Error(context.Background(), "otelx.sdk.error", err, attrs...)
+}
+
+// errorSignal guesses the telemetry signal an SDK error belongs to from its message, e.g.
+// "traces export: context deadline exceeded".
+func errorSignal(msg string) string {
+	msg = strings.ToLower(msg)
+	switch {
+	case strings.Contains(msg, "trace") || strings.Contains(msg, "span"):
+		return "traces"
+	case strings.Contains(msg, "metric"):
+		return "metrics"
+	case strings.Contains(msg, "log"):
+		return "logs"
+	default:
+		return "unknown"
+	}
+}
+
+// errorType names the innermost wrapped error's type.
+func errorType(err error) string {
+	for {
+		next := errors.Unwrap(err)
+		if next == nil {
+			return fmt.Sprintf("%T", err)
+		}
+		err = next
+	}
+}
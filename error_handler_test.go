@@ -0,0 +1,65 @@
+package otelx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+)
+
+func TestWithSDKErrorLogging(t *testing.T) {
+	previous := otel.GetErrorHandler()
+	defer otel.SetErrorHandler(previous)
+
+	rec := &recordingLogger{}
+	prov, err := Setup(context.Background(), Config{ServiceName: "svc", Exporter: ExporterMemory}, rec, WithSDKErrorLogging())
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	defer prov.Shutdown(context.Background())
+
+	otel.Handle(errors.New("traces export: connection refused"))
+	found := false
+	for _, e := range rec.Entries() {
+		found = found || e == "error:otelx.sdk.error"
+	}
+	if !found {
+		t.Fatalf("expected the SDK error to be logged, got %v", rec.Entries())
+	}
+}
+
+func TestLogxErrorHandlerSuppressesRepeats(t *testing.T) {
+	rec := &recordingLogger{}
+	h := newLogxErrorHandler(rec)
+	now := time.Unix(0, 0)
+	h.now = func() time.Time { return now }
+
+	exportErr := fmt.Errorf("traces export: %w", context.DeadlineExceeded)
+	h.Handle(exportErr)
+	h.Handle(exportErr)
+	h.Handle(errors.New("failed to upload metrics: 503"))
+	now = now.Add(sdkErrorInterval)
+	h.Handle(exportErr)
+
+	if got := len(rec.Entries()); got != 3 {
+		t.Fatalf("expected 3 log entries, got %v", rec.Entries())
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if seen := h.seen[exportErr.Error()]; seen.suppressed != 0 || !seen.last.Equal(now) {
+		t.Fatalf("expected the suppression window to restart, got %+v", seen)
+	}
+
+	if got := errorSignal(exportErr.Error()); got != "traces" {
+		t.Fatalf("expected traces signal, got %q", got)
+	}
+	if got := errorSignal("failed to upload metrics: 503"); got != "metrics" {
+		t.Fatalf("expected metrics signal, got %q", got)
+	}
+	if got := errorType(exportErr); got != "context.deadlineExceededError" {
+		t.Fatalf("expected the innermost error type, got %q", got)
+	}
+}
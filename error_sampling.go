@@ -0,0 +1,63 @@
+package otelx
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// ErrorSampledKey marks spans exported by WithAlwaysSampleErrors although head sampling dropped
+// their trace, so the backend can tell the trace is partial.
+const ErrorSampledKey = attribute.Key("otelx.error_sampled")
+
+// errorSamplingProcessor wraps the export processors, which skip unsampled spans, and hands them
+// spans that ended with an Error status as sampled. Only the failed spans themselves are kept;
+// their unsampled parents and siblings have already been discarded.
+type errorSamplingProcessor struct {
+	next []sdktrace.SpanProcessor
+}
+
+func newErrorSamplingProcessor(next []sdktrace.SpanProcessor) *errorSamplingProcessor {
+	return &errorSamplingProcessor{next: next}
+}
+
+func (p *errorSamplingProcessor) OnStart(ctx context.Context, s sdktrace.ReadWriteSpan) {
+	for _, next := range p.next {
+		next.OnStart(ctx, s)
+	}
+}
+
+func (p *errorSamplingProcessor) OnEnd(s sdktrace.ReadOnlySpan) {
+	var span sdktrace.ReadOnlySpan = s
+	if sc := s.SpanContext(); !sc.IsSampled() && s.Status().Code == codes.Error {
+		o := override(s)
+		o.spanContext = sc.WithTraceFlags(sc.TraceFlags().WithSampled(true))
+		o.addAttributes(ErrorSampledKey.Bool(true))
+		span = o
+	}
+	for _, next := range p.next {
+		next.OnEnd(span)
+	}
+}
+
+func (p *errorSamplingProcessor) Shutdown(ctx context.Context) error {
+	var firstErr error
+	for _, next := range p.next {
+		if err := next.Shutdown(ctx); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func (p *errorSamplingProcessor) ForceFlush(ctx context.Context) error {
+	var firstErr error
+	for _, next := range p.next {
+		if err := next.ForceFlush(ctx); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
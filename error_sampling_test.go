@@ -0,0 +1,47 @@
+package otelx
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.opentelemetry.io/otel/codes"
+)
+
+func TestAlwaysSampleErrors(t *testing.T) {
+	cfg := Config{ServiceName: "svc", Exporter: ExporterMemory, SamplingRatio: Float64(0)}
+	prov, err := Setup(context.Background(), cfg, nil, WithAlwaysSampleErrors())
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	defer prov.Shutdown(context.Background())
+
+	tracer := prov.TP.Tracer("test")
+	ctx, root := tracer.Start(context.Background(), "checkout")
+	_, ok := tracer.Start(ctx, "load-cart")
+	ok.End()
+	_, failed := tracer.Start(ctx, "charge-card")
+	failed.RecordError(errors.New("declined"))
+	failed.SetStatus(codes.Error, "declined")
+	failed.End()
+	root.End()
+
+	spans := prov.RecordedSpans()
+	if len(spans) != 1 || spans[0].Name != "charge-card" {
+		t.Fatalf("expected only the failed span to be exported, got %+v", spans)
+	}
+	if !spans[0].SpanContext.IsSampled() || !spanHasAttribute(spans[0].Attributes, ErrorSampledKey, "true") {
+		t.Fatalf("expected a sampled span tagged %s, got %+v", ErrorSampledKey, spans[0])
+	}
+	if spans[0].Parent.SpanID() != root.SpanContext().SpanID() {
+		t.Fatalf("expected the failed span to keep its parent")
+	}
+	if root.SpanContext().IsSampled() {
+		t.Fatalf("expected the head sampling decision to be unchanged")
+	}
+
+	processors := prov.Describe().Processors
+	if len(processors) < 2 || processors[len(processors)-2].Name != "alwaysSampleErrors" {
+		t.Fatalf("expected alwaysSampleErrors before batch, got %+v", processors)
+	}
+}
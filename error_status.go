@@ -0,0 +1,116 @@
+package otelx
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+)
+
+// ErrorMapping decides how RecordError reports errors matched by Match, typically expected
+// business errors that should not turn spans red.
+type ErrorMapping struct {
+	// Match selects the errors this mapping applies to; see ErrorIs and ErrorAs.
+	Match func(error) bool
+	// Status is set on the span. codes.Unset leaves the decision to the instrumentation, codes.Ok
+	// also overrides an error status set later by otelhttp/otelgrpc.
+	Status codes.Code
+	// Attributes are added to the span, e.g. a domain error code.
+	Attributes []attribute.KeyValue
+	// SkipEvent stops the exception event from being recorded.
+	SkipEvent bool
+}
+
+var errorMappings struct {
+	sync.RWMutex
+	list []ErrorMapping
+}
+
+// RegisterErrorMappings adds process-wide error mappings, usually during start-up. The first
+// registered mapping that matches an error wins.
+func RegisterErrorMappings(mappings ...ErrorMapping) {
+	errorMappings.Lock()
+	defer errorMappings.Unlock()
+	for _, m := range mappings {
+		if m.Match != nil {
+			errorMappings.list = append(errorMappings.list, m)
+		}
+	}
+}
+
+// ErrorIs matches errors for which errors.Is(err, target) holds.
+func ErrorIs(target error) func(error) bool {
+	return func(err error) bool { return errors.Is(err, target) }
+}
+
+// ErrorAs matches errors for which errors.As finds a T in the chain.
+func ErrorAs[T error]() func(error) bool {
+	return func(err error) bool {
+		var target T
+		return errors.As(err, &target)
+	}
+}
+
+func lookupErrorMapping(err error) (ErrorMapping, bool) {
+	errorMappings.RLock()
+	defer errorMappings.RUnlock()
+	for _, m := range errorMappings.list {
+		if m.Match(err) {
+			return m, true
+		}
+	}
+	return ErrorMapping{}, false
+}
+
+// RecordError records err on the span in ctx. Errors matching a registered ErrorMapping get the
+// mapping's status and attributes; all others are recorded as an exception with status Error.
+func RecordError(ctx context.Context, err error, opts ...trace.EventOption) {
+	recordSpanError(trace.SpanFromContext(ctx), err, opts...)
+}
+
+func recordSpanError(span trace.Span, err error, opts ...trace.EventOption) {
+	if err == nil {
+		return
+	}
+	m, ok := lookupErrorMapping(err)
+	if !ok {
+		span.RecordError(err, opts...)
+		span.SetStatus(codes.Error, err.Error())
+		return
+	}
+	if !m.SkipEvent {
+		span.RecordError(err, opts...)
+	}
+	if len(m.Attributes) > 0 {
+		span.SetAttributes(m.Attributes...)
+	}
+	switch m.Status {
+	case codes.Error:
+		span.SetStatus(codes.Error, err.Error())
+	case codes.Ok:
+		span.SetStatus(codes.Ok, "")
+	}
+}
+
+// GRPCErrorUnaryInterceptor applies RecordError to errors returned by unary handlers, so
+// registered error mappings take effect on gRPC server spans.
+func GRPCErrorUnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		resp, err := handler(ctx, req)
+		RecordError(ctx, err)
+		return resp, err
+	}
+}
+
+// GRPCErrorStreamInterceptor is the streaming counterpart of GRPCErrorUnaryInterceptor.
+func GRPCErrorStreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		err := handler(srv, ss)
+		RecordError(ss.Context(), err)
+		return err
+	}
+}
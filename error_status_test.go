@@ -0,0 +1,106 @@
+package otelx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var errNotFound = errors.New("not found")
+
+type quotaError struct{ code string }
+
+func (e *quotaError) Error() string { return "quota exceeded: " + e.code }
+
+func withErrorMappings(t *testing.T, mappings ...ErrorMapping) {
+	t.Helper()
+	errorMappings.Lock()
+	saved := errorMappings.list
+	errorMappings.list = nil
+	errorMappings.Unlock()
+	RegisterErrorMappings(mappings...)
+	t.Cleanup(func() {
+		errorMappings.Lock()
+		errorMappings.list = saved
+		errorMappings.Unlock()
+	})
+}
+
+func TestRecordErrorAppliesMappings(t *testing.T) {
+	withErrorMappings(t,
+		ErrorMapping{Match: ErrorIs(errNotFound), Attributes: []attribute.KeyValue{attribute.String("app.error_code", "NOT_FOUND")}, SkipEvent: true},
+		ErrorMapping{Match: ErrorAs[*quotaError](), Status: codes.Ok},
+	)
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	defer tp.Shutdown(context.Background())
+
+	for _, err := range []error{
+		fmt.Errorf("load user: %w", errNotFound),
+		&quotaError{code: "daily"},
+		errors.New("boom"),
+	} {
+		ctx, span := tp.Tracer("test").Start(context.Background(), "op")
+		RecordError(ctx, err)
+		span.End()
+	}
+
+	ended := recorder.Ended()
+	if s := ended[0]; s.Status().Code != codes.Unset || len(s.Events()) != 0 || !spanHasAttribute(s.Attributes(), "app.error_code", "NOT_FOUND") {
+		t.Fatalf("unexpected not-found span: status=%v events=%v attrs=%v", s.Status(), s.Events(), s.Attributes())
+	}
+	if s := ended[1]; s.Status().Code != codes.Ok || len(s.Events()) != 1 {
+		t.Fatalf("unexpected quota span: status=%v events=%v", s.Status(), s.Events())
+	}
+	if s := ended[2]; s.Status().Code != codes.Error || s.Status().Description != "boom" {
+		t.Fatalf("expected unmapped error to mark the span failed, got %v", s.Status())
+	}
+}
+
+func TestHTTPErrorRecordsOnRequestSpan(t *testing.T) {
+	withErrorMappings(t, ErrorMapping{Match: ErrorIs(errNotFound), Status: codes.Ok})
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	defer tp.Shutdown(context.Background())
+
+	ctx, span := tp.Tracer("test").Start(context.Background(), "GET /users")
+	rec := httptest.NewRecorder()
+	HTTPError(rec, httptest.NewRequest(http.MethodGet, "/users", nil).WithContext(ctx), errNotFound, http.StatusNotFound)
+	span.End()
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", rec.Code)
+	}
+	if got := recorder.Ended()[0].Status().Code; got != codes.Ok {
+		t.Fatalf("expected mapped status Ok, got %v", got)
+	}
+}
+
+func TestGRPCErrorUnaryInterceptor(t *testing.T) {
+	withErrorMappings(t, ErrorMapping{Match: ErrorIs(errNotFound), Status: codes.Ok})
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	defer tp.Shutdown(context.Background())
+
+	ctx, span := tp.Tracer("test").Start(context.Background(), "rpc", trace.WithSpanKind(trace.SpanKindServer))
+	_, err := GRPCErrorUnaryInterceptor()(ctx, nil, nil, func(context.Context, any) (any, error) {
+		return nil, errNotFound
+	})
+	span.End()
+
+	if !errors.Is(err, errNotFound) {
+		t.Fatalf("expected handler error to be returned, got %v", err)
+	}
+	if got := recorder.Ended()[0].Status().Code; got != codes.Ok {
+		t.Fatalf("expected mapped status Ok, got %v", got)
+	}
+}
@@ -0,0 +1,88 @@
+package otelx
+
+import (
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// EventsDroppedKey records how many span events were removed by the event limiter.
+const EventsDroppedKey = attribute.Key("otelx.events.dropped")
+
+// EventLimits bounds the span events that reach the exporter.
+type EventLimits struct {
+	// PerSpan is the number of leading events kept verbatim on every span.
+	PerSpan int
+	// SampleEvery keeps every Nth event after the first PerSpan ones; zero drops them all.
+	SampleEvery int
+	// PerSecond caps events exported per second across all spans of a pipeline; zero means
+	// unlimited.
+	PerSecond int
+}
+
+func (l EventLimits) enabled() bool {
+	return l.PerSpan > 0 || l.PerSecond > 0
+}
+
+// eventLimiter trims span events to EventLimits and annotates trimmed spans with EventsDroppedKey.
+type eventLimiter struct {
+	limits EventLimits
+	now    func() time.Time
+
+	mu          sync.Mutex
+	windowStart time.Time
+	windowUsed  int
+}
+
+func newEventLimiter(limits EventLimits) *eventLimiter {
+	return &eventLimiter{limits: limits, now: time.Now}
+}
+
+func (e *eventLimiter) limit(span sdktrace.ReadOnlySpan) sdktrace.ReadOnlySpan {
+	events := span.Events()
+	if len(events) == 0 {
+		return span
+	}
+
+	kept := events
+	if e.limits.PerSpan > 0 && len(events) > e.limits.PerSpan {
+		kept = make([]sdktrace.Event, 0, e.limits.PerSpan)
+		for i, ev := range events {
+			if i < e.limits.PerSpan || (e.limits.SampleEvery > 0 && (i-e.limits.PerSpan+1)%e.limits.SampleEvery == 0) {
+				kept = append(kept, ev)
+			}
+		}
+	}
+	if granted := e.reserve(len(kept)); granted < len(kept) {
+		kept = kept[:granted]
+	}
+
+	dropped := len(events) - len(kept)
+	if dropped == 0 {
+		return span
+	}
+	o := override(span)
+	o.events = append(make([]sdktrace.Event, 0, len(kept)), kept...)
+	o.droppedEvents += dropped
+	o.addAttributes(EventsDroppedKey.Int(dropped))
+	return o
+}
+
+// reserve takes up to n events from the per-second budget and returns how many were granted.
+func (e *eventLimiter) reserve(n int) int {
+	if e.limits.PerSecond <= 0 {
+		return n
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	now := e.now()
+	if now.Sub(e.windowStart) >= time.Second {
+		e.windowStart = now
+		e.windowUsed = 0
+	}
+	granted := min(n, e.limits.PerSecond-e.windowUsed)
+	e.windowUsed += granted
+	return granted
+}
@@ -0,0 +1,95 @@
+package otelx
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestEventLimitExporterKeepsFirstEventsAndSamples(t *testing.T) {
+	mem := tracetest.NewInMemoryExporter()
+	exporter := newTransformExporter(mem, newEventLimiter(EventLimits{PerSpan: 3, SampleEvery: 5}).limit)
+
+	span := spanWithEvents(t, 20)
+	if err := exporter.ExportSpans(context.Background(), []sdktrace.ReadOnlySpan{span}); err != nil {
+		t.Fatalf("export failed: %v", err)
+	}
+
+	got := mem.GetSpans()[0]
+	// events 0,1,2 kept verbatim, then every 5th of the remaining 17: 7, 12, 17
+	if len(got.Events) != 6 {
+		t.Fatalf("expected 6 events, got %d", len(got.Events))
+	}
+	if got.Events[3].Name != "e7" {
+		t.Fatalf("expected sampled event e7, got %s", got.Events[3].Name)
+	}
+	if got.DroppedEvents != 14 {
+		t.Fatalf("expected 14 dropped events, got %d", got.DroppedEvents)
+	}
+	if !spanHasAttribute(got.Attributes, EventsDroppedKey, "14") {
+		t.Fatalf("expected dropped count attribute, got %v", got.Attributes)
+	}
+}
+
+func TestEventLimitExporterPerSecondBudget(t *testing.T) {
+	mem := tracetest.NewInMemoryExporter()
+	limiter := newEventLimiter(EventLimits{PerSecond: 4})
+	now := time.Unix(100, 0)
+	limiter.now = func() time.Time { return now }
+	exporter := newTransformExporter(mem, limiter.limit)
+
+	spans := []sdktrace.ReadOnlySpan{spanWithEvents(t, 3), spanWithEvents(t, 3)}
+	if err := exporter.ExportSpans(context.Background(), spans); err != nil {
+		t.Fatalf("export failed: %v", err)
+	}
+	got := mem.GetSpans()
+	if len(got[0].Events) != 3 || len(got[1].Events) != 1 {
+		t.Fatalf("expected budget of 4 events to be split 3/1, got %d/%d", len(got[0].Events), len(got[1].Events))
+	}
+
+	now = now.Add(time.Second)
+	mem.Reset()
+	if err := exporter.ExportSpans(context.Background(), spans[:1]); err != nil {
+		t.Fatalf("export failed: %v", err)
+	}
+	if len(mem.GetSpans()[0].Events) != 3 {
+		t.Fatalf("expected budget to reset after a second")
+	}
+}
+
+func spanWithEvents(t *testing.T, n int) sdktrace.ReadOnlySpan {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	_, span := tp.Tracer("test").Start(context.Background(), "chatty")
+	for i := 0; i < n; i++ {
+		span.AddEvent(fmt.Sprintf("e%d", i))
+	}
+	span.End()
+	return recorder.Ended()[0]
+}
+
+func TestSetupEventLimitsPerPipeline(t *testing.T) {
+	restore := saveGlobal()
+	defer restore()
+	cfg := Config{ServiceName: "svc", SamplingRatio: Float64(1), Exporters: []ExporterConfig{{Exporter: ExporterMemory}, {Exporter: ExporterMemory}}}
+	prov, err := Setup(context.Background(), cfg, nil, WithEventLimits(EventLimits{PerSecond: 4}), WithSyncExport())
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	defer prov.Shutdown(context.Background())
+
+	_, span := prov.TP.Tracer("test").Start(context.Background(), "chatty")
+	for i := 0; i < 4; i++ {
+		span.AddEvent(fmt.Sprintf("e%d", i))
+	}
+	span.End()
+	spans := prov.RecordedSpans()
+	if len(spans) != 2 || len(spans[0].Events) != 4 || len(spans[1].Events) != 4 {
+		t.Fatalf("expected every pipeline to keep the 4 events within the budget, got %v", spans)
+	}
+}
@@ -0,0 +1,212 @@
+package otelx
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ProcessStderrKey holds the tail of a failed subprocess's stderr on Command spans.
+const ProcessStderrKey = attribute.Key("otelx.process.stderr")
+
+// commandStderrLimit caps the stderr kept for ProcessStderrKey; the end of the output, where
+// tools such as ffmpeg print the actual error, is kept.
+const commandStderrLimit = 2048
+
+// Cmd is an exec.Cmd whose run is recorded as a span. Use its Start, Wait, Run, Output and
+// CombinedOutput; calling the embedded exec.Cmd's methods directly bypasses the span.
+type Cmd struct {
+	*exec.Cmd
+
+	ctx    context.Context
+	span   trace.Span
+	stderr *tailBuffer
+	// ownStderr is set when Cmd installed the stderr writer, so Output can fill ExitError.Stderr
+	// like exec.Cmd does.
+	ownStderr bool
+}
+
+// Command is exec.CommandContext with tracing: a span "exec <name>" covers the subprocess from
+// Start to Wait and records the executable, pid and exit code; on failure it also records the
+// tail of stderr. The span context is passed to the child as TRACEPARENT/TRACESTATE environment
+// variables (via the global propagator), so traced children continue the trace. Arguments are not
+// recorded, as they often carry file names or credentials.
+func Command(ctx context.Context, name string, arg ...string) *Cmd {
+	return &Cmd{Cmd: exec.CommandContext(ctx, name, arg...), ctx: ctx}
+}
+
+// Start starts the command and its span.
+func (c *Cmd) Start() error {
+	if c.span != nil {
+		return errors.New("otelx: command already started")
+	}
+	executable := filepath.Base(c.Path)
+	ctx, span := otel.Tracer(instrumentationName).Start(c.ctx, "exec "+executable,
+		trace.WithAttributes(
+			semconv.ProcessExecutableName(executable),
+			semconv.ProcessExecutablePath(c.Path),
+		))
+	c.span = span
+
+	c.Env = injectEnv(ctx, c.Environ())
+	c.stderr = &tailBuffer{limit: commandStderrLimit}
+	switch {
+	case c.Stderr == nil:
+		c.Stderr = c.stderr
+		c.ownStderr = true
+	case sameWriter(c.Stderr, c.Stdout):
+		// Keep a single writer so exec shares one pipe instead of writing it from two goroutines.
+		w := io.MultiWriter(c.Stderr, c.stderr)
+		c.Stdout, c.Stderr = w, w
+	default:
+		c.Stderr = io.MultiWriter(c.Stderr, c.stderr)
+	}
+
+	if err := c.Cmd.Start(); err != nil {
+		c.end(err)
+		return err
+	}
+	span.SetAttributes(semconv.ProcessPID(c.Process.Pid))
+	return nil
+}
+
+// Wait waits for the command to exit and ends its span.
+func (c *Cmd) Wait() error {
+	err := c.Cmd.Wait()
+	if c.span != nil {
+		c.end(err)
+	}
+	return err
+}
+
+// Run starts the command and waits for it to complete.
+func (c *Cmd) Run() error {
+	if err := c.Start(); err != nil {
+		return err
+	}
+	return c.Wait()
+}
+
+// Output runs the command and returns its standard output, like exec.Cmd.Output.
+func (c *Cmd) Output() ([]byte, error) {
+	if c.Stdout != nil {
+		return nil, errors.New("exec: Stdout already set")
+	}
+	var stdout bytes.Buffer
+	c.Stdout = &stdout
+	err := c.Run()
+	var exitErr *exec.ExitError
+	if c.ownStderr && errors.As(err, &exitErr) {
+		exitErr.Stderr = c.stderr.Bytes()
+	}
+	return stdout.Bytes(), err
+}
+
+// CombinedOutput runs the command and returns its combined standard output and standard error.
+func (c *Cmd) CombinedOutput() ([]byte, error) {
+	if c.Stdout != nil {
+		return nil, errors.New("exec: Stdout already set")
+	}
+	if c.Stderr != nil {
+		return nil, errors.New("exec: Stderr already set")
+	}
+	var out bytes.Buffer
+	c.Stdout = &out
+	c.Stderr = &out
+	err := c.Run()
+	return out.Bytes(), err
+}
+
+func (c *Cmd) end(err error) {
+	span := c.span
+	if c.ProcessState != nil {
+		span.SetAttributes(semconv.ProcessExitCode(c.ProcessState.ExitCode()))
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		if tail := c.stderr.Bytes(); len(tail) > 0 {
+			span.SetAttributes(ProcessStderrKey.String(string(tail)))
+		}
+	}
+	span.End()
+}
+
+// sameWriter reports whether a and b are the same writer, like exec.Cmd's own check; writers of
+// uncomparable types are never the same.
+func sameWriter(a, b io.Writer) (same bool) {
+	defer func() {
+		if recover() != nil {
+			same = false
+		}
+	}()
+	return a == b
+}
+
+// injectEnv returns env with the span context of ctx set as propagation variables, using the
+// upper-cased header names (TRACEPARENT, TRACESTATE, BAGGAGE).
+func injectEnv(ctx context.Context, env []string) []string {
+	carrier := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+	if len(carrier) == 0 {
+		return env
+	}
+	vars := make(map[string]string, len(carrier))
+	for k, v := range carrier {
+		vars[strings.ToUpper(k)] = v
+	}
+	out := make([]string, 0, len(env)+len(vars))
+	for _, kv := range env {
+		if name, _, _ := strings.Cut(kv, "="); vars[name] == "" {
+			out = append(out, kv)
+		}
+	}
+	for k, v := range vars {
+		out = append(out, k+"="+v)
+	}
+	return out
+}
+
+// tailBuffer keeps the last limit bytes written to it.
+type tailBuffer struct {
+	limit     int
+	buf       []byte
+	truncated bool
+}
+
+func (b *tailBuffer) Write(p []byte) (int, error) {
+	n := len(p)
+	if len(p) >= b.limit {
+		b.buf = append(b.buf[:0], p[len(p)-b.limit:]...)
+		b.truncated = true
+		return n, nil
+	}
+	if over := len(b.buf) + len(p) - b.limit; over > 0 {
+		b.buf = append(b.buf[:0], b.buf[over:]...)
+		b.truncated = true
+	}
+	b.buf = append(b.buf, p...)
+	return n, nil
+}
+
+// Bytes returns the kept output, prefixed with "..." when earlier output was discarded.
+func (b *tailBuffer) Bytes() []byte {
+	if b == nil || len(b.buf) == 0 {
+		return nil
+	}
+	if b.truncated {
+		return append([]byte("..."), b.buf...)
+	}
+	return append([]byte(nil), b.buf...)
+}
@@ -0,0 +1,82 @@
+package otelx
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/codes"
+)
+
+func TestCommandPropagatesTraceparent(t *testing.T) {
+	defer saveGlobal()()
+	cfg := Config{ServiceName: "svc", Exporter: ExporterMemory, SamplingRatio: Float64(1)}
+	prov, err := Setup(context.Background(), cfg, nil, WithGlobal())
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	defer prov.Shutdown(context.Background())
+
+	ctx, parent := prov.TP.Tracer("test").Start(context.Background(), "transcode")
+	out, err := Command(ctx, "sh", "-c", `printf %s "$TRACEPARENT"`).Output()
+	parent.End()
+	if err != nil {
+		t.Fatalf("command failed: %v", err)
+	}
+
+	spans := prov.RecordedSpans()
+	if len(spans) != 2 || spans[0].Name != "exec sh" {
+		t.Fatalf("expected an exec span, got %+v", spans)
+	}
+	span := spans[0]
+	want := "00-" + span.SpanContext.TraceID().String() + "-" + span.SpanContext.SpanID().String() + "-01"
+	if string(out) != want {
+		t.Fatalf("expected child TRACEPARENT %q, got %q", want, out)
+	}
+	if span.Parent.SpanID() != parent.SpanContext().SpanID() {
+		t.Fatalf("expected the exec span under the caller's span")
+	}
+	if !spanHasAttribute(span.Attributes, "process.exit.code", "0") || !spanHasAttribute(span.Attributes, "process.executable.name", "sh") {
+		t.Fatalf("missing process attributes: %v", span.Attributes)
+	}
+}
+
+func TestCommandRecordsFailure(t *testing.T) {
+	defer saveGlobal()()
+	cfg := Config{ServiceName: "svc", Exporter: ExporterMemory, SamplingRatio: Float64(1)}
+	prov, err := Setup(context.Background(), cfg, nil, WithGlobal())
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	defer prov.Shutdown(context.Background())
+
+	_, err = Command(context.Background(), "sh", "-c", "echo 'Invalid data found' >&2; exit 3").Output()
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) || string(exitErr.Stderr) != "Invalid data found\n" {
+		t.Fatalf("expected an exit error carrying stderr, got %v", err)
+	}
+
+	spans := prov.RecordedSpans()
+	if len(spans) != 1 || spans[0].Status.Code != codes.Error {
+		t.Fatalf("expected one failed span, got %+v", spans)
+	}
+	if !spanHasAttribute(spans[0].Attributes, "process.exit.code", "3") ||
+		!spanHasAttribute(spans[0].Attributes, ProcessStderrKey, "Invalid data found\n") {
+		t.Fatalf("missing failure attributes: %v", spans[0].Attributes)
+	}
+}
+
+func TestTailBufferKeepsEnd(t *testing.T) {
+	b := &tailBuffer{limit: 8}
+	_, _ = b.Write([]byte("frame=1 "))
+	_, _ = b.Write([]byte("error!"))
+	if got := string(b.Bytes()); got != "...1 error!" {
+		t.Fatalf("unexpected tail %q", got)
+	}
+	_, _ = b.Write([]byte(strings.Repeat("x", 20)))
+	if got := string(b.Bytes()); got != "..."+strings.Repeat("x", 8) {
+		t.Fatalf("unexpected tail %q", got)
+	}
+}
@@ -0,0 +1,40 @@
+package otelx
+
+import (
+	"encoding/binary"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// traceRatioFilter keeps spans whose trace id hashes within ratio. The hash mixes the whole id so
+// the decision is independent of head sampling (sdktrace.TraceIDRatioBased compares the low 8
+// bytes directly) and of X-Ray ids, whose first bytes are a timestamp. The decision is
+// deterministic per trace, so every span of a kept trace reaches the pipeline and pipelines with
+// lower ratios receive a subset of the traces seen by higher ones. Provider.StartCanary spans
+// always pass so the canary checks every pipeline.
+func traceRatioFilter(ratio float64) spanTransform {
+	if ratio >= 1 {
+		return nil
+	}
+	bound := uint64(ratio * (1 << 63))
+	return func(span sdktrace.ReadOnlySpan) sdktrace.ReadOnlySpan {
+		if traceIDHash(span.SpanContext().TraceID())>>1 < bound || isCanarySpan(span) {
+			return span
+		}
+		return nil
+	}
+}
+
+// traceIDHash maps a trace id to a uniformly distributed value using the splitmix64 finalizer.
+func traceIDHash(id [16]byte) uint64 {
+	return mix64(binary.BigEndian.Uint64(id[:8]) ^ mix64(binary.BigEndian.Uint64(id[8:])^0x9e3779b97f4a7c15))
+}
+
+func mix64(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
@@ -0,0 +1,107 @@
+package otelx
+
+import (
+	"context"
+	"encoding/binary"
+	"math/rand/v2"
+	"testing"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTraceRatioFilterIsDeterministicAndNested(t *testing.T) {
+	if traceRatioFilter(1) != nil {
+		t.Fatalf("expected ratio 1 to need no filter")
+	}
+	low, high := traceRatioFilter(0.05), traceRatioFilter(0.5)
+	span := spanWithAttributes(t)
+
+	rng := rand.New(rand.NewPCG(1, 2))
+	var keptLow, keptHigh int
+	for i := 0; i < 2000; i++ {
+		var tid trace.TraceID
+		binary.BigEndian.PutUint64(tid[8:], rng.Uint64())
+		s := &spanContextOverride{ReadOnlySpan: span, sc: trace.NewSpanContext(trace.SpanContextConfig{TraceID: tid})}
+		inLow, inHigh := low(s) != nil, high(s) != nil
+		if inLow != (low(s) != nil) {
+			t.Fatalf("expected the same decision for the same trace id")
+		}
+		if inLow && !inHigh {
+			t.Fatalf("expected traces kept at 5%% to also be kept at 50%%")
+		}
+		if inLow {
+			keptLow++
+		}
+		if inHigh {
+			keptHigh++
+		}
+	}
+	if keptLow == 0 || keptLow >= keptHigh || keptHigh == 2000 {
+		t.Fatalf("unexpected kept counts: low=%d high=%d", keptLow, keptHigh)
+	}
+}
+
+func TestTraceRatioFilterIndependentOfHeadSampling(t *testing.T) {
+	head := sdktrace.TraceIDRatioBased(0.1)
+	span := spanWithAttributes(t)
+	rng := rand.New(rand.NewPCG(3, 4))
+	for _, ratio := range []float64{0.05, 0.5} {
+		filter := traceRatioFilter(ratio)
+		var sampled, kept int
+		for i := 0; i < 50000; i++ {
+			var tid trace.TraceID
+			binary.BigEndian.PutUint64(tid[:8], rng.Uint64())
+			binary.BigEndian.PutUint64(tid[8:], rng.Uint64())
+			if head.ShouldSample(sdktrace.SamplingParameters{TraceID: tid}).Decision != sdktrace.RecordAndSample {
+				continue
+			}
+			sampled++
+			s := &spanContextOverride{ReadOnlySpan: span, sc: trace.NewSpanContext(trace.SpanContextConfig{TraceID: tid})}
+			if filter(s) != nil {
+				kept++
+			}
+		}
+		if share := float64(kept) / float64(sampled); share < ratio*0.8 || share > ratio*1.2 {
+			t.Fatalf("exportRatio %v forwarded %.3f of %d head-sampled traces", ratio, share, sampled)
+		}
+	}
+}
+
+func TestSetupExportRatioPerPipeline(t *testing.T) {
+	cfg := Config{
+		ServiceName:   "svc",
+		SamplingRatio: Float64(1),
+		Exporters: []ExporterConfig{
+			{Exporter: ExporterMemory},
+			{Exporter: ExporterMemory, ExportRatio: Float64(0)},
+		},
+	}
+	prov, err := Setup(context.Background(), cfg, noopLogger{})
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	defer prov.Shutdown(context.Background())
+
+	_, span := prov.TP.Tracer("test").Start(context.Background(), "op")
+	span.End()
+	_ = prov.TP.ForceFlush(context.Background())
+	if got := len(prov.memories[0].GetSpans()); got != 1 {
+		t.Fatalf("expected full pipeline to receive the span, got %d", got)
+	}
+	if got := len(prov.memories[1].GetSpans()); got != 0 {
+		t.Fatalf("expected exportRatio=0 pipeline to receive nothing, got %d", got)
+	}
+
+	cfg.Exporters[1].ExportRatio = Float64(1.5)
+	if _, err := Setup(context.Background(), cfg, nil); err == nil {
+		t.Fatalf("expected exportRatio outside [0,1] to be rejected")
+	}
+}
+
+type spanContextOverride struct {
+	sdktrace.ReadOnlySpan
+	sc trace.SpanContext
+}
+
+func (s *spanContextOverride) SpanContext() trace.SpanContext { return s.sc }
@@ -0,0 +1,186 @@
+package otelx
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	logx "github.com/bionicotaku/lingo-utils-logx"
+	"go.opentelemetry.io/otel/metric"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// ExportStats counts the spans passing through the span batchers, summed over all exporters.
+type ExportStats struct {
+	// Queued spans were handed to a batcher.
+	Queued int64 `json:"queued"`
+	// Exported spans were accepted by their exporter.
+	Exported int64 `json:"exported"`
+	// Failed spans were in an export call that returned an error; the batcher does not retry them.
+	Failed int64 `json:"failed"`
+	// Pending spans were queued but neither exported nor failed yet: they wait in a batcher or were
+	// dropped because its queue was full. After Shutdown every remaining pending span was dropped.
+	Pending int64 `json:"pending"`
+}
+
+// WithExportStats counts spans queued, exported and failed by the span batchers, reported by
+// Provider.ExportStats, as otelx.exporter.spans.* metrics when Provider.MP is set and, for a
+// positive interval, as a periodic otelx.exporter.stats log entry (a warning when spans failed).
+// Shutdown logs a final entry whose dropped count covers spans lost to full queues, so operators
+// can notice silent data loss when the collector is overloaded.
+func WithExportStats(interval time.Duration) Option {
+	return func(o *setupOptions) {
+		o.exportStats = true
+		o.exportStatsEvery = interval
+	}
+}
+
+type exportCounter struct {
+	queued   atomic.Int64
+	exported atomic.Int64
+	failed   atomic.Int64
+
+	stop     chan struct{}
+	stopOnce sync.Once
+	done     chan struct{}
+}
+
+func newExportCounter() *exportCounter {
+	return &exportCounter{stop: make(chan struct{}), done: make(chan struct{})}
+}
+
+func (c *exportCounter) stats() ExportStats {
+	s := ExportStats{Queued: c.queued.Load(), Exported: c.exported.Load(), Failed: c.failed.Load()}
+	s.Pending = max(s.Queued-s.Exported-s.Failed, 0)
+	return s
+}
+
+// exporter counts the outcome of every export call of next.
+func (c *exportCounter) exporter(next sdktrace.SpanExporter) sdktrace.SpanExporter {
+	return &countingExporter{SpanExporter: next, counter: c}
+}
+
+// processor counts the sampled spans handed to next, a batcher.
+func (c *exportCounter) processor(next sdktrace.SpanProcessor) sdktrace.SpanProcessor {
+	return &countingProcessor{SpanProcessor: next, counter: c}
+}
+
+type countingExporter struct {
+	sdktrace.SpanExporter
+	counter *exportCounter
+}
+
+func (e *countingExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	err := e.SpanExporter.ExportSpans(ctx, spans)
+	if err != nil {
+		e.counter.failed.Add(int64(len(spans)))
+	} else {
+		e.counter.exported.Add(int64(len(spans)))
+	}
+	return err
+}
+
+type countingProcessor struct {
+	sdktrace.SpanProcessor
+	counter *exportCounter
+}
+
+func (p *countingProcessor) OnEnd(s sdktrace.ReadOnlySpan) {
+	if s.SpanContext().IsSampled() {
+		p.counter.queued.Add(1)
+	}
+	p.SpanProcessor.OnEnd(s)
+}
+
+// registerMetrics reports the counters as observable counters on mp.
+func (c *exportCounter) registerMetrics(mp metric.MeterProvider) error {
+	meter := mp.Meter(instrumentationName)
+	queued, err := meter.Int64ObservableCounter("otelx.exporter.spans.queued",
+		metric.WithDescription("Spans handed to the span batchers."), metric.WithUnit("{span}"))
+	if err != nil {
+		return err
+	}
+	exported, err := meter.Int64ObservableCounter("otelx.exporter.spans.exported",
+		metric.WithDescription("Spans accepted by the span exporters."), metric.WithUnit("{span}"))
+	if err != nil {
+		return err
+	}
+	failed, err := meter.Int64ObservableCounter("otelx.exporter.spans.failed",
+		metric.WithDescription("Spans in failed export calls."), metric.WithUnit("{span}"))
+	if err != nil {
+		return err
+	}
+	pending, err := meter.Int64ObservableGauge("otelx.exporter.spans.pending",
+		metric.WithDescription("Spans queued but not yet exported, or dropped by a full queue."), metric.WithUnit("{span}"))
+	if err != nil {
+		return err
+	}
+	_, err = meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		s := c.stats()
+		o.ObserveInt64(queued, s.Queued)
+		o.ObserveInt64(exported, s.Exported)
+		o.ObserveInt64(failed, s.Failed)
+		o.ObserveInt64(pending, s.Pending)
+		return nil
+	}, queued, exported, failed, pending)
+	return err
+}
+
+// start logs the counters every interval until close.
+func (c *exportCounter) start(interval time.Duration, logger logx.Logger) {
+	go func() {
+		defer close(c.done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		var last ExportStats
+		for {
+			select {
+			case <-c.stop:
+				return
+			case <-ticker.C:
+				s := c.stats()
+				if s == last {
+					continue
+				}
+				attrs := []logx.Attr{
+					logx.Any("queued", s.Queued-last.Queued),
+					logx.Any("exported", s.Exported-last.Exported),
+					logx.Any("failed", s.Failed-last.Failed),
+					logx.Any("pending", s.Pending),
+				}
+				if s.Failed > last.Failed {
+					logger.Warn(context.Background(), "otelx.exporter.stats", attrs...)
+				} else {
+					logger.Info(context.Background(), "otelx.exporter.stats", attrs...)
+				}
+				last = s
+			}
+		}
+	}()
+}
+
+// close stops periodic logging and, after the batchers were shut down, logs the totals.
+func (c *exportCounter) close(ctx context.Context, logger logx.Logger, started bool) {
+	c.stopOnce.Do(func() {
+		close(c.stop)
+		if started {
+			<-c.done
+		}
+		if logger == nil {
+			return
+		}
+		s := c.stats()
+		attrs := []logx.Attr{
+			logx.Any("queued", s.Queued),
+			logx.Any("exported", s.Exported),
+			logx.Any("failed", s.Failed),
+			logx.Any("dropped", s.Failed+s.Pending),
+		}
+		if s.Failed+s.Pending > 0 {
+			logger.Warn(ctx, "otelx.exporter.stats.summary", attrs...)
+			return
+		}
+		logger.Info(ctx, "otelx.exporter.stats.summary", attrs...)
+	})
+}
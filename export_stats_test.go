@@ -0,0 +1,79 @@
+package otelx
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestExportStats(t *testing.T) {
+	rec := &recordingLogger{}
+	reader := sdkmetric.NewManualReader()
+	cfg := Config{ServiceName: "svc", SamplingRatio: Float64(1)}
+	prov, err := Setup(context.Background(), cfg, rec,
+		WithSpanExporter(tracetest.NewInMemoryExporter()),
+		WithSpanExporter(failingExporter{}),
+		WithMetricReader(reader),
+		WithExportStats(5*time.Millisecond))
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+
+	for range 3 {
+		_, span := prov.TP.Tracer("test").Start(context.Background(), "op")
+		span.End()
+	}
+	_ = prov.TP.ForceFlush(context.Background())
+
+	want := ExportStats{Queued: 6, Exported: 3, Failed: 3}
+	if got := prov.ExportStats(); got != want {
+		t.Fatalf("expected %+v, got %+v", want, got)
+	}
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("collect failed: %v", err)
+	}
+	values := map[string]int64{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			switch data := m.Data.(type) {
+			case metricdata.Sum[int64]:
+				values[m.Name] = data.DataPoints[0].Value
+			case metricdata.Gauge[int64]:
+				values[m.Name] = data.DataPoints[0].Value
+			}
+		}
+	}
+	if values["otelx.exporter.spans.queued"] != 6 || values["otelx.exporter.spans.failed"] != 3 || values["otelx.exporter.spans.pending"] != 0 {
+		t.Fatalf("unexpected export metrics %v", values)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for !slices.Contains(rec.Entries(), "warn:otelx.exporter.stats") && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if err := prov.Shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown failed: %v", err)
+	}
+	entries := rec.Entries()
+	if !slices.Contains(entries, "warn:otelx.exporter.stats") || !slices.Contains(entries, "warn:otelx.exporter.stats.summary") {
+		t.Fatalf("expected periodic and summary warnings, got %v", entries)
+	}
+}
+
+func TestExportStatsDisabled(t *testing.T) {
+	prov, err := Setup(context.Background(), Config{ServiceName: "svc", Exporter: ExporterMemory}, nil)
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	defer prov.Shutdown(context.Background())
+	if got := prov.ExportStats(); got != (ExportStats{}) {
+		t.Fatalf("expected zero stats without WithExportStats, got %+v", got)
+	}
+}
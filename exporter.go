@@ -3,18 +3,102 @@ package otelx
 import (
 	"context"
 	"fmt"
-	"time"
+	"io"
+	"os"
 
 	cloudtrace "github.com/GoogleCloudPlatform/opentelemetry-operations-go/exporter/trace"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/xray"
 	logx "github.com/bionicotaku/lingo-utils-logx"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
 	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/exporters/zipkin"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"google.golang.org/grpc/credentials"
 )
 
-func buildExporter(ctx context.Context, cfg Config, logger logx.Logger) (sdktrace.SpanExporter, error) {
+// buildExporters creates one exporter per configured pipeline, or dry-run stand-ins when
+// cfg.DryRun is set, filtered by the pipeline's ExportRatio. It also returns the dry-run and
+// in-memory exporters so Provider can report on them. Already created exporters are shut down if
+// a later one fails.
+func buildExporters(ctx context.Context, cfg Config, logger logx.Logger) ([]sdktrace.SpanExporter, []*dryRunExporter, []*tracetest.InMemoryExporter, error) {
+	var (
+		exporters []sdktrace.SpanExporter
+		dryRuns   []*dryRunExporter
+		memories  []*tracetest.InMemoryExporter
+	)
+	for _, ec := range cfg.exporterConfigs() {
+		var exporter sdktrace.SpanExporter
+		if cfg.DryRun {
+			dryRun := newDryRunExporter(ec.Exporter, logger)
+			dryRuns = append(dryRuns, dryRun)
+			exporter = dryRun
+			if logger != nil {
+				logger.Info(ctx, "otelx.exporter.dryrun.enabled", logx.String("exporter", string(ec.Exporter)))
+			}
+		} else {
+			var err error
+			exporter, err = buildExporter(ctx, ec, logger)
+			if err != nil {
+				shutdownExporters(ctx, exporters)
+				return nil, nil, nil, err
+			}
+			if mem, ok := exporter.(*tracetest.InMemoryExporter); ok {
+				memories = append(memories, mem)
+			}
+		}
+		exporters = append(exporters, withExportRatio(exporter, ec.ExportRatio))
+	}
+	return exporters, dryRuns, memories, nil
+}
+
+// withExportRatio wraps exporter with the pipeline's ExportRatio filter, if any.
+func withExportRatio(exporter sdktrace.SpanExporter, ratio *float64) sdktrace.SpanExporter {
+	if ratio == nil {
+		return exporter
+	}
+	if filter := traceRatioFilter(*ratio); filter != nil {
+		return newTransformExporter(exporter, filter)
+	}
+	return exporter
+}
+
+// debugTeeOutput receives Config.DebugTee output; tests replace it.
+var debugTeeOutput io.Writer = os.Stdout
+
+// newDebugTeeExporter builds the extra stdout pipeline enabled by Config.DebugTee.
+func newDebugTeeExporter() (sdktrace.SpanExporter, error) {
+	exporter, err := stdouttrace.New(stdouttrace.WithWriter(debugTeeOutput), stdouttrace.WithPrettyPrint())
+	if err != nil {
+		return nil, fmt.Errorf("otelx: create debug tee exporter: %w", err)
+	}
+	return exporter, nil
+}
+
+// shutdownExporters releases exporters that never made it into a TracerProvider.
+func shutdownExporters(ctx context.Context, exporters []sdktrace.SpanExporter) {
+	for _, exporter := range exporters {
+		_ = exporter.Shutdown(ctx)
+	}
+}
+
+func buildExporter(ctx context.Context, cfg ExporterConfig, logger logx.Logger) (sdktrace.SpanExporter, error) {
+	if cfg.Failover != nil {
+		return buildFailoverExporter(ctx, cfg, logger)
+	}
 	logCtx := ctx
 
+	// Local collectors rarely terminate TLS; remote hosts still require an explicit Insecure.
+	if !cfg.Insecure && cfg.autoInsecure() {
+		cfg.Insecure = true
+		if logger != nil {
+			logger.Info(logCtx, "otelx.exporter.insecure.auto", logx.String("endpoint", cfg.Endpoint))
+		}
+	}
+
 	switch cfg.Exporter {
 	case "", ExporterStdout:
 		exporter, err := stdouttrace.New(stdouttrace.WithPrettyPrint())
@@ -37,6 +121,22 @@ func buildExporter(ctx context.Context, cfg Config, logger logx.Logger) (sdktrac
 		if len(cfg.Headers) > 0 {
 			options = append(options, otlptracegrpc.WithHeaders(cfg.Headers))
 		}
+		if cfg.ExporterTimeout > 0 {
+			options = append(options, otlptracegrpc.WithTimeout(cfg.ExporterTimeout))
+		}
+		if cfg.OTLPRetry != nil {
+			options = append(options, otlptracegrpc.WithRetry(cfg.OTLPRetry.grpcRetry()))
+		}
+		if cfg.Compression == CompressionGzip {
+			options = append(options, otlptracegrpc.WithCompressor(CompressionGzip))
+		}
+		if cfg.TLS != nil {
+			tlsCfg, err := cfg.TLS.tlsConfig()
+			if err != nil {
+				return nil, fmt.Errorf("otelx: otlp exporter tls: %w", err)
+			}
+			options = append(options, otlptracegrpc.WithTLSCredentials(credentials.NewTLS(tlsCfg)))
+		}
 
 		exporter, err := otlptracegrpc.New(ctx, options...)
 		if err != nil {
@@ -47,11 +147,56 @@ func buildExporter(ctx context.Context, cfg Config, logger logx.Logger) (sdktrac
 		}
 		return exporter, nil
 
+	case ExporterOTLPHTTP:
+		options := []otlptracehttp.Option{}
+		if path, ok := unixSocketPath(cfg.Endpoint); ok {
+			options = append(options, otlptracehttp.WithEndpoint("localhost"), otlptracehttp.WithHTTPClient(unixSocketClient(path, cfg.timeout())))
+		} else if cfg.Endpoint != "" {
+			options = append(options, otlptracehttp.WithEndpoint(cfg.Endpoint))
+		}
+		if cfg.URLPath != "" {
+			options = append(options, otlptracehttp.WithURLPath(cfg.URLPath))
+		}
+		if cfg.Insecure {
+			options = append(options, otlptracehttp.WithInsecure())
+		}
+		if len(cfg.Headers) > 0 {
+			options = append(options, otlptracehttp.WithHeaders(cfg.Headers))
+		}
+		if cfg.ExporterTimeout > 0 {
+			options = append(options, otlptracehttp.WithTimeout(cfg.ExporterTimeout))
+		}
+		if cfg.OTLPRetry != nil {
+			options = append(options, otlptracehttp.WithRetry(cfg.OTLPRetry.httpRetry()))
+		}
+		switch cfg.Compression {
+		case CompressionGzip:
+			options = append(options, otlptracehttp.WithCompression(otlptracehttp.GzipCompression))
+		case CompressionNone:
+			options = append(options, otlptracehttp.WithCompression(otlptracehttp.NoCompression))
+		}
+		if cfg.TLS != nil {
+			tlsCfg, err := cfg.TLS.tlsConfig()
+			if err != nil {
+				return nil, fmt.Errorf("otelx: otlphttp exporter tls: %w", err)
+			}
+			options = append(options, otlptracehttp.WithTLSClientConfig(tlsCfg))
+		}
+
+		exporter, err := otlptracehttp.New(ctx, options...)
+		if err != nil {
+			return nil, fmt.Errorf("otelx: create otlphttp exporter: %w", err)
+		}
+		if logger != nil {
+			logger.Info(logCtx, "otelx.exporter.otlphttp.enabled")
+		}
+		return exporter, nil
+
 	case ExporterCloudTrace:
 		exporter, err := cloudtrace.New(
 			cloudtrace.WithProjectID(cfg.GCPProjectID),
 			cloudtrace.WithContext(ctx),
-			cloudtrace.WithTimeout(10*time.Second),
+			cloudtrace.WithTimeout(cfg.timeout()),
 		)
 		if err != nil {
 			return nil, fmt.Errorf("otelx: create cloudtrace exporter: %w", err)
@@ -59,6 +204,67 @@ func buildExporter(ctx context.Context, cfg Config, logger logx.Logger) (sdktrac
 		if logger != nil {
 			logger.Info(logCtx, "otelx.exporter.cloudtrace.enabled")
 		}
+		return newQuotaGuardExporter(exporter, string(ExporterCloudTrace), logger), nil
+
+	case ExporterZipkin:
+		options := []zipkin.Option{}
+		if len(cfg.Headers) > 0 {
+			options = append(options, zipkin.WithHeaders(cfg.Headers))
+		}
+
+		// An empty endpoint lets the exporter fall back to OTEL_EXPORTER_ZIPKIN_ENDPOINT or localhost:9411.
+		exporter, err := zipkin.New(cfg.Endpoint, options...)
+		if err != nil {
+			return nil, fmt.Errorf("otelx: create zipkin exporter: %w", err)
+		}
+		if logger != nil {
+			logger.Info(logCtx, "otelx.exporter.zipkin.enabled")
+		}
+		return exporter, nil
+
+	case ExporterXRay:
+		awsOpts := []func(*awsconfig.LoadOptions) error{}
+		if cfg.AWSRegion != "" {
+			awsOpts = append(awsOpts, awsconfig.WithRegion(cfg.AWSRegion))
+		}
+		awsCfg, err := awsconfig.LoadDefaultConfig(ctx, awsOpts...)
+		if err != nil {
+			return nil, fmt.Errorf("otelx: create xray exporter: %w", err)
+		}
+		client := xray.NewFromConfig(awsCfg, func(o *xray.Options) {
+			if cfg.Endpoint != "" {
+				o.BaseEndpoint = aws.String(cfg.Endpoint)
+			}
+		})
+		if logger != nil {
+			logger.Info(logCtx, "otelx.exporter.xray.enabled", logx.String("region", awsCfg.Region))
+		}
+		return newXRayExporter(client), nil
+
+	case ExporterAzureMonitor:
+		conn, err := parseAzureConnectionString(cfg.AzureConnectionString)
+		if err != nil {
+			return nil, fmt.Errorf("otelx: create azuremonitor exporter: %w", err)
+		}
+		if logger != nil {
+			logger.Info(logCtx, "otelx.exporter.azuremonitor.enabled", logx.String("endpoint", conn.IngestionEndpoint))
+		}
+		return newAzureMonitorExporter(conn, nil), nil
+
+	case ExporterMemory:
+		if logger != nil {
+			logger.Debug(logCtx, "otelx.exporter.memory.enabled")
+		}
+		return tracetest.NewInMemoryExporter(), nil
+
+	case ExporterFile:
+		exporter, err := newFileExporter(cfg.FilePath, cfg.FileMaxBytes, cfg.FileMaxBackups)
+		if err != nil {
+			return nil, fmt.Errorf("otelx: create file exporter: %w", err)
+		}
+		if logger != nil {
+			logger.Info(logCtx, "otelx.exporter.file.enabled", logx.String("path", cfg.FilePath))
+		}
 		return exporter, nil
 
 	default:
@@ -0,0 +1,170 @@
+package otelx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	logx "github.com/bionicotaku/lingo-utils-logx"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+const (
+	// DefaultFailoverThreshold is the number of consecutive failed exports that switch to the
+	// secondary endpoint when FailureThreshold is unset.
+	DefaultFailoverThreshold = 3
+	// DefaultFailoverProbeInterval is how often the primary endpoint is retried while failed over
+	// when ProbeInterval is unset.
+	DefaultFailoverProbeInterval = 30 * time.Second
+)
+
+// FailoverConfig adds a secondary OTLP endpoint, e.g. a collector in another zone, that takes over
+// while the primary is unreachable, keeping traces flowing during collector maintenance. The
+// secondary shares every other setting of the pipeline (headers, TLS, compression, ...).
+type FailoverConfig struct {
+	// Endpoint is the secondary collector.
+	Endpoint string `json:"endpoint"`
+	// FailureThreshold is the number of consecutive failed exports to the primary that trigger the
+	// failover (default DefaultFailoverThreshold). The failing batch is retried on the secondary.
+	FailureThreshold int `json:"failureThreshold"`
+	// ProbeInterval is how often a batch is sent to the primary again while failed over; the
+	// exporter fails back once it succeeds (default DefaultFailoverProbeInterval).
+	ProbeInterval time.Duration `json:"probeInterval"`
+}
+
+func (c FailoverConfig) validate() error {
+	if strings.TrimSpace(c.Endpoint) == "" {
+		return errors.New("endpoint is required")
+	}
+	if c.FailureThreshold < 0 || c.ProbeInterval < 0 {
+		return errors.New("failureThreshold and probeInterval must not be negative")
+	}
+	return nil
+}
+
+func (c FailoverConfig) withDefaults() FailoverConfig {
+	if c.FailureThreshold == 0 {
+		c.FailureThreshold = DefaultFailoverThreshold
+	}
+	if c.ProbeInterval == 0 {
+		c.ProbeInterval = DefaultFailoverProbeInterval
+	}
+	return c
+}
+
+// buildFailoverExporter builds the primary and secondary exporters of a pipeline with Failover.
+// Unless OTLPRetry is set, the primary does not retry: failing over replaces its retries, which
+// would otherwise use up the batch's time before the secondary is tried.
+func buildFailoverExporter(ctx context.Context, cfg ExporterConfig, logger logx.Logger) (sdktrace.SpanExporter, error) {
+	failover := cfg.Failover.withDefaults()
+	cfg.Failover = nil
+	primaryCfg := cfg
+	if primaryCfg.OTLPRetry == nil {
+		primaryCfg.OTLPRetry = &OTLPRetryConfig{Enabled: Bool(false)}
+	}
+	primary, err := buildExporter(ctx, primaryCfg, logger)
+	if err != nil {
+		return nil, err
+	}
+	cfg.Endpoint = strings.TrimSpace(failover.Endpoint)
+	secondary, err := buildExporter(ctx, cfg, logger)
+	if err != nil {
+		_ = primary.Shutdown(ctx)
+		return nil, fmt.Errorf("otelx: failover: %w", err)
+	}
+	return newFailoverExporter(primary, secondary, failover, logger), nil
+}
+
+// failoverExporter sends batches to primary until it fails FailureThreshold times in a row, then to
+// secondary, probing primary with a live batch every ProbeInterval.
+type failoverExporter struct {
+	primary   sdktrace.SpanExporter
+	secondary sdktrace.SpanExporter
+	cfg       FailoverConfig
+	logger    logx.Logger
+	now       func() time.Time
+
+	mu        sync.Mutex
+	failures  int
+	failedAt  time.Time // zero while exporting to primary
+	nextProbe time.Time
+}
+
+func newFailoverExporter(primary, secondary sdktrace.SpanExporter, cfg FailoverConfig, logger logx.Logger) *failoverExporter {
+	return &failoverExporter{primary: primary, secondary: secondary, cfg: cfg.withDefaults(), logger: logger, now: time.Now}
+}
+
+func (e *failoverExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	e.mu.Lock()
+	failedOver := !e.failedAt.IsZero()
+	probe := failedOver && !e.now().Before(e.nextProbe)
+	if probe {
+		e.nextProbe = e.now().Add(e.cfg.ProbeInterval)
+	}
+	e.mu.Unlock()
+
+	if !failedOver || probe {
+		primaryCtx, cancel := primaryContext(ctx)
+		err := e.primary.ExportSpans(primaryCtx, spans)
+		cancel()
+		if err == nil {
+			e.recordSuccess(ctx)
+			return nil
+		}
+		if !e.recordFailure(ctx, err) {
+			return err
+		}
+	}
+	return e.secondary.ExportSpans(ctx, spans)
+}
+
+// primaryContext bounds a primary attempt to half of ctx's remaining time, leaving the other half
+// for the secondary when the attempt fails.
+func primaryContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, time.Until(deadline)/2)
+}
+
+// recordSuccess resets the failure count and fails back to primary.
+func (e *failoverExporter) recordSuccess(ctx context.Context) {
+	e.mu.Lock()
+	failedAt := e.failedAt
+	e.failures, e.failedAt = 0, time.Time{}
+	e.mu.Unlock()
+	if !failedAt.IsZero() && e.logger != nil {
+		e.logger.Info(ctx, "otelx.exporter.failback", logx.Duration("duration", e.now().Sub(failedAt)))
+	}
+}
+
+// recordFailure counts a failed primary export and reports whether the batch should go to
+// secondary, which is the case once the exporter has failed over.
+func (e *failoverExporter) recordFailure(ctx context.Context, err error) bool {
+	e.mu.Lock()
+	if !e.failedAt.IsZero() {
+		e.mu.Unlock()
+		return true
+	}
+	e.failures++
+	if e.failures < e.cfg.FailureThreshold {
+		e.mu.Unlock()
+		return false
+	}
+	e.failedAt = e.now()
+	e.nextProbe = e.failedAt.Add(e.cfg.ProbeInterval)
+	failures := e.failures
+	e.mu.Unlock()
+	if e.logger != nil {
+		e.logger.Warn(ctx, "otelx.exporter.failover", logx.Int("failures", failures), logx.String("error", err.Error()))
+	}
+	return true
+}
+
+func (e *failoverExporter) Shutdown(ctx context.Context) error {
+	return errors.Join(e.primary.Shutdown(ctx), e.secondary.Shutdown(ctx))
+}
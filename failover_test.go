@@ -0,0 +1,106 @@
+package otelx
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestFailoverExporter(t *testing.T) {
+	primary := &flakyExporter{InMemoryExporter: tracetest.NewInMemoryExporter()}
+	secondary := &flakyExporter{InMemoryExporter: tracetest.NewInMemoryExporter()}
+	logger := &recordingLogger{}
+	e := newFailoverExporter(primary, secondary, FailoverConfig{FailureThreshold: 2, ProbeInterval: time.Minute}, logger)
+	now := time.Unix(1000, 0)
+	e.now = func() time.Time { return now }
+	ctx := context.Background()
+	spans := endedSpans(t)
+
+	primary.down.Store(true)
+	if err := e.ExportSpans(ctx, spans); err == nil {
+		t.Fatal("expected the first failure to be returned")
+	}
+	if err := e.ExportSpans(ctx, spans); err != nil {
+		t.Fatalf("expected the batch to be retried on the secondary, got %v", err)
+	}
+	if got := len(secondary.GetSpans()); got != len(spans) {
+		t.Fatalf("expected the secondary to receive the batch, got %d spans", got)
+	}
+
+	// Primary recovered, but it is only probed after ProbeInterval.
+	primary.down.Store(false)
+	now = now.Add(30 * time.Second)
+	_ = e.ExportSpans(ctx, spans)
+	if len(primary.GetSpans()) != 0 || len(secondary.GetSpans()) != 2*len(spans) {
+		t.Fatal("expected batches to stay on the secondary before the probe")
+	}
+	now = now.Add(time.Minute)
+	if err := e.ExportSpans(ctx, spans); err != nil {
+		t.Fatalf("probe: %v", err)
+	}
+	if got := len(primary.GetSpans()); got != len(spans) {
+		t.Fatalf("expected the probe batch to reach the primary, got %d spans", got)
+	}
+	entries := logger.Entries()
+	if !slices.Contains(entries, "warn:otelx.exporter.failover") || !slices.Contains(entries, "info:otelx.exporter.failback") {
+		t.Fatalf("expected failover and failback logs, got %v", entries)
+	}
+}
+
+func TestFailoverValidation(t *testing.T) {
+	cases := map[string]Config{
+		"no-endpoint": {ServiceName: "svc", Exporter: ExporterOTLP, Endpoint: "a:4317", Failover: &FailoverConfig{}},
+		"same":        {ServiceName: "svc", Exporter: ExporterOTLP, Endpoint: "a:4317", Failover: &FailoverConfig{Endpoint: "a:4317"}},
+		"stdout":      {ServiceName: "svc", Exporter: ExporterStdout, Failover: &FailoverConfig{Endpoint: "b:4317"}},
+		"negative":    {ServiceName: "svc", Exporter: ExporterOTLP, Failover: &FailoverConfig{Endpoint: "b:4317", FailureThreshold: -1}},
+	}
+	for name, cfg := range cases {
+		if _, err := Setup(context.Background(), cfg, nil); err == nil {
+			t.Fatalf("%s: expected validation error", name)
+		}
+	}
+
+	cfg := Config{ServiceName: "svc", Exporter: ExporterOTLP, Endpoint: "localhost:4317", Failover: &FailoverConfig{Endpoint: "localhost:4318"}}
+	prov, err := Setup(context.Background(), cfg, nil)
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	_ = prov.Shutdown(context.Background())
+}
+
+// blockingExporter never completes an export before ctx is done.
+type blockingExporter struct{ tracetest.InMemoryExporter }
+
+func (e *blockingExporter) ExportSpans(ctx context.Context, _ []sdktrace.ReadOnlySpan) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+// deadlineExporter fails exports whose context is already done, like a network exporter.
+type deadlineExporter struct{ *tracetest.InMemoryExporter }
+
+func (e deadlineExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return e.InMemoryExporter.ExportSpans(ctx, spans)
+}
+
+func TestFailoverExporterBlockedPrimary(t *testing.T) {
+	secondary := deadlineExporter{tracetest.NewInMemoryExporter()}
+	e := newFailoverExporter(&blockingExporter{}, secondary, FailoverConfig{FailureThreshold: 1}, nil)
+	spans := endedSpans(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	if err := e.ExportSpans(ctx, spans); err != nil {
+		t.Fatalf("expected the batch to be re-sent to the secondary, got %v", err)
+	}
+	if got := len(secondary.GetSpans()); got != len(spans) {
+		t.Fatalf("expected the secondary to receive the batch, got %d spans", got)
+	}
+}
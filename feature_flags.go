@@ -0,0 +1,90 @@
+package otelx
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// FeatureFlagEvaluationEvent is the span event recorded for every flag evaluation, per the
+// OpenTelemetry feature-flag semantic conventions.
+const FeatureFlagEvaluationEvent = "feature_flag.evaluation"
+
+// FlagEvaluation describes one feature-flag evaluation. Only Key is required.
+type FlagEvaluation struct {
+	Key string
+	// Provider names the flag management system, e.g. "LaunchDarkly" or "flagd".
+	Provider string
+	// Variant is the name of the served variant, e.g. "on" or "red"; Value is the served value.
+	// Either may be empty.
+	Variant string
+	Value   any
+	// Reason explains the result, e.g. "targeting_match", "default" or "error".
+	Reason string
+	// ContextID identifies the evaluation subject, usually a user or tenant id.
+	ContextID string
+	SetID     string
+	Version   string
+	// Err reports a failed evaluation; it is recorded as error.type and error.message.
+	Err error
+}
+
+// FlagEvaluationHook is called by feature-flag clients after each evaluation. RecordFlagEvaluation
+// satisfies it; adapt it to the flag SDK's hook or after-evaluation callback.
+type FlagEvaluationHook func(ctx context.Context, e FlagEvaluation)
+
+// RecordFlagEvaluation adds a FeatureFlagEvaluationEvent to the span in ctx, so traces show which
+// cohort a request was served in.
+func RecordFlagEvaluation(ctx context.Context, e FlagEvaluation) {
+	span := trace.SpanFromContext(ctx)
+	if !span.IsRecording() || e.Key == "" {
+		return
+	}
+	attrs := []attribute.KeyValue{semconv.FeatureFlagKey(e.Key)}
+	if e.Provider != "" {
+		attrs = append(attrs, semconv.FeatureFlagProviderName(e.Provider))
+	}
+	if e.Variant != "" {
+		attrs = append(attrs, semconv.FeatureFlagResultVariant(e.Variant))
+	}
+	if e.Value != nil {
+		attrs = append(attrs, flagValue(e.Value))
+	}
+	if e.Reason != "" {
+		attrs = append(attrs, semconv.FeatureFlagResultReasonKey.String(e.Reason))
+	}
+	if e.ContextID != "" {
+		attrs = append(attrs, semconv.FeatureFlagContextID(e.ContextID))
+	}
+	if e.SetID != "" {
+		attrs = append(attrs, semconv.FeatureFlagSetID(e.SetID))
+	}
+	if e.Version != "" {
+		attrs = append(attrs, semconv.FeatureFlagVersion(e.Version))
+	}
+	if e.Err != nil {
+		attrs = append(attrs, semconv.ErrorTypeKey.String(fmt.Sprintf("%T", e.Err)), semconv.ErrorMessage(e.Err.Error()))
+	}
+	span.AddEvent(FeatureFlagEvaluationEvent, trace.WithAttributes(attrs...))
+}
+
+func flagValue(v any) attribute.KeyValue {
+	key := semconv.FeatureFlagResultValueKey
+	switch v := v.(type) {
+	case string:
+		return key.String(v)
+	case bool:
+		return key.Bool(v)
+	case int:
+		return key.Int(v)
+	case int64:
+		return key.Int64(v)
+	case float64:
+		return key.Float64(v)
+	default:
+		return key.String(fmt.Sprint(v))
+	}
+}
@@ -0,0 +1,49 @@
+package otelx
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+)
+
+func TestRecordFlagEvaluation(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	defer tp.Shutdown(context.Background())
+
+	var hook FlagEvaluationHook = RecordFlagEvaluation
+	ctx, span := tp.Tracer("test").Start(context.Background(), "op")
+	hook(ctx, FlagEvaluation{Key: "new-player", Provider: "flagd", Variant: "on", Value: true, Reason: "targeting_match", ContextID: "user-1"})
+	hook(ctx, FlagEvaluation{Key: "quality", Value: 720, Reason: "error", Err: errors.New("flag not found")})
+	span.End()
+
+	events := recorder.Ended()[0].Events()
+	if len(events) != 2 || events[0].Name != FeatureFlagEvaluationEvent {
+		t.Fatalf("expected two %s events, got %v", FeatureFlagEvaluationEvent, events)
+	}
+	first := attribute.NewSet(events[0].Attributes...)
+	for key, want := range map[attribute.Key]string{
+		semconv.FeatureFlagKeyKey:           "new-player",
+		semconv.FeatureFlagProviderNameKey:  "flagd",
+		semconv.FeatureFlagResultVariantKey: "on",
+		semconv.FeatureFlagResultValueKey:   "true",
+		semconv.FeatureFlagResultReasonKey:  "targeting_match",
+		semconv.FeatureFlagContextIDKey:     "user-1",
+	} {
+		if v, _ := first.Value(key); v.Emit() != want {
+			t.Fatalf("expected %s=%s, got %q", key, want, v.Emit())
+		}
+	}
+	second := attribute.NewSet(events[1].Attributes...)
+	if v, _ := second.Value(semconv.FeatureFlagResultValueKey); v.AsInt64() != 720 {
+		t.Fatalf("expected the int value to be kept, got %v", v.Emit())
+	}
+	if v, _ := second.Value(semconv.ErrorMessageKey); v.AsString() != "flag not found" {
+		t.Fatalf("expected error.message, got %q", v.AsString())
+	}
+}
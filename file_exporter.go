@@ -0,0 +1,166 @@
+package otelx
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+const (
+	// DefaultFileMaxBytes is the rotation threshold used when Config.FileMaxBytes is unset.
+	DefaultFileMaxBytes int64 = 100 << 20
+	// DefaultFileMaxBackups is the number of rotated files kept when Config.FileMaxBackups is unset.
+	DefaultFileMaxBackups = 5
+)
+
+// fileExporter appends one OTLP-JSON ExportTraceServiceRequest per line to path, rotating the file
+// by size so a log agent can tail and ship it.
+type fileExporter struct {
+	path       string
+	maxBytes   int64
+	maxBackups int
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+}
+
+func newFileExporter(path string, maxBytes int64, maxBackups int) (*fileExporter, error) {
+	if maxBytes <= 0 {
+		maxBytes = DefaultFileMaxBytes
+	}
+	if maxBackups <= 0 {
+		maxBackups = DefaultFileMaxBackups
+	}
+	e := &fileExporter{path: path, maxBytes: maxBytes, maxBackups: maxBackups}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	if err := e.open(); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+func (e *fileExporter) ExportSpans(_ context.Context, spans []sdktrace.ReadOnlySpan) error {
+	if len(spans) == 0 {
+		return nil
+	}
+	line, err := marshalOTLPJSON(&coltracepb.ExportTraceServiceRequest{ResourceSpans: spansToProto(spans)})
+	if err != nil {
+		return fmt.Errorf("otelx: encode spans: %w", err)
+	}
+	line = append(line, '\n')
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.file == nil {
+		return fmt.Errorf("otelx: file exporter is shut down")
+	}
+	if e.size > 0 && e.size+int64(len(line)) > e.maxBytes {
+		if err := e.rotate(); err != nil {
+			return fmt.Errorf("otelx: rotate %s: %w", e.path, err)
+		}
+	}
+	n, err := e.file.Write(line)
+	e.size += int64(n)
+	if err != nil {
+		return fmt.Errorf("otelx: write %s: %w", e.path, err)
+	}
+	return nil
+}
+
+func (e *fileExporter) Shutdown(context.Context) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.file == nil {
+		return nil
+	}
+	err := e.file.Close()
+	e.file = nil
+	return err
+}
+
+func (e *fileExporter) open() error {
+	f, err := os.OpenFile(e.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+	e.file = f
+	e.size = info.Size()
+	return nil
+}
+
+// rotate shifts path.N to path.N+1, dropping the oldest beyond maxBackups, moves the current file
+// to path.1 and reopens path. Callers hold e.mu.
+func (e *fileExporter) rotate() error {
+	if err := e.file.Close(); err != nil {
+		return err
+	}
+	e.file = nil
+	_ = os.Remove(e.backupPath(e.maxBackups))
+	for i := e.maxBackups - 1; i >= 1; i-- {
+		if err := os.Rename(e.backupPath(i), e.backupPath(i+1)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	if err := os.Rename(e.path, e.backupPath(1)); err != nil {
+		return err
+	}
+	return e.open()
+}
+
+func (e *fileExporter) backupPath(n int) string {
+	return fmt.Sprintf("%s.%d", e.path, n)
+}
+
+// otlpIDFields are the bytes fields OTLP/JSON encodes as hex rather than protobuf JSON's base64.
+var otlpIDFields = map[string]bool{"traceId": true, "spanId": true, "parentSpanId": true}
+
+// marshalOTLPJSON encodes req following the OTLP/JSON rules: enum values as integers and trace/span
+// ids as hex strings.
+func marshalOTLPJSON(req *coltracepb.ExportTraceServiceRequest) ([]byte, error) {
+	raw, err := protojson.MarshalOptions{UseEnumNumbers: true}.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	var doc any
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, err
+	}
+	hexIDs(doc)
+	return json.Marshal(doc)
+}
+
+func hexIDs(node any) {
+	switch v := node.(type) {
+	case map[string]any:
+		for key, child := range v {
+			if s, ok := child.(string); ok && otlpIDFields[key] {
+				if b, err := base64.StdEncoding.DecodeString(s); err == nil {
+					v[key] = hex.EncodeToString(b)
+				}
+				continue
+			}
+			hexIDs(child)
+		}
+	case []any:
+		for _, child := range v {
+			hexIDs(child)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package otelx
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func TestFileExporterWritesOTLPJSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "traces", "spans.jsonl")
+	exporter, err := newFileExporter(path, 0, 0)
+	if err != nil {
+		t.Fatalf("create exporter: %v", err)
+	}
+	span := spanWithAttributes(t)
+	if err := exporter.ExportSpans(context.Background(), []sdktrace.ReadOnlySpan{span}); err != nil {
+		t.Fatalf("export failed: %v", err)
+	}
+	if err := exporter.Shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown failed: %v", err)
+	}
+
+	lines := readLines(t, path)
+	if len(lines) != 1 {
+		t.Fatalf("expected one line per batch, got %d", len(lines))
+	}
+	var req struct {
+		ResourceSpans []struct {
+			ScopeSpans []struct {
+				Spans []struct {
+					TraceID string `json:"traceId"`
+					SpanID  string `json:"spanId"`
+					Kind    int    `json:"kind"`
+				} `json:"spans"`
+			} `json:"scopeSpans"`
+		} `json:"resourceSpans"`
+	}
+	if err := json.Unmarshal([]byte(lines[0]), &req); err != nil {
+		t.Fatalf("line is not valid JSON: %v", err)
+	}
+	got := req.ResourceSpans[0].ScopeSpans[0].Spans[0]
+	if got.TraceID != span.SpanContext().TraceID().String() || got.SpanID != span.SpanContext().SpanID().String() {
+		t.Fatalf("expected hex ids %s/%s, got %s/%s", span.SpanContext().TraceID(), span.SpanContext().SpanID(), got.TraceID, got.SpanID)
+	}
+	if got.Kind != int(span.SpanKind()) {
+		t.Fatalf("expected numeric span kind %d, got %d", span.SpanKind(), got.Kind)
+	}
+}
+
+func TestFileExporterRotatesAndPrunes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spans.jsonl")
+	exporter, err := newFileExporter(path, 1, 2)
+	if err != nil {
+		t.Fatalf("create exporter: %v", err)
+	}
+	spans := []sdktrace.ReadOnlySpan{spanWithAttributes(t)}
+	for i := 0; i < 4; i++ {
+		if err := exporter.ExportSpans(context.Background(), spans); err != nil {
+			t.Fatalf("export %d failed: %v", i, err)
+		}
+	}
+	_ = exporter.Shutdown(context.Background())
+
+	for _, p := range []string{path, path + ".1", path + ".2"} {
+		if lines := readLines(t, p); len(lines) != 1 {
+			t.Fatalf("expected %s to hold one batch, got %d", p, len(lines))
+		}
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Fatalf("expected backups beyond fileMaxBackups to be pruned, got %v", err)
+	}
+}
+
+func TestSetupFileExporter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spans.jsonl")
+	prov, err := Setup(context.Background(), Config{ServiceName: "svc", Exporter: ExporterFile, FilePath: path, SamplingRatio: Float64(1)}, noopLogger{})
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	_, span := prov.TP.Tracer("test").Start(context.Background(), "op")
+	span.End()
+	if err := prov.Shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown failed: %v", err)
+	}
+	if lines := readLines(t, path); len(lines) != 1 {
+		t.Fatalf("expected spans flushed to file, got %d lines", len(lines))
+	}
+
+	for name, cfg := range map[string]Config{
+		"missing path":   {ServiceName: "svc", Exporter: ExporterFile},
+		"path elsewhere": {ServiceName: "svc", Exporter: ExporterStdout, FilePath: path},
+	} {
+		if _, err := Setup(context.Background(), cfg, nil); err == nil {
+			t.Fatalf("%s: expected validation error", name)
+		}
+	}
+}
+
+func readLines(t *testing.T, path string) []string {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("open %s: %v", path, err)
+	}
+	defer f.Close()
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	return lines
+}
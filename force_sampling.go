@@ -0,0 +1,108 @@
+package otelx
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/stats"
+)
+
+const (
+	// DefaultForceSampleHeader is the request header honoured by HTTPForceSampling and
+	// GRPCServerHandlerWithForceSampling when no header is given.
+	DefaultForceSampleHeader = "X-Debug-Trace"
+	// ForceSampledKey marks the local root span of a force-sampled request.
+	ForceSampledKey = attribute.Key("otelx.force_sampled")
+)
+
+type forceSampleContextKey struct{}
+
+// ForceSample returns a context whose spans are sampled regardless of the configured sampler, for
+// providers built by Setup or samplers wrapped with ForceSampler.
+func ForceSample(ctx context.Context) context.Context {
+	return context.WithValue(ctx, forceSampleContextKey{}, true)
+}
+
+// ForceSampler samples spans started from a ForceSample context and defers everything else to
+// next. Setup installs it already; use it for tracer providers built by hand.
+func ForceSampler(next sdktrace.Sampler) sdktrace.Sampler {
+	return forceSampler{next}
+}
+
+type forceSampler struct {
+	sdktrace.Sampler
+}
+
+func (s forceSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	if p.ParentContext == nil || p.ParentContext.Value(forceSampleContextKey{}) == nil {
+		return s.Sampler.ShouldSample(p)
+	}
+	parent := trace.SpanContextFromContext(p.ParentContext)
+	result := sdktrace.SamplingResult{Decision: sdktrace.RecordAndSample, Tracestate: parent.TraceState()}
+	if isLocalRoot(parent) {
+		result.Attributes = []attribute.KeyValue{ForceSampledKey.Bool(true)}
+	}
+	return result
+}
+
+// isForceSampled reports whether span is the local root of a force-sampled request.
+func isForceSampled(span sdktrace.ReadOnlySpan) bool {
+	for _, kv := range span.Attributes() {
+		if kv.Key == ForceSampledKey {
+			return kv.Value.AsBool()
+		}
+	}
+	return false
+}
+
+// forceSampleRequested reports whether a debug header value asks for sampling ("1", "true", ...).
+func forceSampleRequested(value string) bool {
+	on, err := strconv.ParseBool(strings.TrimSpace(value))
+	return err == nil && on
+}
+
+// HTTPForceSampling samples every request whose header (DefaultForceSampleHeader when empty) is
+// set to a true value such as "1", so a single reproduced request can be traced in full in
+// production. Install it outside HTTPHandler: the server span must start from the marked context.
+// The header is client controlled; strip it at the edge if untrusted callers must not use it.
+func HTTPForceSampling(header string, next http.Handler) http.Handler {
+	if header == "" {
+		header = DefaultForceSampleHeader
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if forceSampleRequested(r.Header.Get(header)) {
+			r = r.WithContext(ForceSample(r.Context()))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// GRPCServerHandlerWithForceSampling returns an otelgrpc server handler that samples every call
+// whose incoming metadata carries header (DefaultForceSampleHeader when empty) set to a true value.
+func GRPCServerHandlerWithForceSampling(header string, opts ...otelgrpc.Option) stats.Handler {
+	if header == "" {
+		header = DefaultForceSampleHeader
+	}
+	return &forceSamplingServerHandler{Handler: otelgrpc.NewServerHandler(opts...), header: strings.ToLower(header)}
+}
+
+type forceSamplingServerHandler struct {
+	stats.Handler
+	header string
+}
+
+func (h *forceSamplingServerHandler) TagRPC(ctx context.Context, info *stats.RPCTagInfo) context.Context {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(h.header); len(values) > 0 && forceSampleRequested(values[0]) {
+			ctx = ForceSample(ctx)
+		}
+	}
+	return h.Handler.TagRPC(ctx, info)
+}
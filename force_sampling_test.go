@@ -0,0 +1,79 @@
+package otelx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/stats"
+)
+
+func TestHTTPForceSamplingOverridesRatio(t *testing.T) {
+	cfg := Config{ServiceName: "svc", Exporter: ExporterMemory, SamplingRatio: Float64(0)}
+	prov, err := Setup(context.Background(), cfg, nil)
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	defer prov.Shutdown(context.Background())
+
+	child := func(w http.ResponseWriter, r *http.Request) {
+		_, span := prov.TP.Tracer("test").Start(r.Context(), "child")
+		span.End()
+	}
+	handler := HTTPForceSampling("", HTTPHandler("op", http.HandlerFunc(child), otelhttp.WithTracerProvider(prov.TP)))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://localhost/", nil))
+	req := httptest.NewRequest(http.MethodGet, "http://localhost/", nil)
+	req.Header.Set(DefaultForceSampleHeader, "0")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	if err := prov.TP.ForceFlush(context.Background()); err != nil {
+		t.Fatalf("flush failed: %v", err)
+	}
+	if got := len(prov.RecordedSpans()); got != 0 {
+		t.Fatalf("expected requests without the header to follow the ratio, got %d spans", got)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "http://localhost/", nil)
+	req.Header.Set(DefaultForceSampleHeader, "1")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	if err := prov.TP.ForceFlush(context.Background()); err != nil {
+		t.Fatalf("flush failed: %v", err)
+	}
+	spans := prov.RecordedSpans()
+	if len(spans) != 2 {
+		t.Fatalf("expected the server and child span to be sampled, got %d", len(spans))
+	}
+	for _, s := range spans {
+		marked := spanHasAttribute(s.Attributes, ForceSampledKey, "true")
+		if marked != (s.Name == "op") {
+			t.Fatalf("expected only the root span %q to carry %s, got it on %q=%v", "op", ForceSampledKey, s.Name, marked)
+		}
+	}
+}
+
+func TestGRPCServerHandlerWithForceSampling(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithSampler(ForceSampler(sdktrace.NeverSample())),
+		sdktrace.WithSpanProcessor(recorder))
+	defer tp.Shutdown(context.Background())
+
+	h := GRPCServerHandlerWithForceSampling("x-debug", otelgrpc.WithTracerProvider(tp))
+	call := func(ctx context.Context) {
+		ctx = h.TagRPC(ctx, &stats.RPCTagInfo{FullMethodName: "/svc.v1.Svc/Get"})
+		h.HandleRPC(ctx, &stats.End{})
+	}
+	call(metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-debug", "false")))
+	call(metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-debug", "true")))
+
+	ended := recorder.Ended()
+	if len(ended) != 1 || !ended[0].SpanContext().IsSampled() {
+		t.Fatalf("expected only the flagged call to be sampled, got %d spans", len(ended))
+	}
+}
@@ -0,0 +1,138 @@
+package otelx
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// DefaultPayloadMaxBytes caps request snapshots when PayloadSampling.MaxBytes is zero.
+const DefaultPayloadMaxBytes = 1024
+
+// Span event and attributes written by the payload sampling interceptors.
+const (
+	PayloadEventName = "rpc.request.snapshot"
+	PayloadTypeKey   = attribute.Key("rpc.request.type")
+	PayloadBodyKey   = attribute.Key("rpc.request.body")
+)
+
+// PayloadSampling configures GRPCPayloadUnaryInterceptor and GRPCPayloadStreamInterceptor.
+type PayloadSampling struct {
+	// Fields lists the proto field names to keep, dotted for nested messages ("user.id"). Every
+	// other field is removed, so secrets never leave the process unless explicitly allowed.
+	Fields []string
+	// MaxBytes truncates the JSON snapshot (default DefaultPayloadMaxBytes), marking the cut like
+	// WithAttributeTruncation does.
+	MaxBytes int
+}
+
+// GRPCPayloadUnaryInterceptor attaches an allowlisted JSON snapshot of the request message as a
+// PayloadEventName span event when the handler returns an error. Successful RPCs record nothing.
+// The span is the one started by GRPCServerHandler.
+func GRPCPayloadUnaryInterceptor(cfg PayloadSampling) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		resp, err := handler(ctx, req)
+		if err != nil {
+			cfg.record(ctx, req)
+		}
+		return resp, err
+	}
+}
+
+// GRPCPayloadStreamInterceptor is the streaming counterpart of GRPCPayloadUnaryInterceptor. It
+// snapshots the first message the client sent.
+func GRPCPayloadStreamInterceptor(cfg PayloadSampling) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		stream := &payloadServerStream{ServerStream: ss}
+		err := handler(srv, stream)
+		if err != nil && stream.first != nil {
+			cfg.record(ss.Context(), stream.first)
+		}
+		return err
+	}
+}
+
+type payloadServerStream struct {
+	grpc.ServerStream
+	first any
+}
+
+func (s *payloadServerStream) RecvMsg(m any) error {
+	err := s.ServerStream.RecvMsg(m)
+	if err == nil && s.first == nil {
+		s.first = m
+	}
+	return err
+}
+
+func (cfg PayloadSampling) record(ctx context.Context, msg any) {
+	span := trace.SpanFromContext(ctx)
+	if !span.IsRecording() {
+		return
+	}
+	pm, ok := msg.(proto.Message)
+	if !ok {
+		return
+	}
+	span.AddEvent(PayloadEventName, trace.WithAttributes(
+		PayloadTypeKey.String(string(pm.ProtoReflect().Descriptor().FullName())),
+		PayloadBodyKey.String(cfg.snapshot(pm)),
+	))
+}
+
+// snapshot renders msg as JSON with proto field names, keeping only the allowlisted fields.
+func (cfg PayloadSampling) snapshot(msg proto.Message) string {
+	raw, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(msg)
+	if err != nil {
+		return ""
+	}
+	var full map[string]any
+	if err := json.Unmarshal(raw, &full); err != nil {
+		return ""
+	}
+	kept := map[string]any{}
+	for _, path := range cfg.Fields {
+		if value, ok := lookupPath(full, strings.Split(path, ".")); ok {
+			storePath(kept, strings.Split(path, "."), value)
+		}
+	}
+	out, _ := json.Marshal(kept)
+
+	maxBytes := cfg.MaxBytes
+	if maxBytes <= 0 {
+		maxBytes = DefaultPayloadMaxBytes
+	}
+	snapshot, _ := truncateBytes(string(out), maxBytes)
+	return snapshot
+}
+
+func lookupPath(m map[string]any, path []string) (any, bool) {
+	value, ok := m[path[0]]
+	if !ok || len(path) == 1 {
+		return value, ok
+	}
+	nested, isMap := value.(map[string]any)
+	if !isMap {
+		return nil, false
+	}
+	return lookupPath(nested, path[1:])
+}
+
+func storePath(m map[string]any, path []string, value any) {
+	if len(path) == 1 {
+		m[path[0]] = value
+		return
+	}
+	nested, ok := m[path[0]].(map[string]any)
+	if !ok {
+		nested = map[string]any{}
+		m[path[0]] = nested
+	}
+	storePath(nested, path[1:], value)
+}
@@ -0,0 +1,70 @@
+package otelx
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/grpc"
+)
+
+func TestGRPCPayloadUnaryInterceptorSnapshotsFailedRequests(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	defer tp.Shutdown(context.Background())
+
+	interceptor := GRPCPayloadUnaryInterceptor(PayloadSampling{Fields: []string{"name", "status.message", "missing"}})
+	req := &tracepb.Span{
+		Name:    "checkout",
+		TraceId: []byte("secret-trace-id!"),
+		Status:  &tracepb.Status{Message: "boom", Code: tracepb.Status_STATUS_CODE_ERROR},
+	}
+	call := func(fail bool) {
+		ctx, span := tp.Tracer("test").Start(context.Background(), "rpc")
+		defer span.End()
+		_, _ = interceptor(ctx, req, &grpc.UnaryServerInfo{}, func(context.Context, any) (any, error) {
+			if fail {
+				return nil, errors.New("failed")
+			}
+			return nil, nil
+		})
+	}
+	call(false)
+	call(true)
+
+	ended := recorder.Ended()
+	if len(ended[0].Events()) != 0 {
+		t.Fatalf("expected no snapshot on successful RPC")
+	}
+	events := ended[1].Events()
+	if len(events) != 1 || events[0].Name != PayloadEventName {
+		t.Fatalf("expected snapshot event, got %v", events)
+	}
+	var body, typ string
+	for _, kv := range events[0].Attributes {
+		switch kv.Key {
+		case PayloadBodyKey:
+			body = kv.Value.AsString()
+		case PayloadTypeKey:
+			typ = kv.Value.AsString()
+		}
+	}
+	if typ != "opentelemetry.proto.trace.v1.Span" {
+		t.Fatalf("unexpected message type %q", typ)
+	}
+	if body != `{"name":"checkout","status":{"message":"boom"}}` {
+		t.Fatalf("unexpected snapshot %s", body)
+	}
+}
+
+func TestPayloadSnapshotTruncates(t *testing.T) {
+	cfg := PayloadSampling{Fields: []string{"name"}, MaxBytes: 16}
+	got := cfg.snapshot(&tracepb.Span{Name: strings.Repeat("x", 64)})
+	if !strings.HasPrefix(got, `{"name":"xxxxxxx…(+`) {
+		t.Fatalf("expected truncated snapshot, got %s", got)
+	}
+}
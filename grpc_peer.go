@@ -0,0 +1,94 @@
+package otelx
+
+import (
+	"context"
+	"net"
+	"net/url"
+	"strings"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/stats"
+)
+
+// Attribute keys recorded by GRPCClientHandlerForTarget.
+const (
+	GRPCTargetKey       = attribute.Key("rpc.grpc.target")
+	GRPCXDSAuthorityKey = attribute.Key("rpc.grpc.xds.authority")
+	GRPCXDSServiceKey   = attribute.Key("rpc.grpc.xds.service")
+)
+
+// PeerAttributesFunc resolves extra span attributes for the backend a client RPC attempt was
+// routed to, e.g. the xDS cluster or locality owning the peer address.
+type PeerAttributesFunc func(ctx context.Context, addr net.Addr) []attribute.KeyValue
+
+// GRPCClientHandlerForTarget returns a client stats handler that extends the otelgrpc handler with
+// the dial target and the resolved backend peer of every RPC attempt. For xds:// targets the xDS
+// authority and service name are recorded too. peerAttrs may be nil.
+func GRPCClientHandlerForTarget(target string, peerAttrs PeerAttributesFunc, opts ...otelgrpc.Option) stats.Handler {
+	return &peerClientHandler{
+		Handler:     otelgrpc.NewClientHandler(opts...),
+		targetAttrs: targetAttributes(target),
+		peerAttrs:   peerAttrs,
+	}
+}
+
+type peerClientHandler struct {
+	stats.Handler
+	targetAttrs []attribute.KeyValue
+	peerAttrs   PeerAttributesFunc
+}
+
+func (h *peerClientHandler) TagRPC(ctx context.Context, info *stats.RPCTagInfo) context.Context {
+	ctx = h.Handler.TagRPC(ctx, info)
+	if len(h.targetAttrs) > 0 {
+		trace.SpanFromContext(ctx).SetAttributes(h.targetAttrs...)
+	}
+	return ctx
+}
+
+func (h *peerClientHandler) HandleRPC(ctx context.Context, rs stats.RPCStats) {
+	if hdr, ok := rs.(*stats.OutHeader); ok && hdr.Client && hdr.RemoteAddr != nil {
+		span := trace.SpanFromContext(ctx)
+		span.SetAttributes(peerAddrAttributes(hdr.RemoteAddr)...)
+		if h.peerAttrs != nil {
+			if attrs := h.peerAttrs(ctx, hdr.RemoteAddr); len(attrs) > 0 {
+				span.SetAttributes(attrs...)
+			}
+		}
+	}
+	h.Handler.HandleRPC(ctx, rs)
+}
+
+func targetAttributes(target string) []attribute.KeyValue {
+	target = strings.TrimSpace(target)
+	if target == "" {
+		return nil
+	}
+	attrs := []attribute.KeyValue{GRPCTargetKey.String(target)}
+	u, err := url.Parse(target)
+	if err != nil || u.Scheme != "xds" {
+		return attrs
+	}
+	if u.Host != "" {
+		attrs = append(attrs, GRPCXDSAuthorityKey.String(u.Host))
+	}
+	if service := strings.TrimPrefix(u.Path, "/"); service != "" {
+		attrs = append(attrs, GRPCXDSServiceKey.String(service))
+	}
+	return attrs
+}
+
+func peerAddrAttributes(addr net.Addr) []attribute.KeyValue {
+	switch a := addr.(type) {
+	case *net.TCPAddr:
+		return []attribute.KeyValue{
+			semconv.NetworkPeerAddress(a.IP.String()),
+			semconv.NetworkPeerPort(a.Port),
+		}
+	default:
+		return []attribute.KeyValue{semconv.NetworkPeerAddress(addr.String())}
+	}
+}
@@ -0,0 +1,47 @@
+package otelx
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"google.golang.org/grpc/stats"
+)
+
+func TestGRPCClientHandlerForTargetRecordsPeer(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	defer tp.Shutdown(context.Background())
+
+	localityOf := func(_ context.Context, addr net.Addr) []attribute.KeyValue {
+		return []attribute.KeyValue{attribute.String("rpc.grpc.xds.locality", "us-east1-b")}
+	}
+	h := GRPCClientHandlerForTarget("xds://td.example.com/orders", localityOf, otelgrpc.WithTracerProvider(tp))
+
+	ctx := h.TagRPC(context.Background(), &stats.RPCTagInfo{FullMethodName: "/orders.v1.Orders/Get"})
+	h.HandleRPC(ctx, &stats.Begin{Client: true})
+	h.HandleRPC(ctx, &stats.OutHeader{Client: true, RemoteAddr: &net.TCPAddr{IP: net.ParseIP("10.0.0.7"), Port: 8443}})
+	h.HandleRPC(ctx, &stats.End{Client: true})
+
+	ended := recorder.Ended()
+	if len(ended) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(ended))
+	}
+	attrs := ended[0].Attributes()
+	for key, want := range map[attribute.Key]string{
+		GRPCTargetKey:           "xds://td.example.com/orders",
+		GRPCXDSAuthorityKey:     "td.example.com",
+		GRPCXDSServiceKey:       "orders",
+		"network.peer.address":  "10.0.0.7",
+		"network.peer.port":     "8443",
+		"rpc.grpc.xds.locality": "us-east1-b",
+	} {
+		if !spanHasAttribute(attrs, key, want) {
+			t.Fatalf("expected %s=%s, got %v", key, want, attrs)
+		}
+	}
+}
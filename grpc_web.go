@@ -0,0 +1,139 @@
+package otelx
+
+import (
+	"net/http"
+	"strings"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// GRPCWebHandler instruments a gRPC-Web endpoint, such as an in-process grpcweb wrapper or a Go
+// proxy in front of the gRPC server, so browser calls join the backend trace. The server span
+// continues the trace context sent by the web client, is named after the gRPC method
+// ("svc.v1.Svc/Get") and carries rpc.system/rpc.service/rpc.method; the context is re-injected
+// into the request headers, so the gRPC server span nests below it. CORS responses are extended
+// through HTTPTraceCORS. Requests that are not gRPC-Web pass through as plain HTTP spans.
+func GRPCWebHandler(handler http.Handler, opts ...otelhttp.Option) http.Handler {
+	inner := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if isGRPCWeb(r) {
+			if service, method, ok := splitFullMethod(r.URL.Path); ok {
+				trace.SpanFromContext(r.Context()).SetAttributes(
+					semconv.RPCSystemGRPC, semconv.RPCService(service), semconv.RPCMethod(method))
+			}
+			r = r.Clone(r.Context())
+			otel.GetTextMapPropagator().Inject(r.Context(), propagation.HeaderCarrier(r.Header))
+		}
+		handler.ServeHTTP(w, r)
+	})
+	opts = append([]otelhttp.Option{otelhttp.WithSpanNameFormatter(grpcWebSpanName)}, opts...)
+	return HTTPTraceCORS(otelhttp.NewHandler(inner, "grpc-web", opts...))
+}
+
+func isGRPCWeb(r *http.Request) bool {
+	return r.Method == http.MethodPost && strings.HasPrefix(r.Header.Get("Content-Type"), "application/grpc-web")
+}
+
+func grpcWebSpanName(operation string, r *http.Request) string {
+	if isGRPCWeb(r) {
+		if _, _, ok := splitFullMethod(r.URL.Path); ok {
+			return strings.TrimPrefix(r.URL.Path, "/")
+		}
+	}
+	return operation
+}
+
+// splitFullMethod splits "/svc.v1.Svc/Get" into its service and method.
+func splitFullMethod(path string) (service, method string, ok bool) {
+	service, method, ok = strings.Cut(strings.TrimPrefix(path, "/"), "/")
+	return service, method, ok && service != "" && method != "" && !strings.Contains(method, "/")
+}
+
+// HTTPTraceCORS extends the CORS headers written by next (or by a CORS middleware inside it) so
+// browsers may send the global propagator's headers (traceparent, tracestate, baggage) and read
+// exposeHeaders, e.g. DefaultTraceIDHeader. Responses without Access-Control-Allow-Origin and
+// wildcard lists are left unchanged.
+func HTTPTraceCORS(next http.Handler, exposeHeaders ...string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(&corsWriter{ResponseWriter: w, expose: exposeHeaders}, r)
+	})
+}
+
+type corsWriter struct {
+	http.ResponseWriter
+	expose      []string
+	wroteHeader bool
+}
+
+func (w *corsWriter) WriteHeader(code int) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		if h := w.Header(); h.Get("Access-Control-Allow-Origin") != "" {
+			if h.Get("Access-Control-Allow-Headers") != "" {
+				appendHeaderList(h, "Access-Control-Allow-Headers", propagationHeaders())
+			}
+			appendHeaderList(h, "Access-Control-Expose-Headers", w.expose)
+		}
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *corsWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *corsWriter) Flush() {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (w *corsWriter) Unwrap() http.ResponseWriter { return w.ResponseWriter }
+
+// propagationHeaders lists the global propagator's headers, falling back to W3C trace context
+// and baggage while no global propagator is installed.
+func propagationHeaders() []string {
+	if fields := otel.GetTextMapPropagator().Fields(); len(fields) > 0 {
+		return fields
+	}
+	return append(propagation.TraceContext{}.Fields(), propagation.Baggage{}.Fields()...)
+}
+
+// appendHeaderList adds the names missing from the comma-separated header key.
+func appendHeaderList(h http.Header, key string, names []string) {
+	current := h.Get(key)
+	if current == "*" {
+		return
+	}
+	list := current
+	for _, name := range names {
+		if name == "" || headerListContains(current, name) {
+			continue
+		}
+		if list != "" {
+			list += ", "
+		}
+		list += name
+	}
+	if list != "" {
+		h.Set(key, list)
+	}
+}
+
+func headerListContains(list, name string) bool {
+	for _, item := range strings.Split(list, ",") {
+		if strings.EqualFold(strings.TrimSpace(item), name) {
+			return true
+		}
+	}
+	return false
+}
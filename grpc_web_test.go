@@ -0,0 +1,90 @@
+package otelx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestGRPCWebHandlerJoinsBrowserTrace(t *testing.T) {
+	defer saveGlobal()()
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	defer tp.Shutdown(context.Background())
+
+	var forwarded trace.SpanContext
+	backend := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		forwarded = trace.SpanContextFromContext(otel.GetTextMapPropagator().Extract(context.Background(), propagation.HeaderCarrier(r.Header)))
+		w.Header().Set("Access-Control-Allow-Origin", "https://app.example.com")
+		w.Header().Set("Access-Control-Allow-Headers", "content-type, x-grpc-web")
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := GRPCWebHandler(backend, otelhttp.WithTracerProvider(tp))
+
+	const traceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	req := httptest.NewRequest(http.MethodPost, "http://localhost/video.v1.VideoService/GetVideo", nil)
+	req.Header.Set("Content-Type", "application/grpc-web+proto")
+	req.Header.Set("traceparent", traceparent)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	ended := recorder.Ended()
+	if len(ended) != 1 {
+		t.Fatalf("expected one server span, got %d", len(ended))
+	}
+	span := ended[0]
+	if span.Name() != "video.v1.VideoService/GetVideo" {
+		t.Fatalf("expected the span to be named after the method, got %q", span.Name())
+	}
+	if span.SpanContext().TraceID().String() != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Fatalf("expected the browser trace to continue, got %s", span.SpanContext().TraceID())
+	}
+	if !spanHasAttribute(span.Attributes(), semconv.RPCServiceKey, "video.v1.VideoService") ||
+		!spanHasAttribute(span.Attributes(), semconv.RPCMethodKey, "GetVideo") {
+		t.Fatalf("expected rpc.service and rpc.method, got %v", span.Attributes())
+	}
+	if forwarded.SpanID() != span.SpanContext().SpanID() {
+		t.Fatalf("expected the backend to receive the gRPC-Web span as parent")
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Headers"); got != "content-type, x-grpc-web, traceparent, tracestate" {
+		t.Fatalf("expected trace headers to be allowed, got %q", got)
+	}
+}
+
+func TestHTTPTraceCORS(t *testing.T) {
+	cors := func(origin string) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if origin != "" {
+				w.Header().Set("Access-Control-Allow-Origin", origin)
+				w.Header().Set("Access-Control-Expose-Headers", "grpc-status")
+			}
+			_, _ = w.Write([]byte("ok"))
+		})
+	}
+
+	rec := httptest.NewRecorder()
+	HTTPTraceCORS(cors("*"), DefaultTraceIDHeader, "Grpc-Status").ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if got := rec.Header().Get("Access-Control-Expose-Headers"); got != "grpc-status, "+DefaultTraceIDHeader {
+		t.Fatalf("expected the trace id header to be exposed once, got %q", got)
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Headers"); got != "" {
+		t.Fatalf("expected no allow list outside preflight, got %q", got)
+	}
+
+	rec = httptest.NewRecorder()
+	HTTPTraceCORS(cors(""), DefaultTraceIDHeader).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if strings.Contains(rec.Header().Get("Access-Control-Expose-Headers"), DefaultTraceIDHeader) {
+		t.Fatalf("expected non-CORS responses to be left alone")
+	}
+}
@@ -0,0 +1,120 @@
+package otelx
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// headerRefPattern matches secret references in header values: ${env:NAME} or ${file:/path}.
+var headerRefPattern = regexp.MustCompile(`\$\{([A-Za-z]+):([^}]*)\}`)
+
+// validateHeaderRefs checks the syntax of the references in headers without resolving them.
+func validateHeaderRefs(headers map[string]string) error {
+	for name, value := range headers {
+		for _, m := range headerRefPattern.FindAllStringSubmatch(value, -1) {
+			if err := validateHeaderRef(m[1], m[2]); err != nil {
+				return fmt.Errorf("headers[%q]: %w", name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// rejectHeaderRefs fails when a header value contains a reference. Used for exporter configs that
+// do not come from the local Config.
+func rejectHeaderRefs(headers map[string]string) error {
+	for name, value := range headers {
+		if headerRefPattern.MatchString(value) {
+			return fmt.Errorf("headers[%q]: secret references are only resolved in the local config", name)
+		}
+	}
+	return nil
+}
+
+func validateHeaderRef(kind, ref string) error {
+	if kind != "env" && kind != "file" {
+		return fmt.Errorf("unsupported reference ${%s:...}, want env or file", kind)
+	}
+	if strings.TrimSpace(ref) == "" {
+		return fmt.Errorf("empty ${%s:} reference", kind)
+	}
+	return nil
+}
+
+// resolveHeaderRefs returns headers with every ${env:NAME} replaced by the environment variable and
+// every ${file:/path} by the file's content without surrounding whitespace, so API keys can come
+// from the environment or mounted secrets instead of config files. Unset variables and unreadable
+// files are errors. headers is returned as is when it holds no references.
+func resolveHeaderRefs(headers map[string]string, lookupEnv func(string) (string, bool)) (map[string]string, error) {
+	var resolved map[string]string
+	for name, value := range headers {
+		if !headerRefPattern.MatchString(value) {
+			continue
+		}
+		var errs []error
+		value = headerRefPattern.ReplaceAllStringFunc(value, func(ref string) string {
+			m := headerRefPattern.FindStringSubmatch(ref)
+			v, err := resolveHeaderRef(m[1], strings.TrimSpace(m[2]), lookupEnv)
+			if err != nil {
+				errs = append(errs, err)
+			}
+			return v
+		})
+		if err := errors.Join(errs...); err != nil {
+			return nil, fmt.Errorf("headers[%q]: %w", name, err)
+		}
+		if resolved == nil {
+			resolved = make(map[string]string, len(headers))
+			for k, v := range headers {
+				resolved[k] = v
+			}
+		}
+		resolved[name] = value
+	}
+	if resolved == nil {
+		return headers, nil
+	}
+	return resolved, nil
+}
+
+func resolveHeaderRef(kind, ref string, lookupEnv func(string) (string, bool)) (string, error) {
+	if err := validateHeaderRef(kind, ref); err != nil {
+		return "", err
+	}
+	if kind == "env" {
+		v, ok := lookupEnv(ref)
+		if !ok {
+			return "", fmt.Errorf("environment variable %s is not set", ref)
+		}
+		return v, nil
+	}
+	data, err := os.ReadFile(ref)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// resolveHeaderRefs resolves the header references of every pipeline in cfg.
+func (cfg Config) resolveHeaderRefs(lookupEnv func(string) (string, bool)) (Config, error) {
+	headers, err := resolveHeaderRefs(cfg.Headers, lookupEnv)
+	if err != nil {
+		return cfg, fmt.Errorf("otelx: %w", err)
+	}
+	cfg.Headers = headers
+	if len(cfg.Exporters) == 0 {
+		return cfg, nil
+	}
+	exporters := make([]ExporterConfig, len(cfg.Exporters))
+	for i, ec := range cfg.Exporters {
+		if ec.Headers, err = resolveHeaderRefs(ec.Headers, lookupEnv); err != nil {
+			return cfg, fmt.Errorf("otelx: exporters[%d]: %w", i, err)
+		}
+		exporters[i] = ec
+	}
+	cfg.Exporters = exporters
+	return cfg, nil
+}
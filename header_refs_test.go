@@ -0,0 +1,105 @@
+package otelx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestResolveHeaderRefs(t *testing.T) {
+	secret := filepath.Join(t.TempDir(), "key")
+	if err := os.WriteFile(secret, []byte("file-key\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	env := map[string]string{"OTEL_API_KEY": "env-key"}
+	lookup := func(name string) (string, bool) {
+		v, ok := env[name]
+		return v, ok
+	}
+
+	headers := map[string]string{
+		"x-api-key":     "${env:OTEL_API_KEY}",
+		"authorization": "Bearer ${file:" + secret + "}",
+		"x-team":        "plain",
+	}
+	got, err := resolveHeaderRefs(headers, lookup)
+	if err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	if got["x-api-key"] != "env-key" || got["authorization"] != "Bearer file-key" || got["x-team"] != "plain" {
+		t.Fatalf("unexpected headers %v", got)
+	}
+	if headers["x-api-key"] != "${env:OTEL_API_KEY}" {
+		t.Fatal("expected the input map to stay unchanged")
+	}
+
+	for _, value := range []string{"${env:MISSING}", "${file:" + filepath.Join(t.TempDir(), "missing") + "}"} {
+		if _, err := resolveHeaderRefs(map[string]string{"k": value}, lookup); err == nil {
+			t.Fatalf("expected %q to fail", value)
+		}
+	}
+}
+
+func TestSetupResolvesHeaderRefs(t *testing.T) {
+	keys := make(chan string, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys <- r.Header.Get("x-api-key")
+	}))
+	defer srv.Close()
+	t.Setenv("OTELX_TEST_API_KEY", "secret")
+
+	cfg := Config{
+		ServiceName:   "svc",
+		Exporter:      ExporterOTLPHTTP,
+		Endpoint:      strings.TrimPrefix(srv.URL, "http://"),
+		SamplingRatio: Float64(1),
+		Headers:       map[string]string{"x-api-key": "${env:OTELX_TEST_API_KEY}"},
+	}
+	prov, err := Setup(context.Background(), cfg, nil, WithSyncExport())
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	defer prov.Shutdown(context.Background())
+	_, span := prov.TP.Tracer("test").Start(context.Background(), "op")
+	span.End()
+	if got := <-keys; got != "secret" {
+		t.Fatalf("expected the resolved header, got %q", got)
+	}
+
+	cfg.Headers = map[string]string{"x-api-key": "${vault:secret/otel}"}
+	if _, err := Setup(context.Background(), cfg, nil); err == nil {
+		t.Fatal("expected an unsupported reference to fail validation")
+	}
+	cfg.Headers = map[string]string{"x-api-key": "${env:OTELX_TEST_UNSET}"}
+	if _, err := Setup(context.Background(), cfg, nil); err == nil {
+		t.Fatal("expected an unset variable to fail setup")
+	}
+}
+
+func TestApplyRemoteConfigRejectsHeaderRefs(t *testing.T) {
+	t.Setenv("OTELX_TEST_API_KEY", "secret")
+	cfg := Config{ServiceName: "svc", Exporters: []ExporterConfig{{Exporter: ExporterMemory}}}
+	prov, err := Setup(context.Background(), cfg, noopLogger{})
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	defer prov.Shutdown(context.Background())
+
+	for _, value := range []string{"${env:OTELX_TEST_API_KEY}", "Bearer ${file:/etc/hostname}"} {
+		rc := RemoteConfig{Exporters: []ExporterConfig{{
+			Exporter: ExporterOTLPHTTP,
+			Endpoint: "collector.example.com:4318",
+			Headers:  map[string]string{"authorization": value},
+		}}}
+		if err := prov.ApplyRemoteConfig(context.Background(), rc); err == nil || !strings.Contains(err.Error(), "secret references") {
+			t.Fatalf("expected remote header %q to be rejected, got %v", value, err)
+		}
+	}
+	if got := prov.pipelines[0].config().Exporter; got != ExporterMemory {
+		t.Fatalf("expected the pipeline to stay unchanged, got %s", got)
+	}
+}
@@ -4,8 +4,12 @@ import (
 	"net/http"
 
 	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// DefaultTraceIDHeader is the response header written by HTTPTraceIDEcho when none is given.
+const DefaultTraceIDHeader = "X-Trace-Id"
+
 // HTTPHandler wraps the provided handler with OpenTelemetry instrumentation.
 func HTTPHandler(operation string, handler http.Handler, opts ...otelhttp.Option) http.Handler {
 	if operation == "" {
@@ -14,6 +18,21 @@ func HTTPHandler(operation string, handler http.Handler, opts ...otelhttp.Option
 	return otelhttp.NewHandler(handler, operation, opts...)
 }
 
+// HTTPTraceIDEcho writes the trace id of the server span to the response header (default
+// DefaultTraceIDHeader), so API consumers can quote it in support tickets. Install it inside
+// HTTPHandler: otelx.HTTPHandler("api", otelx.HTTPTraceIDEcho("", mux)).
+func HTTPTraceIDEcho(header string, next http.Handler) http.Handler {
+	if header == "" {
+		header = DefaultTraceIDHeader
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if sc := trace.SpanContextFromContext(r.Context()); sc.HasTraceID() {
+			w.Header().Set(header, sc.TraceID().String())
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
 // HTTPTransport wraps the given RoundTripper with OpenTelemetry instrumentation.
 func HTTPTransport(base http.RoundTripper, opts ...otelhttp.Option) http.RoundTripper {
 	if base == nil {
@@ -21,3 +40,10 @@ func HTTPTransport(base http.RoundTripper, opts ...otelhttp.Option) http.RoundTr
 	}
 	return otelhttp.NewTransport(base, opts...)
 }
+
+// HTTPError replies to r like http.Error and records err on the request span through RecordError,
+// so registered ErrorMappings decide whether the span is marked as failed.
+func HTTPError(w http.ResponseWriter, r *http.Request, err error, code int) {
+	RecordError(r.Context(), err)
+	http.Error(w, err.Error(), code)
+}
@@ -0,0 +1,143 @@
+package otelx
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	logx "github.com/bionicotaku/lingo-utils-logx"
+	otellog "go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/log/global"
+)
+
+// fatalFlushTimeout bounds the log flush done before a Fatal call is forwarded.
+const fatalFlushTimeout = 2 * time.Second
+
+// LogBridge returns a logx.Logger that forwards every call to logger (which may be nil) and
+// mirrors it as an OTel log record on the global LoggerProvider. Records carry the trace and span
+// ids of the span in ctx, so the backend can correlate logs with traces. Setup installs the
+// LoggerProvider when Config.Logs is set and the global option is used.
+func LogBridge(logger logx.Logger) logx.Logger {
+	return newLogBridge(logger, global.GetLoggerProvider())
+}
+
+// LogBridge is like the package-level LogBridge but emits to p.LP, falling back to the global
+// LoggerProvider when the provider was set up without logs.
+func (p *Provider) LogBridge(logger logx.Logger) logx.Logger {
+	if p == nil || p.LP == nil {
+		return LogBridge(logger)
+	}
+	return newLogBridge(logger, p.LP)
+}
+
+func newLogBridge(logger logx.Logger, provider otellog.LoggerProvider) *logBridge {
+	return &logBridge{next: logger, provider: provider, otel: provider.Logger(instrumentationName)}
+}
+
+type logBridge struct {
+	next     logx.Logger
+	provider otellog.LoggerProvider
+	otel     otellog.Logger
+	attrs    []otellog.KeyValue
+}
+
+func (b *logBridge) Debug(ctx context.Context, msg string, attrs ...logx.Attr) {
+	if b.next != nil {
+		b.next.Debug(ctx, msg, attrs...)
+	}
+	b.emit(ctx, otellog.SeverityDebug, "DEBUG", msg, nil, attrs)
+}
+
+func (b *logBridge) Info(ctx context.Context, msg string, attrs ...logx.Attr) {
+	if b.next != nil {
+		b.next.Info(ctx, msg, attrs...)
+	}
+	b.emit(ctx, otellog.SeverityInfo, "INFO", msg, nil, attrs)
+}
+
+func (b *logBridge) Warn(ctx context.Context, msg string, attrs ...logx.Attr) {
+	if b.next != nil {
+		b.next.Warn(ctx, msg, attrs...)
+	}
+	b.emit(ctx, otellog.SeverityWarn, "WARN", msg, nil, attrs)
+}
+
+func (b *logBridge) Error(ctx context.Context, msg string, err error, attrs ...logx.Attr) {
+	if b.next != nil {
+		b.next.Error(ctx, msg, err, attrs...)
+	}
+	b.emit(ctx, otellog.SeverityError, "ERROR", msg, err, attrs)
+}
+
+// Fatal emits and flushes the record before forwarding, since the wrapped logger may exit the
+// process before a batch processor exports it.
+func (b *logBridge) Fatal(ctx context.Context, msg string, err error, attrs ...logx.Attr) {
+	b.emit(ctx, otellog.SeverityFatal, "FATAL", msg, err, attrs)
+	if flusher, ok := b.provider.(interface{ ForceFlush(context.Context) error }); ok {
+		flushCtx, cancel := context.WithTimeout(context.Background(), fatalFlushTimeout)
+		_ = flusher.ForceFlush(flushCtx)
+		cancel()
+	}
+	if b.next != nil {
+		b.next.Fatal(ctx, msg, err, attrs...)
+	}
+}
+
+func (b *logBridge) With(attrs ...logx.Attr) logx.Logger {
+	child := &logBridge{provider: b.provider, otel: b.otel}
+	if b.next != nil {
+		child.next = b.next.With(attrs...)
+	}
+	child.attrs = append(append(child.attrs, b.attrs...), convertLogAttrs(attrs)...)
+	return child
+}
+
+func (b *logBridge) emit(ctx context.Context, severity otellog.Severity, severityText, msg string, err error, attrs []logx.Attr) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if !b.otel.Enabled(ctx, otellog.EnabledParameters{Severity: severity}) {
+		return
+	}
+	var record otellog.Record
+	record.SetTimestamp(time.Now())
+	record.SetSeverity(severity)
+	record.SetSeverityText(severityText)
+	record.SetBody(otellog.StringValue(msg))
+	record.AddAttributes(b.attrs...)
+	record.AddAttributes(convertLogAttrs(attrs)...)
+	if err != nil {
+		record.AddAttributes(
+			otellog.String("exception.type", fmt.Sprintf("%T", err)),
+			otellog.String("exception.message", err.Error()),
+		)
+	}
+	b.otel.Emit(ctx, record)
+}
+
+func convertLogAttrs(attrs []logx.Attr) []otellog.KeyValue {
+	out := make([]otellog.KeyValue, 0, len(attrs))
+	for _, attr := range attrs {
+		var value otellog.Value
+		switch v := any(attr.Value).(type) {
+		case string:
+			value = otellog.StringValue(v)
+		case int:
+			value = otellog.IntValue(v)
+		case int64:
+			value = otellog.Int64Value(v)
+		case float64:
+			value = otellog.Float64Value(v)
+		case bool:
+			value = otellog.BoolValue(v)
+		case time.Duration:
+			value = otellog.StringValue(v.String())
+		case error:
+			value = otellog.StringValue(v.Error())
+		default:
+			value = otellog.StringValue(fmt.Sprint(v))
+		}
+		out = append(out, otellog.KeyValue{Key: attr.Key, Value: value})
+	}
+	return out
+}
@@ -0,0 +1,105 @@
+package otelx
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	logx "github.com/bionicotaku/lingo-utils-logx"
+	otellog "go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+)
+
+func TestLogBridgeMirrorsRecords(t *testing.T) {
+	exporter := &memoryLogExporter{}
+	cfg := Config{ServiceName: "svc", Exporter: ExporterMemory, SamplingRatio: Float64(1)}
+	p, err := Setup(context.Background(), cfg, nil, WithLogProcessor(sdklog.NewSimpleProcessor(exporter)))
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	defer func() { _ = p.Shutdown(context.Background()) }()
+
+	rec := &recordingLogger{}
+	logger := p.LogBridge(rec).With(logx.String("component", "billing"))
+
+	ctx, span := p.TP.Tracer("test").Start(context.Background(), "op")
+	logger.Info(ctx, "card.charged", logx.Int("amount", 42))
+	logger.Error(ctx, "card.declined", errors.New("insufficient funds"))
+	span.End()
+
+	if got := rec.Entries(); !reflect.DeepEqual(got, []string{"info:card.charged", "error:card.declined"}) {
+		t.Fatalf("expected calls forwarded to the wrapped logger, got %v", got)
+	}
+
+	records := exporter.Records()
+	if len(records) != 2 {
+		t.Fatalf("expected 2 log records, got %d", len(records))
+	}
+	for _, r := range records {
+		if r.TraceID() != span.SpanContext().TraceID() || r.SpanID() != span.SpanContext().SpanID() {
+			t.Fatalf("expected record %q to carry the span context", r.Body().AsString())
+		}
+	}
+
+	info := logRecordAttrs(records[0])
+	if records[0].Body().AsString() != "card.charged" || records[0].Severity() != otellog.SeverityInfo {
+		t.Fatalf("unexpected info record: %v %v", records[0].Body(), records[0].Severity())
+	}
+	if info["component"] != "billing" || info["amount"] != "42" {
+		t.Fatalf("unexpected info attributes: %v", info)
+	}
+
+	if records[1].Severity() != otellog.SeverityError || records[1].SeverityText() != "ERROR" {
+		t.Fatalf("unexpected error severity: %v %q", records[1].Severity(), records[1].SeverityText())
+	}
+	if msg := logRecordAttrs(records[1])["exception.message"]; msg != "insufficient funds" {
+		t.Fatalf("expected exception.message, got %q", msg)
+	}
+}
+
+func TestLogBridgeAllowsNilLogger(t *testing.T) {
+	exporter := &memoryLogExporter{}
+	provider := sdklog.NewLoggerProvider(sdklog.WithProcessor(sdklog.NewSimpleProcessor(exporter)))
+	defer func() { _ = provider.Shutdown(context.Background()) }()
+
+	(&Provider{LP: provider}).LogBridge(nil).Warn(context.Background(), "queue.full")
+
+	if records := exporter.Records(); len(records) != 1 || records[0].Body().AsString() != "queue.full" {
+		t.Fatalf("expected a single queue.full record, got %d", len(records))
+	}
+}
+
+func TestLogBridgeFlushesBeforeFatal(t *testing.T) {
+	exporter := &memoryLogExporter{}
+	provider := sdklog.NewLoggerProvider(sdklog.WithProcessor(sdklog.NewBatchProcessor(exporter, sdklog.WithExportInterval(time.Hour))))
+	defer func() { _ = provider.Shutdown(context.Background()) }()
+
+	var exportedBeforeFatal int
+	next := fatalHook{fn: func() { exportedBeforeFatal = len(exporter.Records()) }}
+	(&Provider{LP: provider}).LogBridge(next).With(logx.String("component", "billing")).
+		Fatal(context.Background(), "db.unreachable", errors.New("refused"))
+
+	if exportedBeforeFatal != 1 {
+		t.Fatalf("expected the fatal record exported before forwarding, got %d records", exportedBeforeFatal)
+	}
+}
+
+// fatalHook is a logx.Logger that runs fn on Fatal, standing in for a logger that exits.
+type fatalHook struct {
+	noopLogger
+	fn func()
+}
+
+func (h fatalHook) Fatal(context.Context, string, error, ...logx.Attr) { h.fn() }
+func (h fatalHook) With(...logx.Attr) logx.Logger                      { return h }
+
+func logRecordAttrs(r sdklog.Record) map[string]string {
+	out := map[string]string{}
+	r.WalkAttributes(func(kv otellog.KeyValue) bool {
+		out[kv.Key] = kv.Value.String()
+		return true
+	})
+	return out
+}
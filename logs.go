@@ -0,0 +1,134 @@
+package otelx
+
+import (
+	"context"
+	"fmt"
+
+	logx "github.com/bionicotaku/lingo-utils-logx"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploggrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploghttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdoutlog"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	"go.opentelemetry.io/otel/sdk/resource"
+	"google.golang.org/grpc/credentials"
+)
+
+// buildLoggerProvider creates the LoggerProvider enabled by Config.Logs or WithLogProcessor. It
+// shares res with the TracerProvider and exports through the same endpoint and headers as the
+// traces; records emitted with a span in their context carry its trace and span ids. DryRun keeps
+// logs local, like spans.
+func buildLoggerProvider(ctx context.Context, cfg Config, res *resource.Resource, options *setupOptions, logger logx.Logger) (*sdklog.LoggerProvider, error) {
+	lpOpts := []sdklog.LoggerProviderOption{sdklog.WithResource(res)}
+	for _, processor := range options.logProcessors {
+		lpOpts = append(lpOpts, sdklog.WithProcessor(processor))
+	}
+	if cfg.Logs && !cfg.DryRun {
+		if ec, ok := cfg.signalExporterConfig(); ok {
+			exporter, err := buildLogExporter(ctx, ec, logger)
+			if err != nil {
+				return nil, err
+			}
+			lpOpts = append(lpOpts, sdklog.WithProcessor(sdklog.NewBatchProcessor(exporter)))
+		} else if len(options.logProcessors) == 0 && logger != nil {
+			logger.Warn(ctx, "otelx.logs.exporter.unsupported", logx.String("exporter", string(cfg.exporterConfigs()[0].Exporter)))
+		}
+	}
+	return sdklog.NewLoggerProvider(lpOpts...), nil
+}
+
+func buildLogExporter(ctx context.Context, cfg ExporterConfig, logger logx.Logger) (sdklog.Exporter, error) {
+	if cfg.autoInsecure() {
+		cfg.Insecure = true
+	}
+
+	switch cfg.Exporter {
+	case "", ExporterStdout:
+		exporter, err := stdoutlog.New(stdoutlog.WithPrettyPrint())
+		if err != nil {
+			return nil, fmt.Errorf("otelx: create stdout log exporter: %w", err)
+		}
+		if logger != nil {
+			logger.Debug(ctx, "otelx.logs.stdout.enabled")
+		}
+		return exporter, nil
+
+	case ExporterOTLP:
+		options := []otlploggrpc.Option{}
+		if cfg.Endpoint != "" {
+			options = append(options, otlploggrpc.WithEndpoint(cfg.Endpoint))
+		}
+		if cfg.Insecure {
+			options = append(options, otlploggrpc.WithInsecure())
+		}
+		if len(cfg.Headers) > 0 {
+			options = append(options, otlploggrpc.WithHeaders(cfg.Headers))
+		}
+		if cfg.ExporterTimeout > 0 {
+			options = append(options, otlploggrpc.WithTimeout(cfg.ExporterTimeout))
+		}
+		if cfg.Compression != "" {
+			options = append(options, otlploggrpc.WithCompressor(cfg.Compression))
+		}
+		if cfg.TLS != nil {
+			tlsCfg, err := cfg.TLS.tlsConfig()
+			if err != nil {
+				return nil, fmt.Errorf("otelx: otlp log exporter tls: %w", err)
+			}
+			options = append(options, otlploggrpc.WithTLSCredentials(credentials.NewTLS(tlsCfg)))
+		}
+
+		exporter, err := otlploggrpc.New(ctx, options...)
+		if err != nil {
+			return nil, fmt.Errorf("otelx: create otlp log exporter: %w", err)
+		}
+		if logger != nil {
+			logger.Info(ctx, "otelx.logs.otlp.enabled")
+		}
+		return exporter, nil
+
+	case ExporterOTLPHTTP:
+		options := []otlploghttp.Option{}
+		if path, ok := unixSocketPath(cfg.Endpoint); ok {
+			options = append(options, otlploghttp.WithEndpoint("localhost"), otlploghttp.WithHTTPClient(unixSocketClient(path, cfg.timeout())))
+		} else if cfg.Endpoint != "" {
+			options = append(options, otlploghttp.WithEndpoint(cfg.Endpoint))
+		}
+		if path := signalURLPath(cfg.URLPath, "logs"); path != "" {
+			options = append(options, otlploghttp.WithURLPath(path))
+		}
+		if cfg.Insecure {
+			options = append(options, otlploghttp.WithInsecure())
+		}
+		if len(cfg.Headers) > 0 {
+			options = append(options, otlploghttp.WithHeaders(cfg.Headers))
+		}
+		if cfg.ExporterTimeout > 0 {
+			options = append(options, otlploghttp.WithTimeout(cfg.ExporterTimeout))
+		}
+		switch cfg.Compression {
+		case CompressionGzip:
+			options = append(options, otlploghttp.WithCompression(otlploghttp.GzipCompression))
+		case CompressionNone:
+			options = append(options, otlploghttp.WithCompression(otlploghttp.NoCompression))
+		}
+		if cfg.TLS != nil {
+			tlsCfg, err := cfg.TLS.tlsConfig()
+			if err != nil {
+				return nil, fmt.Errorf("otelx: otlphttp log exporter tls: %w", err)
+			}
+			options = append(options, otlploghttp.WithTLSClientConfig(tlsCfg))
+		}
+
+		exporter, err := otlploghttp.New(ctx, options...)
+		if err != nil {
+			return nil, fmt.Errorf("otelx: create otlphttp log exporter: %w", err)
+		}
+		if logger != nil {
+			logger.Info(ctx, "otelx.logs.otlphttp.enabled")
+		}
+		return exporter, nil
+
+	default:
+		return nil, fmt.Errorf("otelx: unsupported log exporter %q", cfg.Exporter)
+	}
+}
@@ -0,0 +1,92 @@
+package otelx
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	otellog "go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+)
+
+type memoryLogExporter struct {
+	mu       sync.Mutex
+	records  []sdklog.Record
+	shutdown bool
+}
+
+func (e *memoryLogExporter) Export(_ context.Context, records []sdklog.Record) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for _, r := range records {
+		e.records = append(e.records, r.Clone())
+	}
+	return nil
+}
+
+func (e *memoryLogExporter) Shutdown(context.Context) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.shutdown = true
+	return nil
+}
+
+func (e *memoryLogExporter) ForceFlush(context.Context) error { return nil }
+
+func (e *memoryLogExporter) Records() []sdklog.Record {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]sdklog.Record(nil), e.records...)
+}
+
+func TestSetupLoggerProviderCorrelatesTraces(t *testing.T) {
+	exporter := &memoryLogExporter{}
+	cfg := Config{ServiceName: "svc", Exporter: ExporterMemory, SamplingRatio: Float64(1)}
+	p, err := Setup(context.Background(), cfg, nil, WithLogProcessor(sdklog.NewSimpleProcessor(exporter)))
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	if p.LP == nil {
+		t.Fatalf("expected LoggerProvider")
+	}
+
+	ctx, span := p.TP.Tracer("test").Start(context.Background(), "op")
+	var record otellog.Record
+	record.SetBody(otellog.StringValue("charged card"))
+	record.SetSeverity(otellog.SeverityInfo)
+	p.LP.Logger("test").Emit(ctx, record)
+	span.End()
+
+	records := exporter.Records()
+	if len(records) != 1 {
+		t.Fatalf("expected 1 log record, got %d", len(records))
+	}
+	if records[0].TraceID() != span.SpanContext().TraceID() || records[0].SpanID() != span.SpanContext().SpanID() {
+		t.Fatalf("expected log record to carry the span context")
+	}
+	if v, ok := records[0].Resource().Set().Value(semconv.ServiceNameKey); !ok || v.AsString() != "svc" {
+		t.Fatalf("expected shared resource on log record")
+	}
+
+	if err := p.Shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown failed: %v", err)
+	}
+	if !exporter.shutdown {
+		t.Fatalf("expected log pipeline to shut down with the provider")
+	}
+}
+
+func TestSetupLogsUsesTraceEndpoint(t *testing.T) {
+	cfg := Config{ServiceName: "svc", Logs: true, Exporter: ExporterOTLP, Endpoint: "localhost:4317"}
+	p, err := Setup(context.Background(), cfg, nil)
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	if p.LP == nil {
+		t.Fatalf("expected LoggerProvider")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_ = p.Shutdown(ctx)
+}
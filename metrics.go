@@ -0,0 +1,272 @@
+package otelx
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	logx "github.com/bionicotaku/lingo-utils-logx"
+	"go.opentelemetry.io/contrib/instrumentation/host"
+	"go.opentelemetry.io/contrib/instrumentation/runtime"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdoutmetric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/exemplar"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/resource"
+	"google.golang.org/grpc/credentials"
+)
+
+// Values accepted by Config.MetricsTemporality, matching OTEL_EXPORTER_OTLP_METRICS_TEMPORALITY_PREFERENCE.
+const (
+	MetricsTemporalityCumulative = "cumulative"
+	MetricsTemporalityDelta      = "delta"
+	MetricsTemporalityLowMemory  = "lowmemory"
+)
+
+// validateMetrics checks the Metrics* settings.
+func (cfg Config) validateMetrics() error {
+	if !cfg.Metrics && (cfg.MetricsInterval != 0 || cfg.MetricsEndpoint != "" || cfg.MetricsTemporality != "") {
+		return fmt.Errorf("metricsInterval/metricsEndpoint/metricsTemporality require metrics=true")
+	}
+	if cfg.MetricsInterval < 0 {
+		return fmt.Errorf("metricsInterval must not be negative")
+	}
+	if cfg.MetricsEndpoint != "" {
+		ec := ExporterConfig{Exporter: ExporterOTLPHTTP, Endpoint: cfg.MetricsEndpoint}
+		if err := ec.resolveEndpointURL().validateEndpoint(); err != nil {
+			return fmt.Errorf("metricsEndpoint: %w", err)
+		}
+	}
+	switch cfg.MetricsTemporality {
+	case "", MetricsTemporalityCumulative, MetricsTemporalityDelta, MetricsTemporalityLowMemory:
+	default:
+		return fmt.Errorf("unsupported metricsTemporality %q", cfg.MetricsTemporality)
+	}
+	return nil
+}
+
+// temporalitySelector implements the temporality preferences defined by the OTLP exporter spec.
+func temporalitySelector(preference string) sdkmetric.TemporalitySelector {
+	switch preference {
+	case MetricsTemporalityDelta:
+		return func(kind sdkmetric.InstrumentKind) metricdata.Temporality {
+			switch kind {
+			case sdkmetric.InstrumentKindUpDownCounter, sdkmetric.InstrumentKindObservableUpDownCounter:
+				return metricdata.CumulativeTemporality
+			}
+			return metricdata.DeltaTemporality
+		}
+	case MetricsTemporalityLowMemory:
+		return func(kind sdkmetric.InstrumentKind) metricdata.Temporality {
+			switch kind {
+			case sdkmetric.InstrumentKindCounter, sdkmetric.InstrumentKindHistogram:
+				return metricdata.DeltaTemporality
+			}
+			return metricdata.CumulativeTemporality
+		}
+	default:
+		return sdkmetric.DefaultTemporalitySelector
+	}
+}
+
+// signalExporterConfig picks the pipeline whose endpoint and headers metrics and logs reuse: the
+// first otlp, otlphttp or stdout pipeline. ok is false when no pipeline can carry other signals.
+func (cfg Config) signalExporterConfig() (ExporterConfig, bool) {
+	for _, ec := range cfg.exporterConfigs() {
+		switch ec.Exporter {
+		case "", ExporterStdout, ExporterOTLP, ExporterOTLPHTTP:
+			return ec, true
+		}
+	}
+	return ExporterConfig{}, false
+}
+
+// metricsExporterConfig is signalExporterConfig with MetricsEndpoint applied. A URL-form
+// MetricsEndpoint decides the protocol, TLS and path itself instead of inheriting the ones resolved
+// from the trace endpoint.
+func (cfg Config) metricsExporterConfig() (ExporterConfig, bool) {
+	ec, ok := cfg.signalExporterConfig()
+	if !ok || cfg.MetricsEndpoint == "" {
+		return ec, ok
+	}
+	ec.Endpoint, ec.httpsEndpoint = cfg.MetricsEndpoint, false
+	if _, unix := unixSocketPath(ec.Endpoint); !unix && ec.remote() && strings.Contains(ec.Endpoint, "://") {
+		ec.Exporter, ec.Insecure, ec.URLPath = "", false, ""
+		ec = ec.resolveEndpointURL()
+	}
+	return ec, true
+}
+
+// buildMeterProvider creates the MeterProvider enabled by Config.Metrics or WithMetricReader. It
+// shares res with the TracerProvider and exports through the same endpoint and headers as the
+// traces unless MetricsEndpoint is set. DryRun keeps metrics local, like spans. Exemplars follow
+// Config.MetricsExemplars.
+func buildMeterProvider(ctx context.Context, cfg Config, res *resource.Resource, options *setupOptions, logger logx.Logger) (*sdkmetric.MeterProvider, error) {
+	mpOpts := []sdkmetric.Option{sdkmetric.WithResource(res)}
+	if cfg.MetricsExemplars != nil {
+		filter := exemplar.AlwaysOffFilter
+		if *cfg.MetricsExemplars {
+			filter = exemplar.TraceBasedFilter
+		}
+		mpOpts = append(mpOpts, sdkmetric.WithExemplarFilter(filter))
+	}
+	for _, reader := range options.metricReaders {
+		mpOpts = append(mpOpts, sdkmetric.WithReader(reader))
+	}
+	if cfg.Metrics && !cfg.DryRun {
+		if ec, ok := cfg.metricsExporterConfig(); ok {
+			exporter, err := buildMetricExporter(ctx, ec, temporalitySelector(cfg.MetricsTemporality), logger)
+			if err != nil {
+				return nil, err
+			}
+			var readerOpts []sdkmetric.PeriodicReaderOption
+			if cfg.MetricsInterval > 0 {
+				readerOpts = append(readerOpts, sdkmetric.WithInterval(cfg.MetricsInterval))
+			}
+			mpOpts = append(mpOpts, sdkmetric.WithReader(sdkmetric.NewPeriodicReader(exporter, readerOpts...)))
+		} else if len(options.metricReaders) == 0 && logger != nil {
+			logger.Warn(ctx, "otelx.metrics.exporter.unsupported", logx.String("exporter", string(cfg.exporterConfigs()[0].Exporter)))
+		}
+	}
+	return sdkmetric.NewMeterProvider(mpOpts...), nil
+}
+
+// startMetricInstrumentation registers the built-in instrumentation enabled by options against mp,
+// which may be nil when metrics are off.
+func startMetricInstrumentation(ctx context.Context, mp *sdkmetric.MeterProvider, options *setupOptions, logger logx.Logger) error {
+	if !options.runtimeMetrics && !options.hostMetrics {
+		return nil
+	}
+	if mp == nil {
+		if logger != nil {
+			logger.Warn(ctx, "otelx.metrics.instrumentation.skipped", logx.String("reason", "metrics disabled"))
+		}
+		return nil
+	}
+	if options.runtimeMetrics {
+		if err := runtime.Start(runtime.WithMeterProvider(mp)); err != nil {
+			return fmt.Errorf("otelx: start runtime metrics: %w", err)
+		}
+	}
+	if options.hostMetrics {
+		if err := host.Start(host.WithMeterProvider(mp)); err != nil {
+			return fmt.Errorf("otelx: start host metrics: %w", err)
+		}
+		if err := startDiskMetrics(mp); err != nil {
+			return fmt.Errorf("otelx: start disk metrics: %w", err)
+		}
+	}
+	return nil
+}
+
+func buildMetricExporter(ctx context.Context, cfg ExporterConfig, temporality sdkmetric.TemporalitySelector, logger logx.Logger) (sdkmetric.Exporter, error) {
+	if cfg.autoInsecure() {
+		cfg.Insecure = true
+	}
+
+	switch cfg.Exporter {
+	case "", ExporterStdout:
+		exporter, err := stdoutmetric.New(stdoutmetric.WithPrettyPrint(), stdoutmetric.WithTemporalitySelector(temporality))
+		if err != nil {
+			return nil, fmt.Errorf("otelx: create stdout metric exporter: %w", err)
+		}
+		if logger != nil {
+			logger.Debug(ctx, "otelx.metrics.stdout.enabled")
+		}
+		return exporter, nil
+
+	case ExporterOTLP:
+		options := []otlpmetricgrpc.Option{otlpmetricgrpc.WithTemporalitySelector(temporality)}
+		if cfg.Endpoint != "" {
+			options = append(options, otlpmetricgrpc.WithEndpoint(cfg.Endpoint))
+		}
+		if cfg.Insecure {
+			options = append(options, otlpmetricgrpc.WithInsecure())
+		}
+		if len(cfg.Headers) > 0 {
+			options = append(options, otlpmetricgrpc.WithHeaders(cfg.Headers))
+		}
+		if cfg.ExporterTimeout > 0 {
+			options = append(options, otlpmetricgrpc.WithTimeout(cfg.ExporterTimeout))
+		}
+		if cfg.Compression == CompressionGzip {
+			options = append(options, otlpmetricgrpc.WithCompressor(CompressionGzip))
+		}
+		if cfg.TLS != nil {
+			tlsCfg, err := cfg.TLS.tlsConfig()
+			if err != nil {
+				return nil, fmt.Errorf("otelx: otlp metric exporter tls: %w", err)
+			}
+			options = append(options, otlpmetricgrpc.WithTLSCredentials(credentials.NewTLS(tlsCfg)))
+		}
+
+		exporter, err := otlpmetricgrpc.New(ctx, options...)
+		if err != nil {
+			return nil, fmt.Errorf("otelx: create otlp metric exporter: %w", err)
+		}
+		if logger != nil {
+			logger.Info(ctx, "otelx.metrics.otlp.enabled")
+		}
+		return exporter, nil
+
+	case ExporterOTLPHTTP:
+		options := []otlpmetrichttp.Option{otlpmetrichttp.WithTemporalitySelector(temporality)}
+		if path, ok := unixSocketPath(cfg.Endpoint); ok {
+			options = append(options, otlpmetrichttp.WithEndpoint("localhost"), otlpmetrichttp.WithHTTPClient(unixSocketClient(path, cfg.timeout())))
+		} else if cfg.Endpoint != "" {
+			options = append(options, otlpmetrichttp.WithEndpoint(cfg.Endpoint))
+		}
+		if path := signalURLPath(cfg.URLPath, "metrics"); path != "" {
+			options = append(options, otlpmetrichttp.WithURLPath(path))
+		}
+		if cfg.Insecure {
+			options = append(options, otlpmetrichttp.WithInsecure())
+		}
+		if len(cfg.Headers) > 0 {
+			options = append(options, otlpmetrichttp.WithHeaders(cfg.Headers))
+		}
+		if cfg.ExporterTimeout > 0 {
+			options = append(options, otlpmetrichttp.WithTimeout(cfg.ExporterTimeout))
+		}
+		switch cfg.Compression {
+		case CompressionGzip:
+			options = append(options, otlpmetrichttp.WithCompression(otlpmetrichttp.GzipCompression))
+		case CompressionNone:
+			options = append(options, otlpmetrichttp.WithCompression(otlpmetrichttp.NoCompression))
+		}
+		if cfg.TLS != nil {
+			tlsCfg, err := cfg.TLS.tlsConfig()
+			if err != nil {
+				return nil, fmt.Errorf("otelx: otlphttp metric exporter tls: %w", err)
+			}
+			options = append(options, otlpmetrichttp.WithTLSClientConfig(tlsCfg))
+		}
+
+		exporter, err := otlpmetrichttp.New(ctx, options...)
+		if err != nil {
+			return nil, fmt.Errorf("otelx: create otlphttp metric exporter: %w", err)
+		}
+		if logger != nil {
+			logger.Info(ctx, "otelx.metrics.otlphttp.enabled")
+		}
+		return exporter, nil
+
+	default:
+		return nil, fmt.Errorf("otelx: unsupported metric exporter %q", cfg.Exporter)
+	}
+}
+
+// signalURLPath derives the path of signal ("metrics", "logs") from a traces URLPath such as
+// /otlp/v1/traces. A path already naming signal is kept; other paths cannot be mapped and fall
+// back to the exporter default.
+func signalURLPath(tracesPath, signal string) string {
+	if strings.HasSuffix(tracesPath, "/v1/"+signal) {
+		return tracesPath
+	}
+	if prefix, ok := strings.CutSuffix(tracesPath, "/v1/traces"); ok {
+		return prefix + "/v1/" + signal
+	}
+	return ""
+}
@@ -0,0 +1,241 @@
+package otelx
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+)
+
+func TestSetupMeterProviderSharesResource(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	cfg := Config{ServiceName: "svc", Exporter: ExporterMemory}
+	p, err := Setup(context.Background(), cfg, noopLogger{}, WithMetricReader(reader))
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	if p.MP == nil {
+		t.Fatalf("expected MeterProvider")
+	}
+
+	counter, err := p.MP.Meter("test").Int64Counter("jobs")
+	if err != nil {
+		t.Fatalf("create counter: %v", err)
+	}
+	counter.Add(context.Background(), 3)
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("collect failed: %v", err)
+	}
+	if v, ok := rm.Resource.Set().Value(semconv.ServiceNameKey); !ok || v.AsString() != "svc" {
+		t.Fatalf("expected service.name=svc on metrics resource, got %v", rm.Resource)
+	}
+	if len(rm.ScopeMetrics) != 1 || rm.ScopeMetrics[0].Metrics[0].Name != "jobs" {
+		t.Fatalf("expected jobs metric, got %+v", rm.ScopeMetrics)
+	}
+
+	if err := p.Shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown failed: %v", err)
+	}
+	if err := reader.Collect(context.Background(), &rm); err == nil {
+		t.Fatalf("expected reader to be shut down with the provider")
+	}
+}
+
+func TestSetupWithoutMetricsHasNoMeterProvider(t *testing.T) {
+	p, err := Setup(context.Background(), Config{ServiceName: "svc", Exporter: ExporterMemory}, noopLogger{})
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	defer p.Shutdown(context.Background())
+	if p.MP != nil {
+		t.Fatalf("expected no MeterProvider unless metrics are enabled")
+	}
+}
+
+func TestSetupMetricsUsesTraceEndpoint(t *testing.T) {
+	cfg := Config{
+		ServiceName:        "svc",
+		Metrics:            true,
+		MetricsInterval:    10 * time.Second,
+		MetricsTemporality: "Delta",
+		Exporter:           ExporterOTLPHTTP,
+		Endpoint:           "localhost:4318",
+	}
+	p, err := Setup(context.Background(), cfg, noopLogger{})
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	if p.MP == nil {
+		t.Fatalf("expected MeterProvider")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_ = p.Shutdown(ctx)
+}
+
+func TestMetricsEndpointURL(t *testing.T) {
+	cfg := Config{
+		ServiceName:     "svc",
+		Metrics:         true,
+		Endpoint:        "https://traces.example.com:4317",
+		MetricsEndpoint: "http://metrics.internal:4318/otel/v1/metrics",
+	}.sanitize()
+	if err := cfg.validate(); err != nil {
+		t.Fatalf("validate failed: %v", err)
+	}
+	ec, ok := cfg.metricsExporterConfig()
+	want := ExporterConfig{Exporter: ExporterOTLPHTTP, Endpoint: "metrics.internal:4318", URLPath: "/otel/v1/metrics", Insecure: true}
+	if !ok || ec.Exporter != want.Exporter || ec.Endpoint != want.Endpoint || ec.URLPath != want.URLPath ||
+		ec.Insecure != want.Insecure || ec.httpsEndpoint {
+		t.Fatalf("metricsExporterConfig() = %+v, want %+v", ec, want)
+	}
+
+	cfg.MetricsEndpoint = "metrics.internal:4317"
+	if ec, _ := cfg.metricsExporterConfig(); ec.Exporter != ExporterOTLP || ec.Endpoint != "metrics.internal:4317" || ec.Insecure {
+		t.Fatalf("expected a host:port MetricsEndpoint to keep the trace protocol and TLS, got %+v", ec)
+	}
+}
+
+func TestSignalURLPath(t *testing.T) {
+	cases := map[string]string{
+		"":                 "",
+		"/v1/traces":       "/v1/metrics",
+		"/otlp/v1/traces":  "/otlp/v1/metrics",
+		"/otel/v1/metrics": "/otel/v1/metrics",
+		"/custom":          "",
+	}
+	for in, want := range cases {
+		if got := signalURLPath(in, "metrics"); got != want {
+			t.Errorf("signalURLPath(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestMetricsSettingsValidation(t *testing.T) {
+	cases := map[string]Config{
+		"requires metrics":     {ServiceName: "svc", MetricsInterval: time.Second},
+		"negative interval":    {ServiceName: "svc", Metrics: true, MetricsInterval: -time.Second},
+		"unknown temporality":  {ServiceName: "svc", Metrics: true, MetricsTemporality: "gauge"},
+		"endpoint w/o metrics": {ServiceName: "svc", MetricsEndpoint: "collector:4317"},
+		"endpoint scheme":      {ServiceName: "svc", Metrics: true, MetricsEndpoint: "ftp://collector:4318"},
+	}
+	for name, cfg := range cases {
+		if _, err := Setup(context.Background(), cfg, nil); err == nil {
+			t.Errorf("%s: expected validation error", name)
+		}
+	}
+}
+
+func TestTemporalitySelector(t *testing.T) {
+	delta := temporalitySelector(MetricsTemporalityDelta)
+	if delta(sdkmetric.InstrumentKindCounter) != metricdata.DeltaTemporality ||
+		delta(sdkmetric.InstrumentKindUpDownCounter) != metricdata.CumulativeTemporality {
+		t.Fatalf("unexpected delta preference")
+	}
+	low := temporalitySelector(MetricsTemporalityLowMemory)
+	if low(sdkmetric.InstrumentKindHistogram) != metricdata.DeltaTemporality ||
+		low(sdkmetric.InstrumentKindObservableCounter) != metricdata.CumulativeTemporality {
+		t.Fatalf("unexpected lowmemory preference")
+	}
+	if temporalitySelector("")(sdkmetric.InstrumentKindCounter) != metricdata.CumulativeTemporality {
+		t.Fatalf("expected cumulative by default")
+	}
+}
+
+func TestWithRuntimeMetrics(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	p, err := Setup(context.Background(), Config{ServiceName: "svc", Exporter: ExporterMemory}, nil,
+		WithMetricReader(reader), WithRuntimeMetrics())
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	defer p.Shutdown(context.Background())
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("collect failed: %v", err)
+	}
+	found := false
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			found = found || m.Name == "go.goroutine.count"
+		}
+	}
+	if !found {
+		t.Fatalf("expected go runtime metrics, got %+v", rm.ScopeMetrics)
+	}
+}
+
+func TestWithHostMetrics(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	p, err := Setup(context.Background(), Config{ServiceName: "svc", Exporter: ExporterMemory}, nil,
+		WithMetricReader(reader), WithHostMetrics())
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	defer p.Shutdown(context.Background())
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("collect failed: %v", err)
+	}
+	names := map[string]bool{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			names[m.Name] = true
+		}
+	}
+	if !names["process.cpu.time"] || !names["system.memory.usage"] {
+		t.Fatalf("expected host metrics, got %v", names)
+	}
+}
+
+func TestMetricsExemplars(t *testing.T) {
+	for _, enabled := range []bool{true, false} {
+		reader := sdkmetric.NewManualReader()
+		cfg := Config{ServiceName: "svc", Exporter: ExporterMemory, SamplingRatio: Float64(1), MetricsExemplars: Bool(enabled)}
+		p, err := Setup(context.Background(), cfg, noopLogger{}, WithMetricReader(reader))
+		if err != nil {
+			t.Fatalf("setup failed: %v", err)
+		}
+
+		latency, err := p.MP.Meter("test").Float64Histogram("latency")
+		if err != nil {
+			t.Fatalf("create histogram: %v", err)
+		}
+		ctx, span := p.TP.Tracer("test").Start(context.Background(), "op")
+		latency.Record(ctx, 0.25)
+		span.End()
+
+		var rm metricdata.ResourceMetrics
+		if err := reader.Collect(context.Background(), &rm); err != nil {
+			t.Fatalf("collect failed: %v", err)
+		}
+		var exemplars []metricdata.Exemplar[float64]
+		for _, sm := range rm.ScopeMetrics {
+			for _, m := range sm.Metrics {
+				if hist, ok := m.Data.(metricdata.Histogram[float64]); ok && m.Name == "latency" {
+					for _, dp := range hist.DataPoints {
+						exemplars = append(exemplars, dp.Exemplars...)
+					}
+				}
+			}
+		}
+		if !enabled {
+			if len(exemplars) != 0 {
+				t.Fatalf("expected no exemplars when disabled, got %d", len(exemplars))
+			}
+		} else {
+			traceID := span.SpanContext().TraceID()
+			if len(exemplars) != 1 || string(exemplars[0].TraceID) != string(traceID[:]) {
+				t.Fatalf("expected exemplar linked to trace %s, got %+v", traceID, exemplars)
+			}
+		}
+		_ = p.Shutdown(context.Background())
+	}
+}
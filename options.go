@@ -1,15 +1,55 @@
 package otelx
 
 import (
+	"time"
+
+	logx "github.com/bionicotaku/lingo-utils-logx"
 	"go.opentelemetry.io/otel/propagation"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
 	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 )
 
 type setupOptions struct {
-	global       bool
-	propagator   propagation.TextMapPropagator
-	resourceOpts []resource.Option
-	samplerHook  func(float64)
+	global        bool
+	propagator    propagation.TextMapPropagator
+	resourceOpts  []resource.Option
+	refreshEvery  time.Duration
+	samplerHook   func(float64)
+	ratioObserver func(float64)
+	sampler       sdktrace.Sampler
+	shadow        sdktrace.Sampler
+
+	tracerProvider *sdktrace.TracerProvider
+	spanExporters  []sdktrace.SpanExporter
+	spanProcessors []sdktrace.SpanProcessor
+	metricReaders  []sdkmetric.Reader
+	logProcessors  []sdklog.Processor
+	prometheus     bool
+	runtimeMetrics bool
+	hostMetrics    bool
+	spanMetrics    bool
+	sampleErrors   bool
+	syncExport     bool
+	sdkErrors      bool
+	exportStats    bool
+
+	exportStatsEvery time.Duration
+	dialTimeout      time.Duration
+	dialWarnOnly     bool
+
+	attrExtractors []ContextAttributeExtractor
+	spanNames      []SpanNameNormalizer
+	stageOrder     []SpanStage
+	eventLimits    EventLimits
+	attrMaxBytes   int
+	truncMarker    string
+	redaction      *redactor
+	cardinality    *CardinalityLimits
+	clock          Clock
+	attrSchemas    []AttributeSchema
+	onViolation    func(SchemaViolation)
 }
 
 // Option customises Setup behaviour.
@@ -36,8 +76,283 @@ func WithResourceOptions(opts ...resource.Option) Option {
 	}
 }
 
+// WithResourceRefresh re-runs resource detection, including WithResourceOptions, every interval and
+// applies changed attributes (spot preemption notices, autoscaler labels) to spans started
+// afterwards. Metrics and logs keep the resource detected by Setup.
+func WithResourceRefresh(interval time.Duration) Option {
+	return func(o *setupOptions) {
+		o.refreshEvery = interval
+	}
+}
+
+// WithTracerProvider makes Setup reuse an existing TracerProvider instead of building a new one.
+// Config is still validated and the propagator and span helpers are wired around tp, but exporter,
+// sampler and resource settings are left to the caller, who also remains responsible for shutting tp down.
+func WithTracerProvider(tp *sdktrace.TracerProvider) Option {
+	return func(o *setupOptions) {
+		o.tracerProvider = tp
+	}
+}
+
+// WithEventLimits keeps the first limits.PerSpan events of each span, samples the rest and caps the
+// exported events per second, protecting exports from loops that call AddEvent thousands of times.
+// Spans that lost events carry an otelx.events.dropped attribute.
+func WithEventLimits(limits EventLimits) Option {
+	return func(o *setupOptions) {
+		o.eventLimits = limits
+	}
+}
+
+// WithAttributeTruncation cuts string attribute values longer than maxBytes at export time. Cut values
+// end with a "…(+N bytes)" marker and gain a companion <key>_truncated=true attribute, so analysts can
+// tell the data was trimmed and by how much; the span is marked otelx.truncated=true. It overrides
+// Config.Truncation.
+func WithAttributeTruncation(maxBytes int) Option {
+	return func(o *setupOptions) {
+		o.attrMaxBytes = maxBytes
+	}
+}
+
+// WithSpanExporter exports spans through exporter instead of the exporters described by Config,
+// while keeping otelx's resource, sampler and propagator assembly. Config's exporter settings,
+// including DryRun, are ignored; repeat the option to fan out to several exporters.
+func WithSpanExporter(exporter sdktrace.SpanExporter) Option {
+	return func(o *setupOptions) {
+		if exporter != nil {
+			o.spanExporters = append(o.spanExporters, exporter)
+		}
+	}
+}
+
+// WithSpanProcessor registers processor on Provider.TP during Setup, after otelx's enrichment
+// processors and before the export pipeline, so it sees every span from the start. Unlike
+// RegisterSpanProcessor afterwards, spans started before registration are not missed. Processors
+// shut down with the provider; repeat the option to add several, which run in order.
+func WithSpanProcessor(processor sdktrace.SpanProcessor) Option {
+	return func(o *setupOptions) {
+		if processor != nil {
+			o.spanProcessors = append(o.spanProcessors, processor)
+		}
+	}
+}
+
+// WithMetricReader adds reader to Provider.MP, e.g. an sdkmetric.ManualReader in tests. It builds
+// the MeterProvider even when Config.Metrics is off.
+func WithMetricReader(reader sdkmetric.Reader) Option {
+	return func(o *setupOptions) {
+		if reader != nil {
+			o.metricReaders = append(o.metricReaders, reader)
+		}
+	}
+}
+
+// WithLogProcessor adds processor to Provider.LP, e.g. a simple processor over a test exporter. It
+// builds the LoggerProvider even when Config.Logs is off.
+func WithLogProcessor(processor sdklog.Processor) Option {
+	return func(o *setupOptions) {
+		if processor != nil {
+			o.logProcessors = append(o.logProcessors, processor)
+		}
+	}
+}
+
+// WithPrometheus adds a Prometheus pull reader to Provider.MP and exposes it through
+// Provider.MetricsHandler. The reader stops with Provider.Shutdown. Like WithMetricReader, it
+// builds the MeterProvider even when Config.Metrics is off.
+func WithPrometheus() Option {
+	return func(o *setupOptions) {
+		o.prometheus = true
+	}
+}
+
+// WithRuntimeMetrics records Go runtime metrics (GC, goroutines, memory, scheduler) with
+// Provider.MP. Collection stops when Provider.Shutdown shuts the MeterProvider down. It requires
+// Config.Metrics or a metric reader option; without a MeterProvider it only logs a warning.
+func WithRuntimeMetrics() Option {
+	return func(o *setupOptions) {
+		o.runtimeMetrics = true
+	}
+}
+
+// WithHostMetrics records host CPU, memory, network and disk I/O metrics with Provider.MP, with the
+// same lifecycle and requirements as WithRuntimeMetrics.
+func WithHostMetrics() Option {
+	return func(o *setupOptions) {
+		o.hostMetrics = true
+	}
+}
+
+// WithSpanMetrics derives RED metrics (SpanMetricsCallsName, SpanMetricsDurationName) per span
+// name, kind and status from ended spans and records them with Provider.MP, so dashboards stay
+// accurate when sampling drops most traces. Unsampled spans are recorded but not exported, which
+// costs some CPU and memory per span; keep span names low-cardinality (see
+// WithSpanNameNormalizers). Requires Metrics or a metric reader, like WithRuntimeMetrics.
+func WithSpanMetrics() Option {
+	return func(o *setupOptions) {
+		o.spanMetrics = true
+	}
+}
+
+// WithAlwaysSampleErrors exports spans that end with an Error status even when head sampling
+// dropped their trace, tagged with ErrorSampledKey. Like WithSpanMetrics, unsampled spans are then
+// recorded but not exported, which costs some CPU and memory per span.
+func WithAlwaysSampleErrors() Option {
+	return func(o *setupOptions) {
+		o.sampleErrors = true
+	}
+}
+
+// WithSyncExport exports every span synchronously when it ends, through
+// sdktrace.NewSimpleSpanProcessor, so spans show up at once (e.g. on stdout) during local
+// debugging. Ending a span then waits for the exporter, so keep it out of production;
+// Config.Batch and Config.DeferredExport are ignored.
+func WithSyncExport() Option {
+	return func(o *setupOptions) {
+		o.syncExport = true
+	}
+}
+
+// WithSamplingRatioObserver calls fn with the head sampling ratio chosen by
+// Config.SamplingTargetPerMinute, once at Setup and after every change.
+func WithSamplingRatioObserver(fn func(ratio float64)) Option {
+	return func(o *setupOptions) {
+		o.ratioObserver = fn
+	}
+}
+
+// WithSampler replaces the default ParentBased(TraceIDRatioBased(SamplingRatio)) head sampler with
+// sampler, e.g. a rules-based or vendor sampler; wrap it in sdktrace.ParentBased to honour parent
+// decisions. Config.SamplingRatio is then ignored and the ratio can no longer be changed at runtime.
+// Canary, shadow and span-metrics sampling still apply on top.
+func WithSampler(sampler sdktrace.Sampler) Option {
+	return func(o *setupOptions) {
+		o.sampler = sampler
+	}
+}
+
+// WithShadowSampler evaluates sampler next to the configured sampler for every new trace without
+// affecting what is recorded or exported. Compare the decisions with Provider.ShadowStats; a
+// summary is logged as otelx.sampler.shadow.summary on Shutdown.
+func WithShadowSampler(sampler sdktrace.Sampler) Option {
+	return func(o *setupOptions) {
+		o.shadow = sampler
+	}
+}
+
+// WithCardinalityGuard protects backends from attribute cardinality explosions: once a key takes
+// more than limits.MaxValues distinct values within limits.Window, its values are hashed or bucketed
+// for the rest of the window and the offending key is reported through the logger.
+func WithCardinalityGuard(limits CardinalityLimits) Option {
+	return func(o *setupOptions) {
+		if limits.MaxValues > 0 {
+			o.cardinality = &limits
+		}
+	}
+}
+
+// WithClock stamps span start, end and event times with clock instead of the system clock, for
+// deterministic tests or to correct a known skew (see OffsetClock). Exported timestamps are
+// shifted by the difference between clock and the system clock when the span ends, so explicit
+// trace.WithTimestamp values keep their meaning. Processors added with WithSpanProcessor and span
+// metrics still see system time. Batch export timers keep using the system clock because the SDK
+// does not expose them; call Provider.TP.ForceFlush in tests.
+func WithClock(clock Clock) Option {
+	return func(o *setupOptions) {
+		o.clock = clock
+	}
+}
+
+// WithAttributeSchemas registers attribute schemas in code, in addition to Config.AttributeSchemas.
+// They are only checked when Config.SchemaValidation is set.
+func WithAttributeSchemas(schemas ...AttributeSchema) Option {
+	return func(o *setupOptions) {
+		o.attrSchemas = append(o.attrSchemas, schemas...)
+	}
+}
+
+// WithSchemaViolationHandler calls fn for every schema violation, e.g. to t.Error in tests.
+func WithSchemaViolationHandler(fn func(SchemaViolation)) Option {
+	return func(o *setupOptions) {
+		o.onViolation = fn
+	}
+}
+
+// schemaProcessor returns the schema validation processor, or nil when validation is off.
+func (o *setupOptions) schemaProcessor(cfg Config, logger logx.Logger) *schemaProcessor {
+	schemas := append(append([]AttributeSchema(nil), cfg.AttributeSchemas...), o.attrSchemas...)
+	if cfg.SchemaValidation == SchemaOff || len(schemas) == 0 {
+		return nil
+	}
+	return newSchemaProcessor(cfg.SchemaValidation, schemas, o.onViolation, logger)
+}
+
 func withSamplerHook(hook func(float64)) Option {
 	return func(o *setupOptions) {
 		o.samplerHook = hook
 	}
 }
+
+// WithContextAttributeExtractor adds span attributes derived from the start context to every span,
+// e.g. user or org ids stored in the request context by auth middleware.
+func WithContextAttributeExtractor(fn ContextAttributeExtractor) Option {
+	return func(o *setupOptions) {
+		if fn != nil {
+			o.attrExtractors = append(o.attrExtractors, fn)
+		}
+	}
+}
+
+// WithSpanNameNormalizers renames every span on start by applying normalizers in order, e.g.
+// otelx.DefaultSpanNameNormalizers() plus service-specific ones. This also covers otelgrpc spans,
+// which have no naming hook; for otelhttp prefer HTTPSpanNames, which sees the request.
+func WithSpanNameNormalizers(normalizers ...SpanNameNormalizer) Option {
+	return func(o *setupOptions) {
+		for _, n := range normalizers {
+			if n != nil {
+				o.spanNames = append(o.spanNames, n)
+			}
+		}
+	}
+}
+
+// stageEnabled reports whether options turn stage on. The attribute drop list is runtime
+// configurable, so that stage is always present.
+func (o *setupOptions) stageEnabled(stage SpanStage) bool {
+	switch stage {
+	case StageEventLimits:
+		return o.eventLimits.enabled()
+	case StageAttributeDrop:
+		return true
+	case StageRedaction:
+		return o.redaction != nil
+	case StageTruncation:
+		return o.attrMaxBytes > 0
+	case StageCardinality:
+		return o.cardinality != nil
+	}
+	return false
+}
+
+// spanTransforms returns the export-time span transforms enabled by options, in the given stage
+// order (see resolveStageOrder).
+func (o *setupOptions) spanTransforms(order []SpanStage, attrFilter *attributeFilter, logger logx.Logger) []spanTransform {
+	var transforms []spanTransform
+	for _, stage := range order {
+		if !o.stageEnabled(stage) {
+			continue
+		}
+		switch stage {
+		case StageEventLimits:
+			transforms = append(transforms, newEventLimiter(o.eventLimits).limit)
+		case StageAttributeDrop:
+			transforms = append(transforms, attrFilter.filter)
+		case StageRedaction:
+			transforms = append(transforms, o.redaction.redact)
+		case StageTruncation:
+			transforms = append(transforms, attrTruncator{maxBytes: o.attrMaxBytes, marker: o.truncMarker}.truncate)
+		case StageCardinality:
+			transforms = append(transforms, newCardinalityGuard(*o.cardinality, logger).guard)
+		}
+	}
+	return transforms
+}
@@ -2,6 +2,7 @@ package otelx
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"reflect"
@@ -11,11 +12,13 @@ import (
 	"time"
 
 	logx "github.com/bionicotaku/lingo-utils-logx"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/sdk/resource"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
 )
 
@@ -85,6 +88,133 @@ func TestSetupOTLPExporter(t *testing.T) {
 	_ = prov.Shutdown(context.Background())
 }
 
+func TestSetupOTLPHTTPExporter(t *testing.T) {
+	cfg := Config{
+		ServiceName: "svc",
+		Exporter:    ExporterOTLPHTTP,
+		Endpoint:    "localhost:4318",
+		URLPath:     "/custom/v1/traces",
+		Insecure:    true,
+		Headers:     map[string]string{"x-api-key": "secret"},
+	}
+	prov, err := Setup(context.Background(), cfg, noopLogger{})
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	_ = prov.Shutdown(context.Background())
+}
+
+func TestSetupRejectsURLPathForGRPC(t *testing.T) {
+	cfg := Config{ServiceName: "svc", Exporter: ExporterOTLP, URLPath: "/v1/traces"}
+	if _, err := Setup(context.Background(), cfg, nil); err == nil {
+		t.Fatalf("expected error for urlPath with grpc exporter")
+	}
+}
+
+func TestSetupRejectsJaegerExporter(t *testing.T) {
+	cfg := Config{ServiceName: "svc", Exporter: "jaeger", Endpoint: "http://localhost:14268/api/traces"}
+	if _, err := Setup(context.Background(), cfg, nil); err == nil || !strings.Contains(err.Error(), "exporter=otlp") {
+		t.Fatalf("expected error pointing at OTLP, got %v", err)
+	}
+}
+
+func TestSetupZipkinExporter(t *testing.T) {
+	cfg := Config{ServiceName: "svc", Exporter: ExporterZipkin, Endpoint: "http://localhost:9411/api/v2/spans"}
+	prov, err := Setup(context.Background(), cfg, noopLogger{})
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	_ = prov.Shutdown(context.Background())
+
+	cfg.Endpoint = "localhost:9411"
+	if _, err := Setup(context.Background(), cfg, nil); err == nil || !strings.Contains(err.Error(), "zipkin exporter") {
+		t.Fatalf("expected zipkin exporter error for endpoint without scheme, got %v", err)
+	}
+}
+
+func TestSetupFansOutToMultipleExporters(t *testing.T) {
+	cfg := Config{
+		ServiceName:   "svc",
+		SamplingRatio: Float64(1),
+		DryRun:        true,
+		Exporters: []ExporterConfig{
+			{Exporter: ExporterStdout},
+			{Exporter: ExporterOTLP, Endpoint: "localhost:4317", Insecure: true},
+		},
+	}
+	prov, err := Setup(context.Background(), cfg, noopLogger{})
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	defer prov.Shutdown(context.Background())
+
+	_, span := prov.TP.Tracer("test").Start(context.Background(), "fan-out")
+	span.End()
+	if err := prov.TP.ForceFlush(context.Background()); err != nil {
+		t.Fatalf("force flush failed: %v", err)
+	}
+	if got := prov.DryRunStats().Spans; got != 2 {
+		t.Fatalf("expected span to reach both pipelines, got %d exports", got)
+	}
+}
+
+func TestSetupExportersValidation(t *testing.T) {
+	cfg := Config{ServiceName: "svc", Exporter: ExporterOTLP, Exporters: []ExporterConfig{{Exporter: ExporterStdout}}}
+	if _, err := Setup(context.Background(), cfg, nil); err == nil {
+		t.Fatalf("expected error when mixing exporter and exporters")
+	}
+
+	cfg = Config{ServiceName: "svc", Exporters: []ExporterConfig{{Exporter: ExporterStdout}, {Exporter: ExporterCloudTrace}}}
+	_, err := Setup(context.Background(), cfg, nil)
+	if err == nil || !strings.Contains(err.Error(), "exporters[1]") {
+		t.Fatalf("expected indexed validation error, got %v", err)
+	}
+}
+
+func TestSetupMemoryExporterRecordsSpans(t *testing.T) {
+	cfg := Config{ServiceName: "svc", Exporter: ExporterMemory, SamplingRatio: Float64(1)}
+	prov, err := Setup(context.Background(), cfg, nil)
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	defer prov.Shutdown(context.Background())
+
+	_, span := prov.TP.Tracer("test").Start(context.Background(), "recorded")
+	span.End()
+
+	spans := prov.RecordedSpans()
+	if len(spans) != 1 || spans[0].Name != "recorded" {
+		t.Fatalf("expected recorded span, got %v", spans)
+	}
+	if !hasAttribute(spans[0].Resource, semconv.ServiceNameKey, "svc") {
+		t.Fatalf("expected recorded span to carry the service resource")
+	}
+
+	prov.ResetRecordedSpans()
+	if spans := prov.RecordedSpans(); len(spans) != 0 {
+		t.Fatalf("expected recorded spans to be cleared, got %d", len(spans))
+	}
+}
+
+func TestSetupWithSpanExporter(t *testing.T) {
+	mem := tracetest.NewInMemoryExporter()
+	cfg := Config{ServiceName: "svc", Exporter: ExporterCloudTrace, GCPProjectID: "project", SamplingRatio: Float64(1)}
+	prov, err := Setup(context.Background(), cfg, nil, WithSpanExporter(mem))
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	defer prov.Shutdown(context.Background())
+
+	_, span := prov.TP.Tracer("test").Start(context.Background(), "custom")
+	span.End()
+	if err := prov.TP.ForceFlush(context.Background()); err != nil {
+		t.Fatalf("force flush failed: %v", err)
+	}
+	if spans := mem.GetSpans(); len(spans) != 1 || spans[0].Name != "custom" {
+		t.Fatalf("expected span in custom exporter, got %v", spans)
+	}
+}
+
 func TestSetupCloudTraceExporterValidation(t *testing.T) {
 	cfg := Config{ServiceName: "svc", Exporter: ExporterCloudTrace}
 	if _, err := Setup(context.Background(), cfg, nil); err == nil {
@@ -92,6 +222,50 @@ func TestSetupCloudTraceExporterValidation(t *testing.T) {
 	}
 }
 
+func TestSetupExporterTimeout(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	ec := ExporterConfig{
+		Exporter:        ExporterOTLPHTTP,
+		Endpoint:        strings.TrimPrefix(srv.URL, "http://"),
+		ExporterTimeout: 50 * time.Millisecond,
+		OTLPRetry:       &OTLPRetryConfig{Enabled: Bool(false)},
+	}
+	exporter, err := buildExporter(context.Background(), ec, nil)
+	if err != nil {
+		t.Fatalf("build exporter: %v", err)
+	}
+	defer exporter.Shutdown(context.Background())
+	spans := tracetest.SpanStubs{{Name: "op"}}.Snapshots()
+	start := time.Now()
+	if err := exporter.ExportSpans(context.Background(), spans); err == nil {
+		t.Fatal("expected the slow export to time out")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("expected the export to stop after the timeout, took %v", elapsed)
+	}
+
+	for _, cfg := range []Config{
+		{ServiceName: "svc", Exporter: ExporterZipkin, ExporterTimeout: time.Second},
+		{ServiceName: "svc", Exporter: ExporterOTLP, ExporterTimeout: -time.Second},
+	} {
+		if _, err := Setup(context.Background(), cfg, nil); err == nil {
+			t.Fatalf("expected validation error for %+v", cfg)
+		}
+	}
+	if got := (ExporterConfig{}).timeout(); got != DefaultExporterTimeout {
+		t.Fatalf("expected the default timeout, got %v", got)
+	}
+}
+
 func TestSetupInvalidExporter(t *testing.T) {
 	cfg := Config{ServiceName: "svc", Exporter: ExporterType("invalid")}
 	if _, err := Setup(context.Background(), cfg, nil); err == nil {
@@ -153,6 +327,45 @@ func TestSetupAllowsZeroSamplingRatio(t *testing.T) {
 	_ = prov.Shutdown(ctx)
 }
 
+func TestSetupWithSampler(t *testing.T) {
+	cfg := Config{ServiceName: "svc", Exporter: ExporterMemory, SamplingRatio: Float64(1)}
+	keepImportant := sdktrace.ParentBased(samplerFunc(func(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+		if strings.HasPrefix(p.Name, "important") {
+			return sdktrace.SamplingResult{Decision: sdktrace.RecordAndSample}
+		}
+		return sdktrace.SamplingResult{Decision: sdktrace.Drop}
+	}))
+	prov, err := Setup(context.Background(), cfg, noopLogger{}, WithSampler(keepImportant))
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	defer prov.Shutdown(context.Background())
+
+	tracer := prov.TP.Tracer("test")
+	_, kept := tracer.Start(context.Background(), "important op")
+	kept.End()
+	_, dropped := tracer.Start(context.Background(), "noise")
+	dropped.End()
+
+	spans := prov.RecordedSpans()
+	if len(spans) != 1 || spans[0].Name != "important op" {
+		t.Fatalf("expected only the span chosen by the custom sampler, got %d", len(spans))
+	}
+	if err := prov.SetSamplingRatio(0.5); err == nil {
+		t.Fatalf("expected ratio changes to be rejected with a custom sampler")
+	}
+	if err := prov.ApplyRemoteConfig(context.Background(), RemoteConfig{SamplingRatio: Float64(0.5)}); err == nil {
+		t.Fatalf("expected remote ratio changes to be rejected with a custom sampler")
+	}
+}
+
+// samplerFunc adapts a function to sdktrace.Sampler.
+type samplerFunc func(sdktrace.SamplingParameters) sdktrace.SamplingResult
+
+func (f samplerFunc) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult { return f(p) }
+
+func (f samplerFunc) Description() string { return "samplerFunc" }
+
 func TestSetupIncludesDefaultResourceDetectors(t *testing.T) {
 	restore := saveGlobal()
 	defer restore()
@@ -185,6 +398,33 @@ func TestSetupIncludesDefaultResourceDetectors(t *testing.T) {
 	_ = prov.Shutdown(ctx)
 }
 
+func TestSetupWithExternalTracerProvider(t *testing.T) {
+	restore := saveGlobal()
+	defer restore()
+
+	tp := sdktrace.NewTracerProvider()
+	defer tp.Shutdown(context.Background())
+
+	prov, err := Setup(context.Background(), Config{ServiceName: "svc"}, nil, WithTracerProvider(tp), WithGlobal())
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	if prov.TP != tp {
+		t.Fatalf("expected external tracer provider to be reused")
+	}
+	if otel.GetTracerProvider() != tp {
+		t.Fatalf("expected external tracer provider to be registered globally")
+	}
+	if err := prov.Shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown failed: %v", err)
+	}
+	_, span := tp.Tracer("test").Start(context.Background(), "still-alive")
+	defer span.End()
+	if !span.IsRecording() {
+		t.Fatalf("expected external tracer provider to stay usable after Provider.Shutdown")
+	}
+}
+
 func TestHTTPHelpers(t *testing.T) {
 	handler := HTTPHandler("op", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
@@ -202,6 +442,25 @@ func TestHTTPHelpers(t *testing.T) {
 	}
 }
 
+func TestHTTPTraceIDEcho(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	defer tp.Shutdown(context.Background())
+
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) })
+	for header, want := range map[string]string{"": DefaultTraceIDHeader, "X-Request-Trace": "X-Request-Trace"} {
+		handler := HTTPHandler("op", HTTPTraceIDEcho(header, ok), otelhttp.WithTracerProvider(tp))
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "http://localhost/", nil))
+
+		ended := recorder.Ended()
+		traceID := ended[len(ended)-1].SpanContext().TraceID().String()
+		if got := rec.Header().Get(want); got != traceID {
+			t.Fatalf("expected %s=%s, got %q", want, traceID, got)
+		}
+	}
+}
+
 func TestGRPCHandlers(t *testing.T) {
 	if GRPCServerHandler() == nil {
 		t.Fatalf("expected server handler")
@@ -264,3 +523,41 @@ func hasAttribute(res *resource.Resource, key attribute.Key, expected string) bo
 	}
 	return false
 }
+
+func spanHasAttribute(attrs []attribute.KeyValue, key attribute.Key, expected string) bool {
+	for _, attr := range attrs {
+		if attr.Key == key && attr.Value.Emit() == expected {
+			return true
+		}
+	}
+	return false
+}
+
+type failingDetector struct{}
+
+func (failingDetector) Detect(context.Context) (*resource.Resource, error) {
+	return nil, errors.New("detector failed")
+}
+
+// shutdownRecorder records whether Shutdown was called.
+type shutdownRecorder struct {
+	*tracetest.InMemoryExporter
+	shutdown bool
+}
+
+func (e *shutdownRecorder) Shutdown(ctx context.Context) error {
+	e.shutdown = true
+	return e.InMemoryExporter.Shutdown(ctx)
+}
+
+func TestSetupFailureReleasesExporters(t *testing.T) {
+	exporter := &shutdownRecorder{InMemoryExporter: tracetest.NewInMemoryExporter()}
+	_, err := Setup(context.Background(), Config{ServiceName: "svc", Metrics: true, Exporter: ExporterStdout}, nil,
+		WithSpanExporter(exporter), WithResourceOptions(resource.WithDetectors(failingDetector{})))
+	if err == nil {
+		t.Fatal("expected the failing resource detector to fail setup")
+	}
+	if !exporter.shutdown {
+		t.Fatal("expected the exporters built before the failure to be shut down")
+	}
+}
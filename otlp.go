@@ -0,0 +1,341 @@
+package otelx
+
+import (
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+)
+
+// spansToProto converts ended spans to their OTLP representation, grouped by resource and scope
+// the same way the OTLP exporters do.
+func spansToProto(spans []sdktrace.ReadOnlySpan) []*tracepb.ResourceSpans {
+	type scopeKey struct {
+		res   *resource.Resource
+		scope instrumentation.Scope
+	}
+	var (
+		out        []*tracepb.ResourceSpans
+		byResource = map[*resource.Resource]*tracepb.ResourceSpans{}
+		byScope    = map[scopeKey]*tracepb.ScopeSpans{}
+	)
+	for _, span := range spans {
+		if span == nil {
+			continue
+		}
+		res := span.Resource()
+		rs, ok := byResource[res]
+		if !ok {
+			rs = &tracepb.ResourceSpans{Resource: resourceToProto(res)}
+			if res != nil {
+				rs.SchemaUrl = res.SchemaURL()
+			}
+			byResource[res] = rs
+			out = append(out, rs)
+		}
+		scope := span.InstrumentationScope()
+		key := scopeKey{res: res, scope: scope}
+		ss, ok := byScope[key]
+		if !ok {
+			ss = &tracepb.ScopeSpans{
+				Scope: &commonpb.InstrumentationScope{
+					Name:       scope.Name,
+					Version:    scope.Version,
+					Attributes: attributesToProto(scope.Attributes.ToSlice()),
+				},
+				SchemaUrl: scope.SchemaURL,
+			}
+			byScope[key] = ss
+			rs.ScopeSpans = append(rs.ScopeSpans, ss)
+		}
+		ss.Spans = append(ss.Spans, spanToProto(span))
+	}
+	return out
+}
+
+func spanToProto(span sdktrace.ReadOnlySpan) *tracepb.Span {
+	sc := span.SpanContext()
+	tid, sid := sc.TraceID(), sc.SpanID()
+	pb := &tracepb.Span{
+		TraceId:                tid[:],
+		SpanId:                 sid[:],
+		TraceState:             sc.TraceState().String(),
+		Flags:                  uint32(sc.TraceFlags()),
+		Name:                   span.Name(),
+		Kind:                   spanKindToProto(span.SpanKind()),
+		StartTimeUnixNano:      uint64(max(span.StartTime().UnixNano(), 0)),
+		EndTimeUnixNano:        uint64(max(span.EndTime().UnixNano(), 0)),
+		Attributes:             attributesToProto(span.Attributes()),
+		DroppedAttributesCount: uint32(span.DroppedAttributes()),
+		DroppedEventsCount:     uint32(span.DroppedEvents()),
+		DroppedLinksCount:      uint32(span.DroppedLinks()),
+		Status:                 &tracepb.Status{Message: span.Status().Description, Code: statusCodeToProto(span.Status().Code)},
+	}
+	if parent := span.Parent(); parent.IsValid() {
+		psid := parent.SpanID()
+		pb.ParentSpanId = psid[:]
+	}
+	for _, ev := range span.Events() {
+		pb.Events = append(pb.Events, &tracepb.Span_Event{
+			TimeUnixNano:           uint64(max(ev.Time.UnixNano(), 0)),
+			Name:                   ev.Name,
+			Attributes:             attributesToProto(ev.Attributes),
+			DroppedAttributesCount: uint32(ev.DroppedAttributeCount),
+		})
+	}
+	for _, link := range span.Links() {
+		ltid, lsid := link.SpanContext.TraceID(), link.SpanContext.SpanID()
+		pb.Links = append(pb.Links, &tracepb.Span_Link{
+			TraceId:                ltid[:],
+			SpanId:                 lsid[:],
+			TraceState:             link.SpanContext.TraceState().String(),
+			Flags:                  uint32(link.SpanContext.TraceFlags()),
+			Attributes:             attributesToProto(link.Attributes),
+			DroppedAttributesCount: uint32(link.DroppedAttributeCount),
+		})
+	}
+	return pb
+}
+
+func resourceToProto(res *resource.Resource) *resourcepb.Resource {
+	if res == nil {
+		return &resourcepb.Resource{}
+	}
+	return &resourcepb.Resource{Attributes: attributesToProto(res.Attributes())}
+}
+
+func attributesToProto(attrs []attribute.KeyValue) []*commonpb.KeyValue {
+	if len(attrs) == 0 {
+		return nil
+	}
+	out := make([]*commonpb.KeyValue, 0, len(attrs))
+	for _, kv := range attrs {
+		out = append(out, &commonpb.KeyValue{Key: string(kv.Key), Value: valueToProto(kv.Value)})
+	}
+	return out
+}
+
+func valueToProto(v attribute.Value) *commonpb.AnyValue {
+	switch v.Type() {
+	case attribute.BOOL:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_BoolValue{BoolValue: v.AsBool()}}
+	case attribute.INT64:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{IntValue: v.AsInt64()}}
+	case attribute.FLOAT64:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_DoubleValue{DoubleValue: v.AsFloat64()}}
+	case attribute.STRING:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: v.AsString()}}
+	case attribute.BOOLSLICE:
+		return arrayToProto(v.AsBoolSlice(), func(b bool) *commonpb.AnyValue {
+			return &commonpb.AnyValue{Value: &commonpb.AnyValue_BoolValue{BoolValue: b}}
+		})
+	case attribute.INT64SLICE:
+		return arrayToProto(v.AsInt64Slice(), func(i int64) *commonpb.AnyValue {
+			return &commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{IntValue: i}}
+		})
+	case attribute.FLOAT64SLICE:
+		return arrayToProto(v.AsFloat64Slice(), func(f float64) *commonpb.AnyValue {
+			return &commonpb.AnyValue{Value: &commonpb.AnyValue_DoubleValue{DoubleValue: f}}
+		})
+	case attribute.STRINGSLICE:
+		return arrayToProto(v.AsStringSlice(), func(s string) *commonpb.AnyValue {
+			return &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: s}}
+		})
+	default:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: v.Emit()}}
+	}
+}
+
+func arrayToProto[T any](values []T, convert func(T) *commonpb.AnyValue) *commonpb.AnyValue {
+	array := &commonpb.ArrayValue{Values: make([]*commonpb.AnyValue, 0, len(values))}
+	for _, v := range values {
+		array.Values = append(array.Values, convert(v))
+	}
+	return &commonpb.AnyValue{Value: &commonpb.AnyValue_ArrayValue{ArrayValue: array}}
+}
+
+func spanKindToProto(kind trace.SpanKind) tracepb.Span_SpanKind {
+	switch kind {
+	case trace.SpanKindInternal:
+		return tracepb.Span_SPAN_KIND_INTERNAL
+	case trace.SpanKindServer:
+		return tracepb.Span_SPAN_KIND_SERVER
+	case trace.SpanKindClient:
+		return tracepb.Span_SPAN_KIND_CLIENT
+	case trace.SpanKindProducer:
+		return tracepb.Span_SPAN_KIND_PRODUCER
+	case trace.SpanKindConsumer:
+		return tracepb.Span_SPAN_KIND_CONSUMER
+	default:
+		return tracepb.Span_SPAN_KIND_UNSPECIFIED
+	}
+}
+
+func statusCodeToProto(code codes.Code) tracepb.Status_StatusCode {
+	switch code {
+	case codes.Ok:
+		return tracepb.Status_STATUS_CODE_OK
+	case codes.Error:
+		return tracepb.Status_STATUS_CODE_ERROR
+	default:
+		return tracepb.Status_STATUS_CODE_UNSET
+	}
+}
+
+// protoToSpans rebuilds read-only spans from their OTLP representation, the inverse of
+// spansToProto, e.g. to re-export spans persisted to disk.
+func protoToSpans(resourceSpans []*tracepb.ResourceSpans) []sdktrace.ReadOnlySpan {
+	var out []sdktrace.ReadOnlySpan
+	for _, rs := range resourceSpans {
+		res := resource.NewWithAttributes(rs.GetSchemaUrl(), attributesFromProto(rs.GetResource().GetAttributes())...)
+		for _, ss := range rs.GetScopeSpans() {
+			scope := instrumentation.Scope{
+				Name:       ss.GetScope().GetName(),
+				Version:    ss.GetScope().GetVersion(),
+				SchemaURL:  ss.GetSchemaUrl(),
+				Attributes: attribute.NewSet(attributesFromProto(ss.GetScope().GetAttributes())...),
+			}
+			for _, pb := range ss.GetSpans() {
+				out = append(out, spanFromProto(pb, res, scope))
+			}
+		}
+	}
+	return out
+}
+
+func spanFromProto(pb *tracepb.Span, res *resource.Resource, scope instrumentation.Scope) sdktrace.ReadOnlySpan {
+	var tid trace.TraceID
+	copy(tid[:], pb.GetTraceId())
+	stub := tracetest.SpanStub{
+		Name:                 pb.GetName(),
+		SpanContext:          spanContextFromProto(tid, pb.GetSpanId(), pb.GetTraceState(), pb.GetFlags()),
+		SpanKind:             spanKindFromProto(pb.GetKind()),
+		StartTime:            time.Unix(0, int64(pb.GetStartTimeUnixNano())),
+		EndTime:              time.Unix(0, int64(pb.GetEndTimeUnixNano())),
+		Attributes:           attributesFromProto(pb.GetAttributes()),
+		DroppedAttributes:    int(pb.GetDroppedAttributesCount()),
+		DroppedEvents:        int(pb.GetDroppedEventsCount()),
+		DroppedLinks:         int(pb.GetDroppedLinksCount()),
+		Status:               sdktrace.Status{Code: statusCodeFromProto(pb.GetStatus().GetCode()), Description: pb.GetStatus().GetMessage()},
+		Resource:             res,
+		InstrumentationScope: scope,
+	}
+	if len(pb.GetParentSpanId()) > 0 {
+		stub.Parent = spanContextFromProto(tid, pb.GetParentSpanId(), "", 0)
+	}
+	for _, ev := range pb.GetEvents() {
+		stub.Events = append(stub.Events, sdktrace.Event{
+			Name:                  ev.GetName(),
+			Time:                  time.Unix(0, int64(ev.GetTimeUnixNano())),
+			Attributes:            attributesFromProto(ev.GetAttributes()),
+			DroppedAttributeCount: int(ev.GetDroppedAttributesCount()),
+		})
+	}
+	for _, link := range pb.GetLinks() {
+		var ltid trace.TraceID
+		copy(ltid[:], link.GetTraceId())
+		stub.Links = append(stub.Links, sdktrace.Link{
+			SpanContext:           spanContextFromProto(ltid, link.GetSpanId(), link.GetTraceState(), link.GetFlags()),
+			Attributes:            attributesFromProto(link.GetAttributes()),
+			DroppedAttributeCount: int(link.GetDroppedAttributesCount()),
+		})
+	}
+	return stub.Snapshot()
+}
+
+func spanContextFromProto(tid trace.TraceID, spanID []byte, traceState string, flags uint32) trace.SpanContext {
+	var sid trace.SpanID
+	copy(sid[:], spanID)
+	ts, _ := trace.ParseTraceState(traceState)
+	return trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    tid,
+		SpanID:     sid,
+		TraceFlags: trace.TraceFlags(flags),
+		TraceState: ts,
+	})
+}
+
+func attributesFromProto(kvs []*commonpb.KeyValue) []attribute.KeyValue {
+	if len(kvs) == 0 {
+		return nil
+	}
+	out := make([]attribute.KeyValue, 0, len(kvs))
+	for _, kv := range kvs {
+		out = append(out, attribute.KeyValue{Key: attribute.Key(kv.GetKey()), Value: valueFromProto(kv.GetValue())})
+	}
+	return out
+}
+
+// valueFromProto converts the values valueToProto produces; arrays take the type of their first
+// element.
+func valueFromProto(v *commonpb.AnyValue) attribute.Value {
+	switch x := v.GetValue().(type) {
+	case *commonpb.AnyValue_BoolValue:
+		return attribute.BoolValue(x.BoolValue)
+	case *commonpb.AnyValue_IntValue:
+		return attribute.Int64Value(x.IntValue)
+	case *commonpb.AnyValue_DoubleValue:
+		return attribute.Float64Value(x.DoubleValue)
+	case *commonpb.AnyValue_ArrayValue:
+		values := x.ArrayValue.GetValues()
+		if len(values) == 0 {
+			return attribute.StringSliceValue(nil)
+		}
+		switch values[0].GetValue().(type) {
+		case *commonpb.AnyValue_BoolValue:
+			return attribute.BoolSliceValue(arrayFromProto(values, (*commonpb.AnyValue).GetBoolValue))
+		case *commonpb.AnyValue_IntValue:
+			return attribute.Int64SliceValue(arrayFromProto(values, (*commonpb.AnyValue).GetIntValue))
+		case *commonpb.AnyValue_DoubleValue:
+			return attribute.Float64SliceValue(arrayFromProto(values, (*commonpb.AnyValue).GetDoubleValue))
+		default:
+			return attribute.StringSliceValue(arrayFromProto(values, (*commonpb.AnyValue).GetStringValue))
+		}
+	default:
+		return attribute.StringValue(v.GetStringValue())
+	}
+}
+
+func arrayFromProto[T any](values []*commonpb.AnyValue, convert func(*commonpb.AnyValue) T) []T {
+	out := make([]T, len(values))
+	for i, v := range values {
+		out[i] = convert(v)
+	}
+	return out
+}
+
+func spanKindFromProto(kind tracepb.Span_SpanKind) trace.SpanKind {
+	switch kind {
+	case tracepb.Span_SPAN_KIND_INTERNAL:
+		return trace.SpanKindInternal
+	case tracepb.Span_SPAN_KIND_SERVER:
+		return trace.SpanKindServer
+	case tracepb.Span_SPAN_KIND_CLIENT:
+		return trace.SpanKindClient
+	case tracepb.Span_SPAN_KIND_PRODUCER:
+		return trace.SpanKindProducer
+	case tracepb.Span_SPAN_KIND_CONSUMER:
+		return trace.SpanKindConsumer
+	default:
+		return trace.SpanKindUnspecified
+	}
+}
+
+func statusCodeFromProto(code tracepb.Status_StatusCode) codes.Code {
+	switch code {
+	case tracepb.Status_STATUS_CODE_OK:
+		return codes.Ok
+	case tracepb.Status_STATUS_CODE_ERROR:
+		return codes.Error
+	default:
+		return codes.Unset
+	}
+}
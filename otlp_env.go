@@ -0,0 +1,131 @@
+package otelx
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// otlpEnv reads the standard OTLP exporter variable name, preferring the traces-specific
+// OTEL_EXPORTER_OTLP_TRACES_<name> over OTEL_EXPORTER_OTLP_<name>. It returns the value, the
+// variable it came from and whether that was the traces-specific one.
+func otlpEnv(getenv func(string) string, name string) (value, from string, signal bool) {
+	if v := strings.TrimSpace(getenv("OTEL_EXPORTER_OTLP_TRACES_" + name)); v != "" {
+		return v, "OTEL_EXPORTER_OTLP_TRACES_" + name, true
+	}
+	from = "OTEL_EXPORTER_OTLP_" + name
+	return strings.TrimSpace(getenv(from)), from, false
+}
+
+// applyOTLPEnv fills the OTLP settings of the single exporter that Config leaves empty from the
+// standard OTEL_EXPORTER_OTLP_* variables (ENDPOINT, PROTOCOL, HEADERS, INSECURE, COMPRESSION,
+// TIMEOUT, CERTIFICATE, CLIENT_CERTIFICATE, CLIENT_KEY and their TRACES_ variants), so otelx
+// follows deployments configured by Kubernetes operators like the stock SDK. An unset Exporter
+// becomes otlp or otlphttp once an endpoint or protocol is set. Nothing is applied with Exporters
+// or a non-OTLP exporter. Invalid variables are skipped and reported in the returned error.
+func (cfg Config) applyOTLPEnv(getenv func(string) string) (Config, error) {
+	if len(cfg.Exporters) > 0 || (cfg.Exporter != "" && cfg.Exporter != ExporterOTLP && cfg.Exporter != ExporterOTLPHTTP) {
+		return cfg, nil
+	}
+	var (
+		errs      []error
+		urlScheme bool // the endpoint's scheme already decided Insecure
+	)
+
+	if protocol, from, _ := otlpEnv(getenv, "PROTOCOL"); protocol != "" && cfg.Exporter == "" {
+		switch protocol {
+		case "grpc":
+			cfg.Exporter = ExporterOTLP
+		case "http/protobuf":
+			cfg.Exporter = ExporterOTLPHTTP
+		default:
+			errs = append(errs, fmt.Errorf("%s: unsupported protocol %q", from, protocol))
+		}
+	}
+	if endpoint, _, signal := otlpEnv(getenv, "ENDPOINT"); endpoint != "" && cfg.Endpoint == "" {
+		u, err := url.Parse(endpoint)
+		isURL := err == nil && u.Host != ""
+		urlScheme = isURL
+		if cfg.Exporter == "" {
+			cfg.Exporter = ExporterOTLPHTTP
+			if (isURL && u.Port() == "4317") || strings.HasSuffix(endpoint, ":4317") {
+				cfg.Exporter = ExporterOTLP
+			}
+		}
+		// OTEL_EXPORTER_OTLP_ENDPOINT is a base URL for OTLP/HTTP; the signal path is appended.
+		if isURL && !signal && cfg.Exporter == ExporterOTLPHTTP && strings.Trim(u.Path, "/") != "" {
+			u.Path = strings.TrimSuffix(u.Path, "/") + "/v1/traces"
+			endpoint = u.String()
+		}
+		cfg.Endpoint = endpoint
+		if ec := cfg.primaryExporter().resolveEndpointURL(); ec.Endpoint != cfg.Endpoint {
+			cfg.Exporter, cfg.Endpoint, cfg.URLPath, cfg.Insecure = ec.Exporter, ec.Endpoint, ec.URLPath, ec.Insecure
+			cfg.httpsEndpoint = ec.httpsEndpoint
+		}
+	}
+	if cfg.Exporter == "" {
+		return cfg, errors.Join(errs...)
+	}
+
+	if raw, from, _ := otlpEnv(getenv, "HEADERS"); raw != "" && len(cfg.Headers) == 0 {
+		headers, err := parseKeyValueList(raw)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", from, err))
+		} else {
+			cfg.Headers = headers
+		}
+	}
+	if raw, from, _ := otlpEnv(getenv, "INSECURE"); raw != "" && !urlScheme && !cfg.Insecure && cfg.TLS == nil {
+		insecure, err := strconv.ParseBool(raw)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", from, err))
+		} else {
+			cfg.Insecure = insecure
+		}
+	}
+	if compression, _, _ := otlpEnv(getenv, "COMPRESSION"); compression != "" && cfg.Compression == "" {
+		cfg.Compression = strings.ToLower(compression)
+	}
+	if raw, from, _ := otlpEnv(getenv, "TIMEOUT"); raw != "" && cfg.ExporterTimeout == 0 {
+		ms, err := strconv.Atoi(raw)
+		if err != nil || ms <= 0 {
+			errs = append(errs, fmt.Errorf("%s: want a positive number of milliseconds, got %q", from, raw))
+		} else {
+			cfg.ExporterTimeout = time.Duration(ms) * time.Millisecond
+		}
+	}
+	if cfg.TLS == nil && !cfg.Insecure {
+		ca, _, _ := otlpEnv(getenv, "CERTIFICATE")
+		cert, _, _ := otlpEnv(getenv, "CLIENT_CERTIFICATE")
+		key, _, _ := otlpEnv(getenv, "CLIENT_KEY")
+		if ca != "" || cert != "" || key != "" {
+			cfg.TLS = &TLSConfig{CAFile: ca, CertFile: cert, KeyFile: key}
+		}
+	}
+	return cfg, errors.Join(errs...)
+}
+
+// parseKeyValueList parses the format of OTEL_EXPORTER_OTLP_HEADERS and OTEL_RESOURCE_ATTRIBUTES:
+// comma-separated key=value pairs with URL-encoded values.
+func parseKeyValueList(raw string) (map[string]string, error) {
+	values := map[string]string{}
+	for _, pair := range strings.Split(raw, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(pair, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid entry %q, want key=value", pair)
+		}
+		decoded, err := url.PathUnescape(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("%q: %w", key, err)
+		}
+		values[key] = decoded
+	}
+	return values, nil
+}
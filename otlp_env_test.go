@@ -0,0 +1,90 @@
+package otelx
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func envMap(vars map[string]string) func(string) string {
+	return func(name string) string { return vars[name] }
+}
+
+func TestApplyOTLPEnv(t *testing.T) {
+	cfg, err := Config{ServiceName: "svc"}.applyOTLPEnv(envMap(map[string]string{
+		"OTEL_EXPORTER_OTLP_ENDPOINT":    "http://collector:4318/otlp",
+		"OTEL_EXPORTER_OTLP_HEADERS":     "x-api-key=abc%20def, x-team=core",
+		"OTEL_EXPORTER_OTLP_COMPRESSION": "GZIP",
+		"OTEL_EXPORTER_OTLP_TIMEOUT":     "2500",
+		"OTEL_EXPORTER_OTLP_INSECURE":    "false",
+	}))
+	if err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	want := ExporterConfig{
+		Exporter:        ExporterOTLPHTTP,
+		Endpoint:        "collector:4318",
+		URLPath:         "/otlp/v1/traces",
+		Insecure:        true,
+		Headers:         map[string]string{"x-api-key": "abc def", "x-team": "core"},
+		Compression:     CompressionGzip,
+		ExporterTimeout: 2500 * time.Millisecond,
+	}
+	if got := cfg.primaryExporter(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+
+	cfg, err = Config{ServiceName: "svc"}.applyOTLPEnv(envMap(map[string]string{
+		"OTEL_EXPORTER_OTLP_ENDPOINT":        "collector:4318",
+		"OTEL_EXPORTER_OTLP_TRACES_ENDPOINT": "collector.example.com:4317",
+		"OTEL_EXPORTER_OTLP_TRACES_PROTOCOL": "grpc",
+		"OTEL_EXPORTER_OTLP_CERTIFICATE":     "/etc/otel/ca.pem",
+	}))
+	if err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	if cfg.Exporter != ExporterOTLP || cfg.Endpoint != "collector.example.com:4317" || cfg.TLS == nil || cfg.TLS.CAFile != "/etc/otel/ca.pem" {
+		t.Fatalf("expected the traces-specific grpc settings, got %+v", cfg.primaryExporter())
+	}
+}
+
+func TestApplyOTLPEnvKeepsConfig(t *testing.T) {
+	env := envMap(map[string]string{
+		"OTEL_EXPORTER_OTLP_ENDPOINT": "http://env-collector:4318",
+		"OTEL_EXPORTER_OTLP_HEADERS":  "x-api-key=env",
+	})
+	base := Config{ServiceName: "svc", Exporter: ExporterOTLP, Endpoint: "collector:4317", Headers: map[string]string{"x-api-key": "cfg"}}
+	if cfg, _ := base.applyOTLPEnv(env); !reflect.DeepEqual(cfg, base) {
+		t.Fatalf("expected explicit settings to win, got %+v", cfg.primaryExporter())
+	}
+	cloud := Config{ServiceName: "svc", Exporter: ExporterCloudTrace, GCPProjectID: "p"}
+	if cfg, _ := cloud.applyOTLPEnv(env); !reflect.DeepEqual(cfg, cloud) {
+		t.Fatalf("expected non-OTLP exporters to ignore the variables, got %+v", cfg.primaryExporter())
+	}
+	if cfg, _ := (Config{ServiceName: "svc"}).applyOTLPEnv(envMap(nil)); cfg.Exporter != "" {
+		t.Fatalf("expected stdout without variables, got %q", cfg.Exporter)
+	}
+
+	_, err := Config{ServiceName: "svc"}.applyOTLPEnv(envMap(map[string]string{
+		"OTEL_EXPORTER_OTLP_PROTOCOL": "http/json",
+		"OTEL_EXPORTER_OTLP_ENDPOINT": "collector:4317",
+		"OTEL_EXPORTER_OTLP_TIMEOUT":  "soon",
+		"OTEL_EXPORTER_OTLP_HEADERS":  "novalue",
+	}))
+	if err == nil {
+		t.Fatal("expected invalid variables to be reported")
+	}
+}
+
+func TestSetupUsesOTLPEnv(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "http://localhost:4318")
+	prov, err := Setup(context.Background(), Config{ServiceName: "svc"}, nil)
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	defer prov.Shutdown(context.Background())
+	if d := prov.Describe(); d.Exporters[0].Exporter != ExporterOTLPHTTP || d.Exporters[0].Endpoint != "localhost:4318" {
+		t.Fatalf("expected the env endpoint to select otlphttp, got %+v", d.Exporters)
+	}
+}
@@ -0,0 +1,59 @@
+package otelx
+
+import (
+	"errors"
+	"time"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+)
+
+// OTLPRetryConfig tunes how the otlp and otlphttp exporters retry a batch the collector rejected
+// with a retryable error, using exponential backoff. Zero fields keep the exporter defaults: retries
+// enabled, 5s initial interval, 30s max interval and 1m max elapsed time.
+type OTLPRetryConfig struct {
+	// Enabled=false sends every batch once. Nil keeps retries enabled.
+	Enabled *bool `json:"enabled"`
+	// InitialInterval is the wait before the first retry.
+	InitialInterval time.Duration `json:"initialInterval"`
+	// MaxInterval caps the wait between retries.
+	MaxInterval time.Duration `json:"maxInterval"`
+	// MaxElapsedTime gives up on the batch once this long has passed since the first attempt.
+	MaxElapsedTime time.Duration `json:"maxElapsedTime"`
+}
+
+func (c OTLPRetryConfig) validate() error {
+	if c.InitialInterval < 0 || c.MaxInterval < 0 || c.MaxElapsedTime < 0 {
+		return errors.New("initialInterval, maxInterval and maxElapsedTime must not be negative")
+	}
+	if c.InitialInterval > 0 && c.MaxInterval > 0 && c.InitialInterval > c.MaxInterval {
+		return errors.New("initialInterval must not exceed maxInterval")
+	}
+	return nil
+}
+
+// grpcRetry returns the otlptracegrpc retry settings for c.
+func (c OTLPRetryConfig) grpcRetry() otlptracegrpc.RetryConfig {
+	r := otlptracegrpc.RetryConfig{Enabled: true, InitialInterval: 5 * time.Second, MaxInterval: 30 * time.Second, MaxElapsedTime: time.Minute}
+	if c.Enabled != nil {
+		r.Enabled = *c.Enabled
+	}
+	if c.InitialInterval > 0 {
+		r.InitialInterval = c.InitialInterval
+	}
+	if c.MaxInterval > 0 {
+		r.MaxInterval = c.MaxInterval
+	}
+	if c.MaxElapsedTime > 0 {
+		r.MaxElapsedTime = c.MaxElapsedTime
+	}
+	if r.InitialInterval > r.MaxInterval {
+		r.MaxInterval = r.InitialInterval
+	}
+	return r
+}
+
+// httpRetry returns the otlptracehttp retry settings for c.
+func (c OTLPRetryConfig) httpRetry() otlptracehttp.RetryConfig {
+	return otlptracehttp.RetryConfig(c.grpcRetry())
+}
@@ -0,0 +1,73 @@
+package otelx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestOTLPRetry(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: trace.TraceID{1}, SpanID: trace.SpanID{1}, TraceFlags: trace.FlagsSampled,
+	})
+	spans := []sdktrace.ReadOnlySpan{tracetest.SpanStub{Name: "op", SpanContext: sc}.Snapshot()}
+	export := func(retry OTLPRetryConfig) int32 {
+		requests.Store(0)
+		ec := ExporterConfig{
+			Exporter:  ExporterOTLPHTTP,
+			Endpoint:  strings.TrimPrefix(srv.URL, "http://"),
+			Insecure:  true,
+			OTLPRetry: &retry,
+		}
+		exporter, err := buildExporter(context.Background(), ec, nil)
+		if err != nil {
+			t.Fatalf("build exporter: %v", err)
+		}
+		defer exporter.Shutdown(context.Background())
+		if err := exporter.ExportSpans(context.Background(), spans); err == nil {
+			t.Fatal("expected the export to fail")
+		}
+		return requests.Load()
+	}
+
+	if n := export(OTLPRetryConfig{Enabled: Bool(false)}); n != 1 {
+		t.Fatalf("expected a single attempt with retries disabled, got %d", n)
+	}
+	if n := export(OTLPRetryConfig{InitialInterval: 5 * time.Millisecond, MaxInterval: 10 * time.Millisecond, MaxElapsedTime: 200 * time.Millisecond}); n < 2 {
+		t.Fatalf("expected retries, got %d attempts", n)
+	}
+}
+
+func TestOTLPRetryValidation(t *testing.T) {
+	cases := map[string]Config{
+		"negative":     {ServiceName: "svc", Exporter: ExporterOTLP, OTLPRetry: &OTLPRetryConfig{MaxElapsedTime: -time.Second}},
+		"inverted":     {ServiceName: "svc", Exporter: ExporterOTLP, OTLPRetry: &OTLPRetryConfig{InitialInterval: time.Minute, MaxInterval: time.Second}},
+		"non-otlp":     {ServiceName: "svc", Exporter: ExporterStdout, OTLPRetry: &OTLPRetryConfig{}},
+		"with-exports": {ServiceName: "svc", OTLPRetry: &OTLPRetryConfig{}, Exporters: []ExporterConfig{{Exporter: ExporterOTLP}}},
+	}
+	for name, cfg := range cases {
+		if _, err := Setup(context.Background(), cfg, nil); err == nil {
+			t.Fatalf("%s: expected validation error", name)
+		}
+	}
+
+	r := OTLPRetryConfig{InitialInterval: time.Minute}.grpcRetry()
+	if !r.Enabled || r.MaxInterval != time.Minute || r.MaxElapsedTime != time.Minute {
+		t.Fatalf("unexpected retry settings %+v", r)
+	}
+}
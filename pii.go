@@ -0,0 +1,65 @@
+package otelx
+
+import (
+	"regexp"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+var (
+	// sqlLiteral matches quoted strings and standalone numbers in SQL text.
+	sqlLiteral = regexp.MustCompile(`'(?:[^']|'')*'|\b\d+(?:\.\d+)?\b`)
+	// urlUserinfo matches the "user:password@" part of an absolute URL.
+	urlUserinfo = regexp.MustCompile(`^([A-Za-z][A-Za-z0-9+.-]*://)[^/?#@]*@`)
+)
+
+// piiHeaders are request/response header attributes (http.*.header.<name>) carrying credentials.
+var piiHeaders = map[string]bool{
+	"authorization":       true,
+	"proxy-authorization": true,
+	"cookie":              true,
+	"set-cookie":          true,
+	"x-api-key":           true,
+}
+
+// scrubPII masks well-known sensitive semantic-convention attributes regardless of the
+// instrumentation that set them: enduser ids are hashed, credential headers replaced, SQL literals
+// turned into "?" and URL userinfo into "REDACTED:REDACTED". It reports whether v changed.
+func scrubPII(key attribute.Key, v attribute.Value) (attribute.Value, bool) {
+	k := string(key)
+	switch {
+	case k == "enduser.id" || k == "enduser.pseudo.id":
+		if v.Type() == attribute.STRING && v.AsString() != "" {
+			return attribute.StringValue(hashRedaction(v.AsString())), true
+		}
+	case k == "db.statement" || k == "db.query.text":
+		if v.Type() == attribute.STRING {
+			if s := sqlLiteral.ReplaceAllString(v.AsString(), "?"); s != v.AsString() {
+				return attribute.StringValue(s), true
+			}
+		}
+	case k == "url.full" || k == "http.url" || k == "url.original":
+		if v.Type() == attribute.STRING {
+			if s := urlUserinfo.ReplaceAllString(v.AsString(), "${1}REDACTED:REDACTED@"); s != v.AsString() {
+				return attribute.StringValue(s), true
+			}
+		}
+	case strings.HasPrefix(k, "http.request.header.") || strings.HasPrefix(k, "http.response.header."):
+		name := strings.TrimPrefix(strings.TrimPrefix(k, "http.request.header."), "http.response.header.")
+		if !piiHeaders[strings.ToLower(name)] {
+			break
+		}
+		switch v.Type() {
+		case attribute.STRING:
+			return attribute.StringValue(DefaultRedactionReplacement), true
+		case attribute.STRINGSLICE:
+			values := make([]string, len(v.AsStringSlice()))
+			for i := range values {
+				values[i] = DefaultRedactionReplacement
+			}
+			return attribute.StringSliceValue(values), true
+		}
+	}
+	return v, false
+}
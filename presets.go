@@ -0,0 +1,138 @@
+package otelx
+
+import (
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// vendorPreset holds the OTLP settings a vendor documents for trace ingestion.
+type vendorPreset struct {
+	exporter ExporterType
+	endpoint string
+	urlPath  string
+	insecure bool
+	// authHeader receives the API key; empty means the vendor needs no key.
+	authHeader string
+	// authValue formats the API key into the header value, defaulting to the key itself.
+	authValue func(apiKey string) (string, error)
+	// endpointRequired is set for vendors whose endpoint is tenant specific.
+	endpointRequired bool
+}
+
+var vendorPresets = map[string]vendorPreset{
+	"honeycomb": {
+		exporter:   ExporterOTLP,
+		endpoint:   "api.honeycomb.io:443",
+		authHeader: "x-honeycomb-team",
+	},
+	// Grafana Cloud's endpoint is per stack (otlp-gateway-<zone>.grafana.net) and its API key is
+	// "<instance id>:<token>", sent as basic auth.
+	"grafana-cloud": {
+		exporter:         ExporterOTLPHTTP,
+		urlPath:          "/otlp/v1/traces",
+		authHeader:       "Authorization",
+		authValue:        grafanaCloudAuth,
+		endpointRequired: true,
+	},
+	"datadog-agent": {
+		exporter: ExporterOTLP,
+		endpoint: "localhost:4317",
+		insecure: true,
+	},
+	"signoz": {
+		exporter:   ExporterOTLP,
+		endpoint:   "ingest.us.signoz.cloud:443",
+		authHeader: "signoz-ingestion-key",
+	},
+	"newrelic": {
+		exporter:   ExporterOTLP,
+		endpoint:   "otlp.nr-data.net:4317",
+		authHeader: "api-key",
+	},
+}
+
+func grafanaCloudAuth(apiKey string) (string, error) {
+	if !strings.Contains(apiKey, ":") {
+		return "", fmt.Errorf("apiKey must be \"<instance id>:<token>\" for preset grafana-cloud")
+	}
+	return "Basic " + base64.StdEncoding.EncodeToString([]byte(apiKey)), nil
+}
+
+// presetNames lists the supported presets for error messages.
+func presetNames() string {
+	names := make([]string, 0, len(vendorPresets))
+	for name := range vendorPresets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// applyPreset fills exporter, endpoint, URL path, TLS and auth header from ec.Preset. Explicit
+// settings win over preset defaults. Problems are reported by validatePreset.
+func (ec ExporterConfig) applyPreset() ExporterConfig {
+	preset, ok := vendorPresets[ec.Preset]
+	if !ok || (ec.Exporter != "" && ec.Exporter != preset.exporter) {
+		return ec
+	}
+	ec.Exporter = preset.exporter
+	if ec.Endpoint == "" {
+		ec.Endpoint = preset.endpoint
+	}
+	if ec.URLPath == "" {
+		ec.URLPath = preset.urlPath
+	}
+	ec.Insecure = ec.Insecure || preset.insecure
+	if preset.authHeader != "" && ec.APIKey != "" {
+		value := ec.APIKey
+		if preset.authValue != nil {
+			var err error
+			if value, err = preset.authValue(ec.APIKey); err != nil {
+				return ec
+			}
+		}
+		if _, set := ec.Headers[preset.authHeader]; !set {
+			headers := make(map[string]string, len(ec.Headers)+1)
+			for k, v := range ec.Headers {
+				headers[k] = v
+			}
+			headers[preset.authHeader] = value
+			ec.Headers = headers
+		}
+	}
+	return ec
+}
+
+// validatePreset checks ec.Preset and the settings it depends on.
+func (ec ExporterConfig) validatePreset() error {
+	if ec.Preset == "" {
+		if ec.APIKey != "" {
+			return fmt.Errorf("apiKey is only supported together with preset")
+		}
+		return nil
+	}
+	preset, ok := vendorPresets[ec.Preset]
+	if !ok {
+		return fmt.Errorf("unsupported preset %q (supported: %s)", ec.Preset, presetNames())
+	}
+	if ec.Exporter != preset.exporter {
+		return fmt.Errorf("preset %s requires exporter=%s", ec.Preset, preset.exporter)
+	}
+	if preset.endpointRequired && ec.Endpoint == "" {
+		return fmt.Errorf("endpoint is required for preset %s", ec.Preset)
+	}
+	if preset.authHeader == "" {
+		return nil
+	}
+	if ec.APIKey == "" {
+		return fmt.Errorf("apiKey is required for preset %s", ec.Preset)
+	}
+	if preset.authValue != nil {
+		if _, err := preset.authValue(ec.APIKey); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package otelx
+
+import (
+	"context"
+	"encoding/base64"
+	"strings"
+	"testing"
+)
+
+func TestPresetFillsVendorSettings(t *testing.T) {
+	cfg := Config{ServiceName: "svc", Preset: "Honeycomb", APIKey: "hc-key"}.sanitize()
+	if err := cfg.validate(); err != nil {
+		t.Fatalf("validate failed: %v", err)
+	}
+	if cfg.Exporter != ExporterOTLP || cfg.Endpoint != "api.honeycomb.io:443" || cfg.Headers["x-honeycomb-team"] != "hc-key" {
+		t.Fatalf("unexpected honeycomb settings: %+v", cfg.primaryExporter())
+	}
+
+	ec := ExporterConfig{Preset: "grafana-cloud", APIKey: "123:tok", Endpoint: "otlp-gateway-prod-eu-west-2.grafana.net"}.sanitize()
+	if err := ec.validate(); err != nil {
+		t.Fatalf("validate failed: %v", err)
+	}
+	want := "Basic " + base64.StdEncoding.EncodeToString([]byte("123:tok"))
+	if ec.Exporter != ExporterOTLPHTTP || ec.URLPath != "/otlp/v1/traces" || ec.Headers["Authorization"] != want {
+		t.Fatalf("unexpected grafana settings: %+v", ec)
+	}
+
+	ec = ExporterConfig{Preset: "datadog-agent", Endpoint: "dd-agent:4317"}.sanitize()
+	if err := ec.validate(); err != nil || !ec.Insecure || ec.Endpoint != "dd-agent:4317" {
+		t.Fatalf("expected explicit endpoint to win over the preset, got %+v (%v)", ec, err)
+	}
+}
+
+func TestPresetKeepsExplicitHeaders(t *testing.T) {
+	ec := ExporterConfig{Preset: "newrelic", APIKey: "nr", Headers: map[string]string{"api-key": "override", "x": "y"}}.sanitize()
+	if ec.Headers["api-key"] != "override" || ec.Headers["x"] != "y" {
+		t.Fatalf("expected explicit headers to win, got %v", ec.Headers)
+	}
+}
+
+func TestPresetValidation(t *testing.T) {
+	for name, cfg := range map[string]Config{
+		"unknown":        {ServiceName: "svc", Preset: "acme", APIKey: "k"},
+		"missing key":    {ServiceName: "svc", Preset: "signoz"},
+		"endpoint":       {ServiceName: "svc", Preset: "grafana-cloud", APIKey: "1:t"},
+		"grafana key":    {ServiceName: "svc", Preset: "grafana-cloud", APIKey: "s3cr3t", Endpoint: "gw.grafana.net"},
+		"exporter clash": {ServiceName: "svc", Preset: "honeycomb", APIKey: "k", Exporter: ExporterZipkin},
+		"key no preset":  {ServiceName: "svc", APIKey: "k"},
+	} {
+		_, err := Setup(context.Background(), cfg, nil)
+		if err == nil {
+			t.Fatalf("%s: expected validation error", name)
+		}
+		if strings.Contains(err.Error(), "s3cr3t") {
+			t.Fatalf("%s: error leaks the api key: %v", name, err)
+		}
+	}
+}
+
+func TestSetupWithPreset(t *testing.T) {
+	prov, err := Setup(context.Background(), Config{ServiceName: "svc", Preset: "honeycomb", APIKey: "hc-key"}, noopLogger{})
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	_ = prov.Shutdown(context.Background())
+}
@@ -0,0 +1,70 @@
+package otelx
+
+import (
+	"context"
+	"net/http"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/stats"
+)
+
+// PrincipalFunc returns the authenticated principal for a request, or "" when anonymous.
+type PrincipalFunc func(ctx context.Context) string
+
+// recordPrincipal sets enduser.id on the span in ctx when fn resolves a principal.
+func recordPrincipal(ctx context.Context, fn PrincipalFunc) {
+	if fn == nil {
+		return
+	}
+	if principal := fn(ctx); principal != "" {
+		trace.SpanFromContext(ctx).SetAttributes(semconv.EnduserID(principal))
+	}
+}
+
+// HTTPPrincipal records the principal resolved by fn as enduser.id on the server span.
+// Install it inside HTTPHandler and after the auth middleware so fn can read what auth stored in the context.
+func HTTPPrincipal(fn PrincipalFunc, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		recordPrincipal(r.Context(), fn)
+		next.ServeHTTP(w, r)
+	})
+}
+
+// GRPCServerHandlerWithPrincipal returns an otelgrpc server handler that records the principal
+// resolved by fn as enduser.id. The handler sees incoming metadata and peer info (e.g. mTLS identities);
+// principals stored by auth interceptors need GRPCPrincipalUnaryInterceptor / GRPCPrincipalStreamInterceptor.
+func GRPCServerHandlerWithPrincipal(fn PrincipalFunc, opts ...otelgrpc.Option) stats.Handler {
+	return &principalServerHandler{Handler: otelgrpc.NewServerHandler(opts...), principal: fn}
+}
+
+type principalServerHandler struct {
+	stats.Handler
+	principal PrincipalFunc
+}
+
+func (h *principalServerHandler) HandleRPC(ctx context.Context, rs stats.RPCStats) {
+	if _, ok := rs.(*stats.InHeader); ok {
+		recordPrincipal(ctx, h.principal)
+	}
+	h.Handler.HandleRPC(ctx, rs)
+}
+
+// GRPCPrincipalUnaryInterceptor records the principal resolved by fn as enduser.id on the server span.
+// Chain it after the auth interceptor.
+func GRPCPrincipalUnaryInterceptor(fn PrincipalFunc) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		recordPrincipal(ctx, fn)
+		return handler(ctx, req)
+	}
+}
+
+// GRPCPrincipalStreamInterceptor is the streaming counterpart of GRPCPrincipalUnaryInterceptor.
+func GRPCPrincipalStreamInterceptor(fn PrincipalFunc) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		recordPrincipal(ss.Context(), fn)
+		return handler(srv, ss)
+	}
+}
@@ -0,0 +1,65 @@
+package otelx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/stats"
+)
+
+func TestHTTPPrincipalRecordsEnduser(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	defer tp.Shutdown(context.Background())
+
+	principal := func(ctx context.Context) string {
+		id, _ := ctx.Value(userIDKey{}).(string)
+		return id
+	}
+	auth := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), userIDKey{}, "alice")))
+		})
+	}
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) })
+	handler := HTTPHandler("op", auth(HTTPPrincipal(principal, ok)), otelhttp.WithTracerProvider(tp))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://localhost/", nil))
+
+	ended := recorder.Ended()
+	if len(ended) != 1 || !spanHasAttribute(ended[0].Attributes(), semconv.EnduserIDKey, "alice") {
+		t.Fatalf("expected enduser.id=alice on server span")
+	}
+}
+
+func TestGRPCServerHandlerWithPrincipal(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	defer tp.Shutdown(context.Background())
+
+	principal := func(ctx context.Context) string {
+		if md, ok := metadata.FromIncomingContext(ctx); ok && len(md.Get("x-user")) > 0 {
+			return md.Get("x-user")[0]
+		}
+		return ""
+	}
+	h := GRPCServerHandlerWithPrincipal(principal, otelgrpc.WithTracerProvider(tp))
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-user", "bob"))
+	ctx = h.TagRPC(ctx, &stats.RPCTagInfo{FullMethodName: "/svc.v1.Svc/Get"})
+	h.HandleRPC(ctx, &stats.InHeader{})
+	h.HandleRPC(ctx, &stats.End{})
+
+	ended := recorder.Ended()
+	if len(ended) != 1 || !spanHasAttribute(ended[0].Attributes(), semconv.EnduserIDKey, "bob") {
+		t.Fatalf("expected enduser.id=bob on server span")
+	}
+}
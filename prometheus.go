@@ -0,0 +1,39 @@
+package otelx
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	otelprom "go.opentelemetry.io/otel/exporters/prometheus"
+)
+
+// prometheusEndpoint is the pull-based metrics reader created by WithPrometheus. It uses its own
+// registry so otelx never collides with collectors registered on prometheus.DefaultRegisterer.
+type prometheusEndpoint struct {
+	reader  *otelprom.Exporter
+	handler http.Handler
+}
+
+func newPrometheusEndpoint() (*prometheusEndpoint, error) {
+	registry := prometheus.NewRegistry()
+	reader, err := otelprom.New(otelprom.WithRegisterer(registry))
+	if err != nil {
+		return nil, fmt.Errorf("otelx: create prometheus exporter: %w", err)
+	}
+	return &prometheusEndpoint{
+		reader:  reader,
+		handler: promhttp.HandlerFor(registry, promhttp.HandlerOpts{EnableOpenMetrics: true}),
+	}, nil
+}
+
+// MetricsHandler serves the Provider's metrics in the Prometheus exposition format, for mounting
+// at /metrics. Scrapers that negotiate OpenMetrics also receive exemplars. It is nil unless Setup
+// was called with WithPrometheus.
+func (p *Provider) MetricsHandler() http.Handler {
+	if p == nil || p.prometheus == nil {
+		return nil
+	}
+	return p.prometheus.handler
+}
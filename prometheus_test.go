@@ -0,0 +1,47 @@
+package otelx
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestWithPrometheusServesMetrics(t *testing.T) {
+	p, err := Setup(context.Background(), Config{ServiceName: "svc", Exporter: ExporterMemory}, nil, WithPrometheus())
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	defer p.Shutdown(context.Background())
+	if p.MP == nil || p.MetricsHandler() == nil {
+		t.Fatalf("expected MeterProvider and metrics handler")
+	}
+
+	counter, err := p.MP.Meter("test").Int64Counter("jobs.processed")
+	if err != nil {
+		t.Fatalf("create counter: %v", err)
+	}
+	counter.Add(context.Background(), 2)
+
+	rec := httptest.NewRecorder()
+	p.MetricsHandler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body, _ := io.ReadAll(rec.Body)
+	if !strings.Contains(string(body), "jobs_processed_total") {
+		t.Fatalf("expected counter in exposition, got:\n%s", body)
+	}
+	if !strings.Contains(string(body), `service_name="svc"`) {
+		t.Fatalf("expected service resource in target_info, got:\n%s", body)
+	}
+}
+
+func TestMetricsHandlerNilWithoutPrometheus(t *testing.T) {
+	p, err := Setup(context.Background(), Config{ServiceName: "svc", Exporter: ExporterMemory}, nil)
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	defer p.Shutdown(context.Background())
+	if p.MetricsHandler() != nil {
+		t.Fatalf("expected no metrics handler")
+	}
+}
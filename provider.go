@@ -2,24 +2,53 @@ package otelx
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
-	"time"
 
 	logx "github.com/bionicotaku/lingo-utils-logx"
+	awsxray "go.opentelemetry.io/contrib/propagators/aws/xray"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/log/global"
 	"go.opentelemetry.io/otel/propagation"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
 	"go.opentelemetry.io/otel/sdk/resource"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
 )
 
 // Provider bundles the TracerProvider, Propagator and shutdown hook created by Setup.
 type Provider struct {
-	TP         *sdktrace.TracerProvider
-	Propagator propagation.TextMapPropagator
-	shutdown   func(context.Context) error
+	TP *sdktrace.TracerProvider
+	// MP is set when Config.Metrics or WithMetricReader is used and shuts down with TP.
+	MP *sdkmetric.MeterProvider
+	// LP is set when Config.Logs or WithLogProcessor is used and shuts down with TP.
+	LP          *sdklog.LoggerProvider
+	Propagator  propagation.TextMapPropagator
+	shutdown    func(context.Context) error
+	dryRuns     []*dryRunExporter
+	exportStats *exportCounter
+	memories    []*tracetest.InMemoryExporter
+	canary      *canaryMonitor
+	prometheus  *prometheusEndpoint
+	shadow      *shadowSampler
+	desc        *describer
+	logger      logx.Logger
+
+	// Runtime-adjustable pieces, see ApplyRemoteConfig.
+	sampler *dynamicSampler
+	// ratioLocked is returned by ratio changes when WithSampler or RemoteSampling owns sampling.
+	ratioLocked error
+	attrFilter  *attributeFilter
+	pipelines   []*swappableExporter
+	serviceName string
+	environment string
 }
 
 // Shutdown flushes remaining spans and releases exporter resources.
@@ -30,12 +59,76 @@ func (p *Provider) Shutdown(ctx context.Context) error {
 	return p.shutdown(ctx)
 }
 
+// DryRunStats reports the spans and bytes a Config.DryRun pipeline discarded so far, summed over
+// all configured exporters. It returns zero stats when dry-run mode is off.
+func (p *Provider) DryRunStats() DryRunStats {
+	var total DryRunStats
+	if p == nil {
+		return total
+	}
+	for _, d := range p.dryRuns {
+		stats := d.stats()
+		total.Batches += stats.Batches
+		total.Spans += stats.Spans
+		total.Bytes += stats.Bytes
+	}
+	return total
+}
+
+// ExportStats reports the spans queued, exported and failed by the span batchers when
+// WithExportStats is used; it returns zero stats otherwise.
+func (p *Provider) ExportStats() ExportStats {
+	if p == nil || p.exportStats == nil {
+		return ExportStats{}
+	}
+	return p.exportStats.stats()
+}
+
+// RecordedSpans flushes pending spans and returns everything exported to ExporterMemory pipelines,
+// letting tests assert on emitted spans without registering their own span processors.
+func (p *Provider) RecordedSpans() tracetest.SpanStubs {
+	if p == nil || len(p.memories) == 0 {
+		return nil
+	}
+	_ = p.TP.ForceFlush(context.Background())
+	var spans tracetest.SpanStubs
+	for _, mem := range p.memories {
+		spans = append(spans, mem.GetSpans()...)
+	}
+	return spans
+}
+
+// ResetRecordedSpans clears the spans held by ExporterMemory pipelines.
+func (p *Provider) ResetRecordedSpans() {
+	if p == nil {
+		return
+	}
+	for _, mem := range p.memories {
+		mem.Reset()
+	}
+}
+
 // Setup initialises OpenTelemetry tracing according to Config.
 func Setup(ctx context.Context, cfg Config, logger logx.Logger, opts ...Option) (*Provider, error) {
 	cfg = cfg.sanitize()
+	if cfg.Enabled != nil && !*cfg.Enabled {
+		return setupDisabled(ctx, cfg, logger, opts...), nil
+	}
+	cfg, otlpEnvErr := cfg.applyOTLPEnv(os.Getenv)
+	if otlpEnvErr != nil && logger != nil {
+		logger.Warn(ctx, "otelx.exporter.env.invalid", logx.String("error", otlpEnvErr.Error()))
+	}
+	cfg, resourceEnvErr := cfg.applyResourceEnv(os.Getenv)
+	if resourceEnvErr != nil && logger != nil {
+		logger.Warn(ctx, "otelx.resource.env.invalid", logx.String("error", resourceEnvErr.Error()))
+	}
 	if err := cfg.validate(); err != nil {
 		return nil, err
 	}
+	cfg, err := cfg.resolveHeaderRefs(os.LookupEnv)
+	if err != nil {
+		return nil, err
+	}
 
 	options := &setupOptions{}
 	for _, opt := range opts {
@@ -44,18 +137,144 @@ func Setup(ctx context.Context, cfg Config, logger logx.Logger, opts ...Option)
 		}
 	}
 
-	exporter, err := buildExporter(ctx, cfg, logger)
+	if len(cfg.SpanNameRules) > 0 {
+		options.spanNames = append(spanNameRuleNormalizers(cfg.SpanNameRules), options.spanNames...)
+	}
+	if cfg.Truncation != nil && options.attrMaxBytes == 0 {
+		options.attrMaxBytes, options.truncMarker = cfg.Truncation.MaxBytes, cfg.Truncation.Marker
+	}
+	if len(cfg.RedactionRules) > 0 || cfg.ScrubPII {
+		options.redaction = newRedactor(cfg.RedactionRules, cfg.ScrubPII)
+	}
+
+	var ignoreParent bool
+	if options.sampler == nil {
+		var envErr error
+		if cfg, ignoreParent, envErr = cfg.applySamplerEnv(os.Getenv); envErr != nil && logger != nil {
+			logger.Warn(ctx, "otelx.sampler.env.invalid", logx.String("error", envErr.Error()))
+		}
+	}
+
+	for i, schema := range options.attrSchemas {
+		if err := schema.validate(); err != nil {
+			return nil, fmt.Errorf("otelx: WithAttributeSchemas[%d]: %w", i, err)
+		}
+	}
+	stageOrder, err := resolveStageOrder(options.stageOrder)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("otelx: WithStageOrder: %w", err)
+	}
+
+	if cfg.SDKLogLevel != "" && logger != nil {
+		otel.SetLogger(newSDKLogger(logger, cfg.SDKLogLevel))
 	}
+	if options.sdkErrors && logger != nil {
+		otel.SetErrorHandler(newLogxErrorHandler(logger))
+	}
+
+	// X-Ray only accepts trace ids that start with a recent Unix time, and AWS load balancers and
+	// API Gateway propagate X-Amzn-Trace-Id rather than traceparent.
+	xrayEnabled := len(options.spanExporters) == 0 && cfg.usesExporter(ExporterXRay)
 
-	sampler := DefaultSamplingRatio
+	prop := options.propagator
+	if prop == nil {
+		prop = defaultPropagator(cfg, xrayEnabled, logger)
+	}
+
+	if options.tracerProvider != nil {
+		return wrapTracerProvider(options.tracerProvider, prop, cfg, options, logger), nil
+	}
+
+	var (
+		exporters []sdktrace.SpanExporter
+		dryRuns   []*dryRunExporter
+		memories  []*tracetest.InMemoryExporter
+	)
+	if len(options.spanExporters) > 0 {
+		exporters = append(exporters, options.spanExporters...)
+		if logger != nil {
+			logger.Debug(ctx, "otelx.exporter.custom.enabled", logx.Int("count", len(exporters)))
+		}
+	} else {
+		if options.dialTimeout > 0 && !cfg.DryRun {
+			if err := checkConnectivity(ctx, cfg, options, logger); err != nil {
+				return nil, err
+			}
+		}
+		exporters, dryRuns, memories, err = buildExporters(ctx, cfg, logger)
+		if err != nil {
+			return nil, err
+		}
+	}
+	// cleanups release what Setup built so far, in reverse order, when a later step fails.
+	var cleanups []func()
+	built := false
+	defer func() {
+		if built {
+			return
+		}
+		for i := len(cleanups) - 1; i >= 0; i-- {
+			cleanups[i]()
+		}
+	}()
+	cleanups = append(cleanups, func() { shutdownExporters(ctx, exporters) })
+	if cfg.DebugTee {
+		tee, err := newDebugTeeExporter()
+		if err != nil {
+			return nil, err
+		}
+		// Appended after the configured pipelines, so it is never replaced by ApplyRemoteConfig.
+		exporters = append(exporters, tee)
+		if logger != nil {
+			logger.Info(ctx, "otelx.exporter.debugtee.enabled")
+		}
+	}
+	// Pipelines built from Config can be replaced later by ApplyRemoteConfig.
+	var pipelines []*swappableExporter
+	if len(options.spanExporters) == 0 && !cfg.DryRun {
+		for i, ec := range cfg.exporterConfigs() {
+			pipeline := &swappableExporter{current: exporters[i], cfg: ec}
+			pipelines = append(pipelines, pipeline)
+			exporters[i] = pipeline
+		}
+	}
+	// Only network pipelines are buffered; local ones (stdout, file, memory, DebugTee) cannot be
+	// unreachable.
+	if cfg.DiskBuffer != nil && len(options.spanExporters) == 0 && !cfg.DryRun {
+		for i, ec := range cfg.exporterConfigs() {
+			if !ec.remote() {
+				continue
+			}
+			dir := cfg.DiskBuffer.Dir
+			if len(pipelines) > 1 {
+				dir = filepath.Join(dir, strconv.Itoa(i))
+			}
+			buffer, err := newDiskBufferExporter(exporters[i], dir, *cfg.DiskBuffer, logger)
+			if err != nil {
+				return nil, fmt.Errorf("otelx: diskBuffer: %w", err)
+			}
+			exporters[i] = buffer
+		}
+	}
+	attrFilter := &attributeFilter{}
+	canary := newCanaryMonitor(len(exporters))
+	for i, exporter := range exporters {
+		// Each pipeline gets its own transforms so stateful ones (event budget, cardinality guard)
+		// see every span once.
+		exporters[i] = &canaryExporter{
+			SpanExporter: newTransformExporter(exporter, options.spanTransforms(stageOrder, attrFilter, logger)...),
+			monitor:      canary,
+		}
+	}
+
+	ratio := DefaultSamplingRatio
 	if cfg.SamplingRatio != nil {
-		sampler = *cfg.SamplingRatio
+		ratio = *cfg.SamplingRatio
 	}
 	if options.samplerHook != nil {
-		options.samplerHook(sampler)
+		options.samplerHook(ratio)
 	}
+	sampler := newDynamicSampler(ratio)
 
 	resourceOpts := []resource.Option{
 		resource.WithSchemaURL(semconv.SchemaURL),
@@ -89,30 +308,362 @@ func Setup(ctx context.Context, cfg Config, logger logx.Logger, opts ...Option)
 		return nil, fmt.Errorf("otelx: build resource: %w", err)
 	}
 
-	tp := sdktrace.NewTracerProvider(
-		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(sampler))),
-		sdktrace.WithResource(res),
-		sdktrace.WithBatcher(exporter,
-			sdktrace.WithBatchTimeout(5*time.Second),
-			sdktrace.WithMaxExportBatchSize(512),
-		),
+	var prom *prometheusEndpoint
+	if options.prometheus {
+		if prom, err = newPrometheusEndpoint(); err != nil {
+			return nil, err
+		}
+		options.metricReaders = append(options.metricReaders, prom.reader)
+	}
+	var mp *sdkmetric.MeterProvider
+	if cfg.Metrics || len(options.metricReaders) > 0 {
+		if mp, err = buildMeterProvider(ctx, cfg, res, options, logger); err != nil {
+			return nil, err
+		}
+		cleanups = append(cleanups, func() { _ = mp.Shutdown(ctx) })
+	}
+	if err := startMetricInstrumentation(ctx, mp, options, logger); err != nil {
+		return nil, err
+	}
+
+	var (
+		headSampler = newRuleSampler(cfg.SamplingRules, sampler)
+		remote      *remoteSampler
+		ratioLocked error
 	)
+	parentBased := func(s sdktrace.Sampler) sdktrace.Sampler {
+		if cfg.ControlPlane != nil {
+			s = ControlPlaneSampler(*cfg.ControlPlane, s)
+		}
+		if ignoreParent {
+			return s
+		}
+		return sdktrace.ParentBased(s)
+	}
+	headSampler = parentBased(headSampler)
+	switch {
+	case options.sampler != nil:
+		headSampler = options.sampler
+		ratioLocked = errCustomSampler
+		if logger != nil {
+			logger.Debug(ctx, "otelx.sampler.custom", logx.String("sampler", headSampler.Description()))
+			if cfg.SamplingRatio != nil {
+				logger.Warn(ctx, "otelx.sampler.ratio.ignored", logx.Float64("samplingRatio", *cfg.SamplingRatio))
+			}
+			if len(cfg.SamplingRules) > 0 {
+				logger.Warn(ctx, "otelx.sampler.rules.ignored", logx.Int("samplingRules", len(cfg.SamplingRules)))
+			}
+			if cfg.RemoteSampling != nil {
+				logger.Warn(ctx, "otelx.sampler.remote.ignored", logx.String("endpoint", cfg.RemoteSampling.Endpoint))
+			}
+			if cfg.ControlPlane != nil {
+				logger.Warn(ctx, "otelx.sampler.controlPlane.ignored")
+			}
+		}
+	case cfg.RemoteSampling != nil:
+		remote = newRemoteSampler(*cfg.RemoteSampling, cfg.ServiceName, newRuleSampler(cfg.SamplingRules, sampler), logger)
+		headSampler = parentBased(remote)
+		ratioLocked = errRemoteSampling
+		cleanups = append(cleanups, remote.close)
+		if logger != nil {
+			logger.Info(ctx, "otelx.sampler.remote", logx.String("endpoint", cfg.RemoteSampling.Endpoint))
+		}
+	}
+	var adaptive *adaptiveController
+	if cfg.SamplingTargetPerMinute > 0 {
+		if options.sampler != nil {
+			if logger != nil {
+				logger.Warn(ctx, "otelx.sampler.target.ignored", logx.Float64("samplingTargetPerMinute", cfg.SamplingTargetPerMinute))
+			}
+		} else {
+			if adaptive, err = newAdaptiveController(sampler, cfg.SamplingTargetPerMinute, mp, options.ratioObserver, logger); err != nil {
+				return nil, err
+			}
+			ratioLocked = errAdaptiveSampling
+		}
+	}
+	var (
+		rootSampler sdktrace.Sampler = canarySampler{forceSampler{headSampler}}
+		shadow      *shadowSampler
+	)
+	if options.shadow != nil {
+		shadow = newShadowSampler(rootSampler, options.shadow)
+		rootSampler = shadow
+	}
+	var spanMetrics *spanMetricsProcessor
+	if options.spanMetrics {
+		if spanMetrics, err = newSpanMetricsProcessor(ctx, mp, logger); err != nil {
+			return nil, err
+		}
+	}
+	if spanMetrics != nil || options.sampleErrors {
+		rootSampler = recordDroppedSampler{rootSampler}
+	}
+	var budget *spanBudget
+	if cfg.MaxSpansPerRequest > 0 {
+		budget = newSpanBudget(cfg.MaxSpansPerRequest)
+		rootSampler = budget.sampler(rootSampler)
+	}
+	tpOpts := []sdktrace.TracerProviderOption{
+		sdktrace.WithSampler(rootSampler),
+		sdktrace.WithResource(res),
+	}
+	if cfg.SpanLimits != nil {
+		tpOpts = append(tpOpts, sdktrace.WithRawSpanLimits(cfg.SpanLimits.spanLimits()))
+	}
+	if xrayEnabled {
+		tpOpts = append(tpOpts, sdktrace.WithIDGenerator(awsxray.NewIDGenerator()))
+	}
+	if len(options.attrExtractors) > 0 {
+		tpOpts = append(tpOpts, sdktrace.WithSpanProcessor(newContextAttributeProcessor(options.attrExtractors)))
+	}
+	if len(options.spanNames) > 0 {
+		tpOpts = append(tpOpts, sdktrace.WithSpanProcessor(newSpanNameProcessor(options.spanNames)))
+	}
+	if len(cfg.SpanKindAttributes) > 0 {
+		defaults, _ := parseSpanKindAttributes(cfg.SpanKindAttributes)
+		tpOpts = append(tpOpts, sdktrace.WithSpanProcessor(newSpanKindAttributeProcessor(defaults)))
+	}
+	if schemas := options.schemaProcessor(cfg, logger); schemas != nil {
+		tpOpts = append(tpOpts, sdktrace.WithSpanProcessor(schemas))
+	}
+	if spanMetrics != nil {
+		tpOpts = append(tpOpts, sdktrace.WithSpanProcessor(spanMetrics))
+	}
+	if adaptive != nil {
+		tpOpts = append(tpOpts, sdktrace.WithSpanProcessor(adaptive))
+	}
+	for _, processor := range options.spanProcessors {
+		tpOpts = append(tpOpts, sdktrace.WithSpanProcessor(processor))
+	}
+	var batchOpts []sdktrace.BatchSpanProcessorOption
+	if cfg.Batch != nil {
+		batchOpts = cfg.Batch.batchOptions()
+	} else {
+		batchOpts = BatchConfig{}.batchOptions()
+	}
+	if cfg.DeferredExport != nil {
+		batchOpts = cfg.DeferredExport.batchOptions()
+	}
+	var exportStats *exportCounter
+	if options.exportStats {
+		exportStats = newExportCounter()
+		if mp != nil {
+			if err := exportStats.registerMetrics(mp); err != nil {
+				return nil, fmt.Errorf("otelx: export stats metrics: %w", err)
+			}
+		}
+	}
+	batchers := make([]sdktrace.SpanProcessor, 0, len(exporters))
+	for _, exporter := range exporters {
+		var batcher sdktrace.SpanProcessor
+		if exportStats != nil {
+			exporter = exportStats.exporter(exporter)
+		}
+		if options.syncExport {
+			batcher = sdktrace.NewSimpleSpanProcessor(exporter)
+		} else {
+			batcher = sdktrace.NewBatchSpanProcessor(exporter, batchOpts...)
+		}
+		if exportStats != nil {
+			batcher = exportStats.processor(batcher)
+		}
+		batchers = append(batchers, batcher)
+	}
+	if options.syncExport && logger != nil && (cfg.Batch != nil || cfg.DeferredExport != nil) {
+		logger.Warn(ctx, "otelx.export.batch.ignored")
+	}
+	exportProcessors := batchers
+	if cfg.TailSampling != nil {
+		exportProcessors = []sdktrace.SpanProcessor{newTailSamplingProcessor(*cfg.TailSampling, exportProcessors)}
+	}
+	if options.sampleErrors {
+		exportProcessors = []sdktrace.SpanProcessor{newErrorSamplingProcessor(exportProcessors)}
+	}
+	if len(cfg.DropSpans) > 0 {
+		exportProcessors = []sdktrace.SpanProcessor{newSpanDropProcessor(cfg.DropSpans, exportProcessors)}
+	}
+	if budget != nil {
+		exportProcessors = []sdktrace.SpanProcessor{budget.processor(exportProcessors)}
+	}
+	if options.clock != nil {
+		exportProcessors = []sdktrace.SpanProcessor{newClockProcessor(options.clock, exportProcessors)}
+	}
+	var dynamicRes *dynamicResource
+	if options.refreshEvery > 0 {
+		dynamicRes = newDynamicResource(res, func(ctx context.Context) (*resource.Resource, error) {
+			return resource.New(ctx, resourceOpts...)
+		}, logger)
+		exportProcessors = []sdktrace.SpanProcessor{newResourceProcessor(dynamicRes, exportProcessors)}
+	}
+	for _, processor := range exportProcessors {
+		tpOpts = append(tpOpts, sdktrace.WithSpanProcessor(processor))
+	}
 
-	prop := options.propagator
-	if prop == nil {
-		prop = propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+	var lp *sdklog.LoggerProvider
+	if cfg.Logs || len(options.logProcessors) > 0 {
+		if lp, err = buildLoggerProvider(ctx, cfg, res, options, logger); err != nil {
+			return nil, err
+		}
+	}
+
+	desc := &describer{
+		static: Description{
+			Enabled:     true,
+			ServiceName: cfg.ServiceName,
+			Environment: cfg.Environment,
+			Exporters:   describeExporters(cfg, options),
+			Processors:  options.describeProcessors(cfg, stageOrder, spanMetrics != nil, adaptive != nil),
+			Metrics:     mp != nil,
+			Logs:        lp != nil,
+		},
+		sampler:  rootSampler,
+		resource: func() *resource.Resource { return res },
+	}
+	if dynamicRes != nil {
+		desc.resource = func() *resource.Resource { return dynamicRes.current.Load() }
+	}
+
+	tp := sdktrace.NewTracerProvider(tpOpts...)
+	if dynamicRes != nil {
+		// Started last so a failed Setup leaves no goroutine behind; Shutdown stops it.
+		dynamicRes.start(options.refreshEvery)
+	}
+	if adaptive != nil {
+		adaptive.start(adaptiveInterval)
 	}
 
 	if options.global {
 		otel.SetTracerProvider(tp)
 		otel.SetTextMapPropagator(prop)
+		if mp != nil {
+			otel.SetMeterProvider(mp)
+		}
+		if lp != nil {
+			global.SetLoggerProvider(lp)
+		}
 	}
 
+	statsLogged := exportStats != nil && options.exportStatsEvery > 0 && logger != nil
+	if statsLogged {
+		exportStats.start(options.exportStatsEvery, logger)
+	}
+	built = true
 	return &Provider{
 		TP:         tp,
+		MP:         mp,
+		LP:         lp,
 		Propagator: prop,
 		shutdown: func(ctx context.Context) error {
-			return tp.Shutdown(ctx)
+			if shadow != nil {
+				shadow.logSummary(ctx, logger)
+			}
+			err := tp.Shutdown(ctx)
+			if exportStats != nil {
+				exportStats.close(ctx, logger, statsLogged)
+			}
+			remote.close()
+			if mp != nil {
+				err = errors.Join(err, mp.Shutdown(ctx))
+			}
+			if lp != nil {
+				err = errors.Join(err, lp.Shutdown(ctx))
+			}
+			return err
 		},
+		dryRuns:     dryRuns,
+		exportStats: exportStats,
+		memories:    memories,
+		canary:      canary,
+		prometheus:  prom,
+		shadow:      shadow,
+		desc:        desc,
+		logger:      logger,
+
+		sampler:     sampler,
+		ratioLocked: ratioLocked,
+		attrFilter:  attrFilter,
+		pipelines:   pipelines,
+		serviceName: cfg.ServiceName,
+		environment: cfg.Environment,
 	}, nil
 }
+
+// setupDisabled returns a Provider whose TracerProvider never samples, so spans cost almost nothing
+// and nothing is exported. Exporter settings are not validated. Trace context still flows through
+// the propagator, keeping traces intact for downstream services that do record them.
+func setupDisabled(ctx context.Context, cfg Config, logger logx.Logger, opts ...Option) *Provider {
+	options := &setupOptions{}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(options)
+		}
+	}
+	prop := options.propagator
+	if prop == nil {
+		prop = defaultPropagator(cfg, len(options.spanExporters) == 0 && cfg.usesExporter(ExporterXRay), logger)
+	}
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSampler(sdktrace.NeverSample()))
+	if options.global {
+		otel.SetTracerProvider(tp)
+		otel.SetTextMapPropagator(prop)
+	}
+	if logger != nil {
+		logger.Info(ctx, "otelx.provider.disabled")
+	}
+	desc := &describer{sampler: sdktrace.NeverSample()}
+	return &Provider{TP: tp, Propagator: prop, desc: desc}
+}
+
+// defaultPropagator propagates W3C trace context and baggage within cfg's baggage limits, plus
+// X-Amzn-Trace-Id when xray is set.
+func defaultPropagator(cfg Config, xray bool, logger logx.Logger) propagation.TextMapPropagator {
+	props := []propagation.TextMapPropagator{
+		propagation.TraceContext{},
+		LimitedBaggage(cfg.BaggageMaxMembers, cfg.BaggageMaxBytes, logger),
+	}
+	if xray {
+		props = append(props, awsxray.Propagator{})
+	}
+	return propagation.NewCompositeTextMapPropagator(props...)
+}
+
+// wrapTracerProvider wires otelx helpers around a TracerProvider built by the caller.
+// The caller keeps ownership of tp, so the returned Provider does not shut it down.
+func wrapTracerProvider(tp *sdktrace.TracerProvider, prop propagation.TextMapPropagator, cfg Config, options *setupOptions, logger logx.Logger) *Provider {
+	if len(options.attrExtractors) > 0 {
+		tp.RegisterSpanProcessor(newContextAttributeProcessor(options.attrExtractors))
+	}
+	if len(options.spanNames) > 0 {
+		tp.RegisterSpanProcessor(newSpanNameProcessor(options.spanNames))
+	}
+	if len(cfg.SpanKindAttributes) > 0 {
+		defaults, _ := parseSpanKindAttributes(cfg.SpanKindAttributes)
+		tp.RegisterSpanProcessor(newSpanKindAttributeProcessor(defaults))
+	}
+	if schemas := options.schemaProcessor(cfg, logger); schemas != nil {
+		tp.RegisterSpanProcessor(schemas)
+	}
+	for _, processor := range options.spanProcessors {
+		tp.RegisterSpanProcessor(processor)
+	}
+	if options.global {
+		otel.SetTracerProvider(tp)
+		otel.SetTextMapPropagator(prop)
+	}
+	if logger != nil {
+		logger.Debug(context.Background(), "otelx.provider.external")
+	}
+	desc := &describer{static: Description{
+		Enabled:     true,
+		ServiceName: cfg.ServiceName,
+		Environment: cfg.Environment,
+		External:    true,
+	}}
+	for _, processor := range options.describeProcessors(cfg, nil, false, false) {
+		if processor.Phase != PhaseExport.String() {
+			desc.static.Processors = append(desc.static.Processors, processor)
+		}
+	}
+	return &Provider{TP: tp, Propagator: prop, desc: desc}
+}
@@ -0,0 +1,118 @@
+package otelx
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	logx "github.com/bionicotaku/lingo-utils-logx"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Quota backoff bounds for quotaGuardExporter.
+const (
+	quotaBackoffMin = 10 * time.Second
+	quotaBackoffMax = 5 * time.Minute
+	quotaMinRatio   = 1.0 / 64
+)
+
+// quotaGuardExporter protects quota-limited APIs such as Cloud Trace. A ResourceExhausted error
+// pauses exports for a growing backoff and halves the share of traces sent afterwards; each
+// backoff period without quota errors doubles the share again until every trace is exported.
+type quotaGuardExporter struct {
+	sdktrace.SpanExporter
+	name   string
+	logger logx.Logger
+	now    func() time.Time
+
+	mu        sync.Mutex
+	ratio     float64
+	backoff   time.Duration
+	pausedTil time.Time
+	nextRaise time.Time
+	dropped   int
+}
+
+func newQuotaGuardExporter(exporter sdktrace.SpanExporter, name string, logger logx.Logger) *quotaGuardExporter {
+	return &quotaGuardExporter{SpanExporter: exporter, name: name, logger: logger, now: time.Now, ratio: 1}
+}
+
+func (e *quotaGuardExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	ratio, ok := e.admit(ctx, len(spans))
+	if !ok {
+		return nil
+	}
+	if filter := traceRatioFilter(ratio); filter != nil {
+		kept := make([]sdktrace.ReadOnlySpan, 0, len(spans))
+		for _, span := range spans {
+			if filter(span) != nil {
+				kept = append(kept, span)
+			}
+		}
+		e.mu.Lock()
+		e.dropped += len(spans) - len(kept)
+		e.mu.Unlock()
+		if len(kept) == 0 {
+			return nil
+		}
+		spans = kept
+	}
+
+	err := e.SpanExporter.ExportSpans(ctx, spans)
+	if status.Code(err) == codes.ResourceExhausted {
+		e.exhausted(ctx)
+	}
+	return err
+}
+
+// admit reports the ratio to export with, or false while exports are paused. It also raises the
+// ratio once a backoff period passed without quota errors.
+func (e *quotaGuardExporter) admit(ctx context.Context, batch int) (float64, bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	now := e.now()
+	if now.Before(e.pausedTil) {
+		e.dropped += batch
+		return 0, false
+	}
+	if e.ratio < 1 && !now.Before(e.nextRaise) {
+		e.ratio *= 2
+		e.nextRaise = now.Add(e.backoff)
+		if e.ratio >= 1 {
+			e.ratio, e.backoff = 1, 0
+			if e.logger != nil {
+				e.logger.Info(ctx, "otelx.exporter.quota.recovered",
+					logx.String("exporter", e.name),
+					logx.Int("dropped", e.dropped),
+				)
+			}
+			e.dropped = 0
+		}
+	}
+	return e.ratio, true
+}
+
+func (e *quotaGuardExporter) exhausted(ctx context.Context) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.backoff == 0 {
+		e.backoff = quotaBackoffMin
+	} else if e.backoff *= 2; e.backoff > quotaBackoffMax {
+		e.backoff = quotaBackoffMax
+	}
+	if e.ratio /= 2; e.ratio < quotaMinRatio {
+		e.ratio = quotaMinRatio
+	}
+	now := e.now()
+	e.pausedTil = now.Add(e.backoff)
+	e.nextRaise = e.pausedTil.Add(e.backoff)
+	if e.logger != nil {
+		e.logger.Warn(ctx, "otelx.exporter.quota.exceeded",
+			logx.String("exporter", e.name),
+			logx.Duration("backoff", e.backoff),
+			logx.Float64("exportRatio", e.ratio),
+		)
+	}
+}
@@ -0,0 +1,122 @@
+package otelx
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// quotaExporter fails with ResourceExhausted while exhausted is set and counts delivered spans.
+type quotaExporter struct {
+	mu        sync.Mutex
+	exhausted bool
+	calls     int
+	delivered int
+}
+
+func (e *quotaExporter) ExportSpans(_ context.Context, spans []sdktrace.ReadOnlySpan) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.calls++
+	if e.exhausted {
+		return status.Error(codes.ResourceExhausted, "quota exceeded")
+	}
+	e.delivered += len(spans)
+	return nil
+}
+
+func (e *quotaExporter) Shutdown(context.Context) error { return nil }
+
+func TestQuotaGuardBacksOffAndRecovers(t *testing.T) {
+	inner := &quotaExporter{exhausted: true}
+	rec := &recordingLogger{}
+	guard := newQuotaGuardExporter(inner, "cloudtrace", rec)
+	now := time.Unix(1000, 0)
+	guard.now = func() time.Time { return now }
+
+	var spans []sdktrace.ReadOnlySpan
+	for i := 0; i < 200; i++ {
+		spans = append(spans, spanWithAttributes(t))
+	}
+	ctx := context.Background()
+
+	if err := guard.ExportSpans(ctx, spans); status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("expected quota error to surface, got %v", err)
+	}
+	if !slices.Contains(rec.Entries(), "warn:otelx.exporter.quota.exceeded") {
+		t.Fatalf("expected quota warning, got %v", rec.Entries())
+	}
+
+	// Paused: the API is not called at all.
+	inner.exhausted = false
+	if err := guard.ExportSpans(ctx, spans); err != nil || inner.calls != 1 {
+		t.Fatalf("expected export to be skipped during backoff, err=%v calls=%d", err, inner.calls)
+	}
+
+	// After the pause only about half of the traces are sent.
+	now = now.Add(quotaBackoffMin)
+	if err := guard.ExportSpans(ctx, spans); err != nil {
+		t.Fatalf("export failed: %v", err)
+	}
+	if inner.delivered == 0 || inner.delivered >= len(spans) {
+		t.Fatalf("expected a downsampled batch, delivered %d of %d", inner.delivered, len(spans))
+	}
+
+	// A full backoff period without errors restores full export.
+	now = now.Add(quotaBackoffMin)
+	inner.delivered = 0
+	if err := guard.ExportSpans(ctx, spans); err != nil {
+		t.Fatalf("export failed: %v", err)
+	}
+	if inner.delivered != len(spans) {
+		t.Fatalf("expected full export after recovery, delivered %d of %d", inner.delivered, len(spans))
+	}
+	if !slices.Contains(rec.Entries(), "info:otelx.exporter.quota.recovered") {
+		t.Fatalf("expected recovery log, got %v", rec.Entries())
+	}
+}
+
+func TestQuotaGuardIgnoresOtherErrors(t *testing.T) {
+	guard := newQuotaGuardExporter(failingExporter{}, "cloudtrace", nil)
+	span := spanWithAttributes(t)
+	for i := 0; i < 2; i++ {
+		if err := guard.ExportSpans(context.Background(), []sdktrace.ReadOnlySpan{span}); err == nil {
+			t.Fatalf("expected exporter error")
+		}
+	}
+	if guard.ratio != 1 || !guard.pausedTil.IsZero() {
+		t.Fatalf("expected no backoff for non-quota errors")
+	}
+}
+
+func TestQuotaGuardDownsamplesHeadSampledTraces(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSampler(sdktrace.TraceIDRatioBased(0.1)), sdktrace.WithSpanProcessor(recorder))
+	for i := 0; i < 4000; i++ {
+		_, span := tp.Tracer("test").Start(context.Background(), "op")
+		span.End()
+	}
+	spans := recorder.Ended()
+
+	inner := &quotaExporter{exhausted: true}
+	guard := newQuotaGuardExporter(inner, "cloudtrace", nil)
+	now := time.Unix(1000, 0)
+	guard.now = func() time.Time { return now }
+	_ = guard.ExportSpans(context.Background(), spans)
+
+	inner.exhausted = false
+	now = now.Add(quotaBackoffMin)
+	if err := guard.ExportSpans(context.Background(), spans); err != nil {
+		t.Fatalf("export failed: %v", err)
+	}
+	if share := float64(inner.delivered) / float64(len(spans)); share < 0.35 || share > 0.65 {
+		t.Fatalf("expected about half of %d head-sampled spans after one backoff, delivered %.2f", len(spans), share)
+	}
+}
@@ -0,0 +1,123 @@
+package otelx
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Attributes and event recorded by RecordRateLimit.
+const (
+	RateLimitAllowedKey    = attribute.Key("ratelimit.allowed")
+	RateLimitPolicyKey     = attribute.Key("ratelimit.policy")
+	RateLimitLimitKey      = attribute.Key("ratelimit.limit")
+	RateLimitRemainingKey  = attribute.Key("ratelimit.remaining")
+	RateLimitRetryAfterKey = attribute.Key("ratelimit.retry_after_ms")
+
+	RateLimitThrottledEvent = "ratelimit.throttled"
+)
+
+// RateLimitDecision is the outcome of a rate limiter check for one request.
+type RateLimitDecision struct {
+	Allowed bool
+	// Policy names the limit that applied, e.g. "per-user" or "tenant:acme"; optional.
+	Policy string
+	// Limit and Remaining describe the quota window; they are recorded only when Limit > 0.
+	Limit     int64
+	Remaining int64
+	// RetryAfter tells throttled callers when to retry; optional.
+	RetryAfter time.Duration
+}
+
+func (d RateLimitDecision) attributes() []attribute.KeyValue {
+	attrs := []attribute.KeyValue{RateLimitAllowedKey.Bool(d.Allowed)}
+	if d.Policy != "" {
+		attrs = append(attrs, RateLimitPolicyKey.String(d.Policy))
+	}
+	if d.Limit > 0 {
+		attrs = append(attrs, RateLimitLimitKey.Int64(d.Limit), RateLimitRemainingKey.Int64(d.Remaining))
+	}
+	if d.RetryAfter > 0 {
+		attrs = append(attrs, RateLimitRetryAfterKey.Int64(d.RetryAfter.Milliseconds()))
+	}
+	return attrs
+}
+
+// RecordRateLimit records d on the span in ctx: the decision as attributes and, when the request
+// is throttled, a RateLimitThrottledEvent, so throttling shows up in traces instead of as
+// unexplained 429s. Call it from any limiter; HTTPRateLimit and the gRPC interceptors do.
+func RecordRateLimit(ctx context.Context, d RateLimitDecision) {
+	span := trace.SpanFromContext(ctx)
+	if !span.IsRecording() {
+		return
+	}
+	attrs := d.attributes()
+	span.SetAttributes(attrs...)
+	if !d.Allowed {
+		span.AddEvent(RateLimitThrottledEvent, trace.WithAttributes(attrs[1:]...))
+	}
+}
+
+// HTTPRateLimit asks limit for a decision on every request and records it on the server span.
+// Throttled requests get 429 Too Many Requests with Retry-After; allowed ones reach next. When the
+// decision carries a quota, RateLimit-Limit and RateLimit-Remaining headers are set either way.
+// Install it inside HTTPHandler.
+func HTTPRateLimit(limit func(*http.Request) RateLimitDecision, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		d := limit(r)
+		RecordRateLimit(r.Context(), d)
+		if d.Limit > 0 {
+			w.Header().Set("RateLimit-Limit", strconv.FormatInt(d.Limit, 10))
+			w.Header().Set("RateLimit-Remaining", strconv.FormatInt(d.Remaining, 10))
+		}
+		if !d.Allowed {
+			if d.RetryAfter > 0 {
+				w.Header().Set("Retry-After", strconv.FormatInt(int64(math.Ceil(d.RetryAfter.Seconds())), 10))
+			}
+			http.Error(w, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// GRPCRateLimitUnaryInterceptor asks limit for a decision on every call and records it on the
+// server span; throttled calls fail with codes.ResourceExhausted.
+func GRPCRateLimitUnaryInterceptor(limit func(ctx context.Context, fullMethod string) RateLimitDecision) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if err := checkRateLimit(ctx, limit(ctx, info.FullMethod)); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// GRPCRateLimitStreamInterceptor is the streaming counterpart of GRPCRateLimitUnaryInterceptor.
+func GRPCRateLimitStreamInterceptor(limit func(ctx context.Context, fullMethod string) RateLimitDecision) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := checkRateLimit(ss.Context(), limit(ss.Context(), info.FullMethod)); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+func checkRateLimit(ctx context.Context, d RateLimitDecision) error {
+	RecordRateLimit(ctx, d)
+	if d.Allowed {
+		return nil
+	}
+	msg := "rate limit exceeded"
+	if d.Policy != "" {
+		msg += ": " + d.Policy
+	}
+	return status.Error(codes.ResourceExhausted, msg)
+}
@@ -0,0 +1,97 @@
+package otelx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestHTTPRateLimitRecordsThrottling(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	defer tp.Shutdown(context.Background())
+
+	remaining := int64(1)
+	limit := func(*http.Request) RateLimitDecision {
+		d := RateLimitDecision{Allowed: remaining > 0, Policy: "per-user", Limit: 1, RetryAfter: 1500 * time.Millisecond}
+		if remaining > 0 {
+			remaining--
+		}
+		d.Remaining = remaining
+		return d
+	}
+	called := 0
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { called++ })
+	handler := HTTPHandler("op", HTTPRateLimit(limit, ok), otelhttp.WithTracerProvider(tp))
+
+	for range 2 {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://localhost/", nil))
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "http://localhost/", nil))
+
+	if called != 1 {
+		t.Fatalf("expected only the first request to reach the handler, got %d", called)
+	}
+	if rec.Code != http.StatusTooManyRequests || rec.Header().Get("Retry-After") != "2" || rec.Header().Get("RateLimit-Remaining") != "0" {
+		t.Fatalf("unexpected throttled response: %d %v", rec.Code, rec.Header())
+	}
+
+	ended := recorder.Ended()
+	if len(ended) != 3 {
+		t.Fatalf("expected 3 spans, got %d", len(ended))
+	}
+	allowed := attribute.NewSet(ended[0].Attributes()...)
+	if v, _ := allowed.Value(RateLimitAllowedKey); !v.AsBool() || len(ended[0].Events()) != 0 {
+		t.Fatalf("expected the first request to be recorded as allowed")
+	}
+	throttled := attribute.NewSet(ended[1].Attributes()...)
+	if v, _ := throttled.Value(RateLimitAllowedKey); v.AsBool() {
+		t.Fatalf("expected the second request to be recorded as throttled")
+	}
+	if v, _ := throttled.Value(RateLimitRetryAfterKey); v.AsInt64() != 1500 {
+		t.Fatalf("expected retry_after_ms=1500, got %v", v.AsInt64())
+	}
+	if !spanHasAttribute(ended[1].Attributes(), RateLimitPolicyKey, "per-user") {
+		t.Fatalf("expected the policy on the throttled span")
+	}
+	if ev := ended[1].Events(); len(ev) != 1 || ev[0].Name != RateLimitThrottledEvent {
+		t.Fatalf("expected a %s event, got %v", RateLimitThrottledEvent, ev)
+	}
+}
+
+func TestGRPCRateLimitUnaryInterceptor(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	defer tp.Shutdown(context.Background())
+
+	var method string
+	interceptor := GRPCRateLimitUnaryInterceptor(func(_ context.Context, fullMethod string) RateLimitDecision {
+		method = fullMethod
+		return RateLimitDecision{Policy: "tenant:acme"}
+	})
+	ctx, span := tp.Tracer("test").Start(context.Background(), "server")
+	_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/svc.v1.Svc/Get"}, func(context.Context, any) (any, error) {
+		t.Fatalf("throttled call reached the handler")
+		return nil, nil
+	})
+	span.End()
+
+	if status.Code(err) != codes.ResourceExhausted || method != "/svc.v1.Svc/Get" {
+		t.Fatalf("expected ResourceExhausted for /svc.v1.Svc/Get, got %v (%s)", err, method)
+	}
+	ended := recorder.Ended()
+	if len(ended) != 1 || !spanHasAttribute(ended[0].Attributes(), RateLimitPolicyKey, "tenant:acme") || len(ended[0].Events()) != 1 {
+		t.Fatalf("expected the throttling decision on the server span")
+	}
+}
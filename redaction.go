@@ -0,0 +1,203 @@
+package otelx
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// RedactionAction says what a RedactionRule does with a matching value.
+type RedactionAction string
+
+const (
+	// RedactionReplace replaces every match with the rule's Replacement (default DefaultRedactionReplacement).
+	RedactionReplace RedactionAction = "replace"
+	// RedactionHash replaces every match with "sha256:" and the first 16 hex digits of its hash, so
+	// equal values stay correlatable. Low-entropy values such as emails can be guessed back.
+	RedactionHash RedactionAction = "hash"
+)
+
+// DefaultRedactionReplacement is written over matches of RedactionReplace rules without Replacement.
+const DefaultRedactionReplacement = "[REDACTED]"
+
+// builtinRedactions are the patterns RedactionRule.Builtin can name.
+var builtinRedactions = map[string]string{
+	"email":       `[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`,
+	"cardNumber":  `\b(?:\d[ -]?){12,18}\d\b`,
+	"bearerToken": `(?i)bearer\s+[A-Za-z0-9\-._~+/]+=*`,
+	"jwt":         `eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+`,
+}
+
+// RedactionRule rewrites the parts of string attribute values, on spans and span events, that
+// match Pattern (or the Builtin pattern) before export.
+type RedactionRule struct {
+	// Pattern is a regular expression, e.g. `\b\d{3}-\d{2}-\d{4}\b`.
+	Pattern string `json:"pattern"`
+	// Builtin names a predefined pattern instead: email, cardNumber, bearerToken or jwt.
+	Builtin string `json:"builtin"`
+	// Keys limits the rule to these attribute keys; empty applies it to every key.
+	Keys []string `json:"keys"`
+	// Action is RedactionReplace (default) or RedactionHash.
+	Action      RedactionAction `json:"action"`
+	Replacement string          `json:"replacement"`
+}
+
+func (r RedactionRule) validate() error {
+	if (r.Pattern == "") == (r.Builtin == "") {
+		return errors.New("exactly one of pattern and builtin is required")
+	}
+	if r.Builtin != "" {
+		if _, ok := builtinRedactions[r.Builtin]; !ok {
+			return fmt.Errorf("unknown builtin %q", r.Builtin)
+		}
+	}
+	if r.Pattern != "" {
+		if _, err := regexp.Compile(r.Pattern); err != nil {
+			return fmt.Errorf("pattern: %w", err)
+		}
+	}
+	switch r.Action {
+	case "", RedactionReplace, RedactionHash:
+	default:
+		return fmt.Errorf("unsupported action %q", r.Action)
+	}
+	return nil
+}
+
+type compiledRedaction struct {
+	pattern *regexp.Regexp
+	keys    map[attribute.Key]bool
+	replace func(string) string
+}
+
+// redactor applies Config.ScrubPII and then validated Config.RedactionRules in order.
+type redactor struct {
+	rules    []compiledRedaction
+	scrubPII bool
+}
+
+func newRedactor(rules []RedactionRule, scrubPII bool) *redactor {
+	r := &redactor{rules: make([]compiledRedaction, len(rules)), scrubPII: scrubPII}
+	for i, rule := range rules {
+		pattern := rule.Pattern
+		if rule.Builtin != "" {
+			pattern = builtinRedactions[rule.Builtin]
+		}
+		c := compiledRedaction{pattern: regexp.MustCompile(pattern)}
+		if len(rule.Keys) > 0 {
+			c.keys = make(map[attribute.Key]bool, len(rule.Keys))
+			for _, k := range rule.Keys {
+				c.keys[attribute.Key(strings.TrimSpace(k))] = true
+			}
+		}
+		if rule.Action == RedactionHash {
+			c.replace = hashRedaction
+		} else {
+			replacement := rule.Replacement
+			if replacement == "" {
+				replacement = DefaultRedactionReplacement
+			}
+			c.replace = func(string) string { return replacement }
+		}
+		r.rules[i] = c
+	}
+	return r
+}
+
+func hashRedaction(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return "sha256:" + hex.EncodeToString(sum[:8])
+}
+
+// redact rewrites matching values in the span's and its events' attributes.
+func (r *redactor) redact(span sdktrace.ReadOnlySpan) sdktrace.ReadOnlySpan {
+	attrs, changed := r.redactAttributes(span.Attributes())
+	events := span.Events()
+	var redactedEvents []sdktrace.Event
+	for i, event := range events {
+		eventAttrs, eventChanged := r.redactAttributes(event.Attributes)
+		if !eventChanged {
+			continue
+		}
+		if redactedEvents == nil {
+			redactedEvents = append([]sdktrace.Event(nil), events...)
+		}
+		redactedEvents[i].Attributes = eventAttrs
+	}
+	if !changed && redactedEvents == nil {
+		return span
+	}
+	o := override(span)
+	if changed {
+		o.setAttributes(attrs...)
+	}
+	if redactedEvents != nil {
+		o.events = redactedEvents
+	}
+	return o
+}
+
+// redactAttributes returns attrs with matches rewritten; the input slice is never modified.
+func (r *redactor) redactAttributes(attrs []attribute.KeyValue) ([]attribute.KeyValue, bool) {
+	var out []attribute.KeyValue
+	for i, kv := range attrs {
+		value, changed := r.redactValue(kv.Key, kv.Value)
+		if !changed {
+			continue
+		}
+		if out == nil {
+			out = append([]attribute.KeyValue(nil), attrs...)
+		}
+		out[i] = attribute.KeyValue{Key: kv.Key, Value: value}
+	}
+	if out == nil {
+		return attrs, false
+	}
+	return out, true
+}
+
+func (r *redactor) redactValue(key attribute.Key, v attribute.Value) (attribute.Value, bool) {
+	scrubbed := false
+	if r.scrubPII {
+		v, scrubbed = scrubPII(key, v)
+	}
+	switch v.Type() {
+	case attribute.STRING:
+		if s, changed := r.redactString(key, v.AsString()); changed {
+			return attribute.StringValue(s), true
+		}
+	case attribute.STRINGSLICE:
+		values := v.AsStringSlice()
+		changed := false
+		for i, s := range values {
+			if redacted, ok := r.redactString(key, s); ok {
+				values[i] = redacted
+				changed = true
+			}
+		}
+		if changed {
+			return attribute.StringSliceValue(values), true
+		}
+	}
+	return v, scrubbed
+}
+
+func (r *redactor) redactString(key attribute.Key, s string) (string, bool) {
+	changed := false
+	for _, rule := range r.rules {
+		if rule.keys != nil && !rule.keys[key] {
+			continue
+		}
+		if rule.pattern.MatchString(s) {
+			s = rule.pattern.ReplaceAllStringFunc(s, rule.replace)
+			changed = true
+		}
+	}
+	return s, changed
+}
@@ -0,0 +1,87 @@
+package otelx
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestRedactionRules(t *testing.T) {
+	cfg := Config{
+		ServiceName:   "svc",
+		Exporter:      ExporterMemory,
+		SamplingRatio: Float64(1),
+		RedactionRules: []RedactionRule{
+			{Builtin: "email", Action: RedactionHash},
+			{Builtin: "bearerToken"},
+			{Pattern: `\d{4}`, Keys: []string{"pin"}, Replacement: "****"},
+		},
+	}
+	prov, err := Setup(context.Background(), cfg, nil)
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	defer prov.Shutdown(context.Background())
+
+	_, span := prov.TP.Tracer("test").Start(context.Background(), "op", trace.WithAttributes(
+		attribute.String("user.email", "alice@example.com"),
+		attribute.String("http.request.header.authorization", "Bearer abc.def"),
+		attribute.String("pin", "1234"),
+		attribute.String("order", "1234"),
+		attribute.StringSlice("cc", []string{"bob@example.com", "none"}),
+	))
+	span.AddEvent("login", trace.WithAttributes(attribute.String("message", "welcome alice@example.com")))
+	span.End()
+
+	spans := prov.RecordedSpans()
+	if len(spans) != 1 {
+		t.Fatalf("expected one span, got %d", len(spans))
+	}
+	got := attribute.NewSet(spans[0].Attributes...)
+	email, _ := got.Value("user.email")
+	if !strings.HasPrefix(email.AsString(), "sha256:") || len(email.AsString()) != len("sha256:")+16 {
+		t.Fatalf("expected a hashed email, got %q", email.AsString())
+	}
+	for key, want := range map[attribute.Key]string{
+		"http.request.header.authorization": DefaultRedactionReplacement,
+		"pin":                               "****",
+		"order":                             "1234",
+	} {
+		if v, _ := got.Value(key); v.AsString() != want {
+			t.Fatalf("expected %s=%q, got %q", key, want, v.AsString())
+		}
+	}
+	if cc, _ := got.Value("cc"); !strings.HasPrefix(cc.AsStringSlice()[0], "sha256:") || cc.AsStringSlice()[1] != "none" {
+		t.Fatalf("expected slice values to be redacted, got %v", cc.AsStringSlice())
+	}
+	if msg := spans[0].Events[0].Attributes[0].Value.AsString(); msg == "welcome alice@example.com" || !strings.HasPrefix(msg, "welcome sha256:") {
+		t.Fatalf("expected event attributes to be redacted, got %q", msg)
+	}
+
+	found := false
+	for _, p := range prov.Describe().Processors {
+		found = found || (p.Name == string(StageRedaction) && p.Phase == PhaseRedact.String())
+	}
+	if !found {
+		t.Fatalf("expected redaction in the description")
+	}
+}
+
+func TestRedactionRulesValidation(t *testing.T) {
+	cases := map[string]RedactionRule{
+		"exactly one of pattern and builtin": {},
+		"unknown builtin":                    {Builtin: "ssn"},
+		"pattern:":                           {Pattern: "("},
+		"unsupported action":                 {Builtin: "email", Action: "drop"},
+	}
+	for want, rule := range cases {
+		cfg := Config{ServiceName: "svc", Exporter: ExporterMemory, RedactionRules: []RedactionRule{rule}}
+		_, err := Setup(context.Background(), cfg, nil)
+		if err == nil || !strings.Contains(err.Error(), "otelx: redactionRules[0]: "+want) {
+			t.Fatalf("expected %q error, got %v", want, err)
+		}
+	}
+}
@@ -0,0 +1,315 @@
+package otelx
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	logx "github.com/bionicotaku/lingo-utils-logx"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// RemoteConfig is the document a control plane serves to running services. Nil fields leave the
+// current setting unchanged.
+type RemoteConfig struct {
+	// SamplingRatio replaces the head sampling ratio.
+	SamplingRatio *float64 `json:"samplingRatio"`
+	// DropAttributes lists span attribute keys removed before export; an empty list clears it.
+	DropAttributes []string `json:"dropAttributes"`
+	// Exporters replaces the pipelines from Config, one entry per configured pipeline in the same
+	// order. Only changed pipelines are rebuilt.
+	Exporters []ExporterConfig `json:"exporters"`
+}
+
+// dynamicSampler is a TraceIDRatioBased sampler whose ratio can change at runtime.
+type dynamicSampler struct {
+	current   atomic.Pointer[sdktrace.Sampler]
+	ratioBits atomic.Uint64
+}
+
+func newDynamicSampler(ratio float64) *dynamicSampler {
+	s := &dynamicSampler{}
+	s.setRatio(ratio)
+	return s
+}
+
+func (s *dynamicSampler) setRatio(ratio float64) {
+	sampler := sdktrace.TraceIDRatioBased(ratio)
+	s.current.Store(&sampler)
+	s.ratioBits.Store(math.Float64bits(ratio))
+}
+
+func (s *dynamicSampler) ratio() float64 {
+	return math.Float64frombits(s.ratioBits.Load())
+}
+
+func (s *dynamicSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	return (*s.current.Load()).ShouldSample(p)
+}
+
+func (s *dynamicSampler) Description() string {
+	return (*s.current.Load()).Description()
+}
+
+// attributeFilter removes a runtime-configurable set of attribute keys at export.
+type attributeFilter struct {
+	keys atomic.Pointer[map[attribute.Key]bool]
+}
+
+func (f *attributeFilter) set(keys []string) {
+	if len(keys) == 0 {
+		f.keys.Store(nil)
+		return
+	}
+	drop := make(map[attribute.Key]bool, len(keys))
+	for _, k := range keys {
+		drop[attribute.Key(k)] = true
+	}
+	f.keys.Store(&drop)
+}
+
+func (f *attributeFilter) filter(span sdktrace.ReadOnlySpan) sdktrace.ReadOnlySpan {
+	drop := f.keys.Load()
+	if drop == nil {
+		return span
+	}
+	attrs := span.Attributes()
+	kept := make([]attribute.KeyValue, 0, len(attrs))
+	for _, kv := range attrs {
+		if !(*drop)[kv.Key] {
+			kept = append(kept, kv)
+		}
+	}
+	if len(kept) == len(attrs) {
+		return span
+	}
+	o := override(span)
+	o.setAttributes(kept...)
+	return o
+}
+
+// swappableExporter lets the control plane replace a pipeline's exporter while the batcher in
+// front of it keeps running. Swaps wait for in-flight exports to finish.
+type swappableExporter struct {
+	mu      sync.RWMutex
+	current sdktrace.SpanExporter
+	cfg     ExporterConfig
+}
+
+func (e *swappableExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.current.ExportSpans(ctx, spans)
+}
+
+func (e *swappableExporter) Shutdown(ctx context.Context) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.current.Shutdown(ctx)
+}
+
+func (e *swappableExporter) config() ExporterConfig {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.cfg
+}
+
+// swap installs exporter and returns the previous one, which the caller shuts down.
+func (e *swappableExporter) swap(exporter sdktrace.SpanExporter, cfg ExporterConfig) sdktrace.SpanExporter {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	old := e.current
+	e.current, e.cfg = exporter, cfg
+	return old
+}
+
+// errCustomSampler rejects sampling ratio changes on providers whose sampler came from WithSampler.
+var errCustomSampler = errors.New("otelx: sampling ratio is managed by the sampler passed to WithSampler")
+
+// SetSamplingRatio changes the head sampling ratio of a provider built by Setup without
+// restarting it. Spans already started keep their decision. Providers using WithSampler or
+// Config.RemoteSampling reject it.
+func (p *Provider) SetSamplingRatio(ratio float64) error {
+	if p == nil || p.sampler == nil {
+		return errors.New("otelx: sampling ratio can only be changed on a provider built by Setup")
+	}
+	if p.ratioLocked != nil {
+		return p.ratioLocked
+	}
+	if ratio < 0 || ratio > 1 {
+		return fmt.Errorf("otelx: samplingRatio must be within [0,1], got %v", ratio)
+	}
+	p.sampler.setRatio(ratio)
+	return nil
+}
+
+// ApplyRemoteConfig applies settings pushed by a control plane. The whole document is validated and
+// every changed exporter is built before anything changes, so a rejected document leaves the
+// provider as it was; exporter pipelines are rebuilt only when their config differs. Header
+// values with ${env:...} or ${file:...} references are rejected, so a control plane cannot make
+// the process send local secrets to an endpoint of its choosing.
+func (p *Provider) ApplyRemoteConfig(ctx context.Context, rc RemoteConfig) error {
+	if p == nil || p.sampler == nil {
+		return errors.New("otelx: remote config requires a provider built by Setup")
+	}
+	if rc.SamplingRatio != nil {
+		if p.ratioLocked != nil {
+			return p.ratioLocked
+		}
+		if ratio := *rc.SamplingRatio; ratio < 0 || ratio > 1 {
+			return fmt.Errorf("otelx: samplingRatio must be within [0,1], got %v", ratio)
+		}
+	}
+	exporters := make([]ExporterConfig, len(rc.Exporters))
+	if len(rc.Exporters) > 0 {
+		if len(rc.Exporters) != len(p.pipelines) {
+			return fmt.Errorf("otelx: remote config has %d exporters, provider has %d replaceable pipelines", len(rc.Exporters), len(p.pipelines))
+		}
+		for i, ec := range rc.Exporters {
+			exporters[i] = ec.sanitize()
+			if err := exporters[i].validate(); err != nil {
+				return fmt.Errorf("otelx: exporters[%d]: %w", i, err)
+			}
+			if err := rejectHeaderRefs(exporters[i].Headers); err != nil {
+				return fmt.Errorf("otelx: exporters[%d]: %w", i, err)
+			}
+		}
+	}
+
+	// Build every changed exporter before touching anything, so a failed build changes nothing.
+	built := make([]sdktrace.SpanExporter, len(exporters))
+	for i, ec := range exporters {
+		if reflect.DeepEqual(p.pipelines[i].config(), ec) {
+			continue
+		}
+		exporter, err := buildExporter(ctx, ec, p.logger)
+		if err != nil {
+			for _, b := range built {
+				if b != nil {
+					_ = b.Shutdown(ctx)
+				}
+			}
+			return fmt.Errorf("otelx: exporters[%d]: %w", i, err)
+		}
+		built[i] = withExportRatio(exporter, ec.ExportRatio)
+	}
+
+	var applied []string
+	if rc.SamplingRatio != nil {
+		p.sampler.setRatio(*rc.SamplingRatio)
+		applied = append(applied, "samplingRatio")
+	}
+	if rc.DropAttributes != nil {
+		p.attrFilter.set(rc.DropAttributes)
+		applied = append(applied, "dropAttributes")
+	}
+	for i, exporter := range built {
+		if exporter == nil {
+			continue
+		}
+		old := p.pipelines[i].swap(exporter, exporters[i])
+		_ = old.Shutdown(ctx)
+		applied = append(applied, fmt.Sprintf("exporters[%d]", i))
+	}
+
+	if p.logger != nil && len(applied) > 0 {
+		p.logger.Info(ctx, "otelx.remote.applied", logx.Any("fields", applied))
+	}
+	return nil
+}
+
+// StartRemoteControl polls endpoint every interval for a JSON RemoteConfig and applies it, turning
+// the service into a remotely managed agent. Requests carry the service name and environment as
+// query parameters and honour ETag/If-None-Match; failures are logged as otelx.remote.failed and
+// the last good settings stay in place. A nil client uses a client with a 10s timeout. Call the
+// returned function to stop polling.
+func (p *Provider) StartRemoteControl(endpoint string, interval time.Duration, client *http.Client) (func(), error) {
+	if p == nil || p.sampler == nil {
+		return nil, errors.New("otelx: remote control requires a provider built by Setup")
+	}
+	if interval <= 0 {
+		return nil, fmt.Errorf("otelx: remote control interval must be positive, got %v", interval)
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("otelx: invalid remote control endpoint %q", endpoint)
+	}
+	q := u.Query()
+	q.Set("service", p.serviceName)
+	if p.environment != "" {
+		q.Set("environment", p.environment)
+	}
+	u.RawQuery = q.Encode()
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		var etag string
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			next, err := p.pollRemoteConfig(ctx, client, u.String(), etag)
+			if err != nil && ctx.Err() == nil && p.logger != nil {
+				p.logger.Warn(ctx, "otelx.remote.failed", logx.String("endpoint", endpoint), logx.String("error", err.Error()))
+			}
+			if err == nil {
+				etag = next
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return func() {
+		cancel()
+		<-done
+	}, nil
+}
+
+// pollRemoteConfig fetches and applies one RemoteConfig, returning the ETag to send next time.
+func (p *Provider) pollRemoteConfig(ctx context.Context, client *http.Client, endpoint, etag string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return etag, err
+	}
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return etag, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusNotModified:
+		return etag, nil
+	case http.StatusOK:
+	default:
+		return etag, fmt.Errorf("otelx: remote control returned status %d", resp.StatusCode)
+	}
+	var rc RemoteConfig
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&rc); err != nil {
+		return etag, fmt.Errorf("otelx: decode remote config: %w", err)
+	}
+	if err := p.ApplyRemoteConfig(ctx, rc); err != nil {
+		return etag, err
+	}
+	return resp.Header.Get("ETag"), nil
+}
@@ -0,0 +1,77 @@
+package otelx
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	logx "github.com/bionicotaku/lingo-utils-logx"
+	"go.opentelemetry.io/contrib/samplers/jaegerremote"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// DefaultRemoteSamplingInterval is how often strategies are polled when RefreshInterval is unset.
+const DefaultRemoteSamplingInterval = time.Minute
+
+// RemoteSamplingConfig points Setup at a Jaeger-compatible sampling endpoint (Jaeger agent or
+// collector, or the OpenTelemetry Collector's jaegerremotesampling extension), so sampling
+// strategies are managed centrally and picked up without a redeploy.
+type RemoteSamplingConfig struct {
+	// Endpoint is the strategies URL, e.g. "http://jaeger-agent:5778/sampling"; the service name is
+	// added as the "service" query parameter.
+	Endpoint string `json:"endpoint"`
+	// RefreshInterval is the polling period (default DefaultRemoteSamplingInterval).
+	RefreshInterval time.Duration `json:"refreshInterval"`
+}
+
+func (c RemoteSamplingConfig) validate() error {
+	if c.Endpoint == "" {
+		return errors.New("endpoint is required")
+	}
+	u, err := url.Parse(c.Endpoint)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("endpoint must be an http(s) URL, got %q", c.Endpoint)
+	}
+	if c.RefreshInterval < 0 {
+		return errors.New("refreshInterval must not be negative")
+	}
+	return nil
+}
+
+// errRemoteSampling rejects sampling ratio changes on providers using Config.RemoteSampling.
+var errRemoteSampling = errors.New("otelx: sampling ratio is managed by the remote sampling endpoint")
+
+// remoteSampler polls strategies for serviceName. Until the first successful fetch spans go to
+// initial, the SamplingRules and SamplingRatio sampler.
+type remoteSampler struct {
+	*jaegerremote.Sampler
+	endpoint string
+}
+
+func newRemoteSampler(cfg RemoteSamplingConfig, serviceName string, initial sdktrace.Sampler, logger logx.Logger) *remoteSampler {
+	interval := cfg.RefreshInterval
+	if interval == 0 {
+		interval = DefaultRemoteSamplingInterval
+	}
+	opts := []jaegerremote.Option{
+		jaegerremote.WithSamplingServerURL(cfg.Endpoint),
+		jaegerremote.WithSamplingRefreshInterval(interval),
+		jaegerremote.WithInitialSampler(initial),
+	}
+	if logger != nil {
+		opts = append(opts, jaegerremote.WithLogger(newSDKLogger(logger, "warn").WithName("otelx.sampler.remote")))
+	}
+	return &remoteSampler{Sampler: jaegerremote.New(serviceName, opts...), endpoint: cfg.Endpoint}
+}
+
+func (s *remoteSampler) Description() string {
+	return "JaegerRemoteSampler{endpoint=" + s.endpoint + "}"
+}
+
+// close stops polling; it is a no-op on a nil sampler.
+func (s *remoteSampler) close() {
+	if s != nil {
+		s.Sampler.Close()
+	}
+}
@@ -0,0 +1,79 @@
+package otelx
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRemoteSamplingAppliesFetchedStrategy(t *testing.T) {
+	var service atomic.Value
+	var rate atomic.Value
+	rate.Store("0")
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		service.Store(r.URL.Query().Get("service"))
+		_, _ = w.Write([]byte(`{"strategyType":"PROBABILISTIC","probabilisticSampling":{"samplingRate":` + rate.Load().(string) + `}}`))
+	}))
+	defer srv.Close()
+
+	cfg := Config{
+		ServiceName:    "svc",
+		Exporter:       ExporterMemory,
+		SamplingRatio:  Float64(1),
+		RemoteSampling: &RemoteSamplingConfig{Endpoint: srv.URL + "/sampling", RefreshInterval: 10 * time.Millisecond},
+	}
+	prov, err := Setup(context.Background(), cfg, nil)
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	defer prov.Shutdown(context.Background())
+
+	sampled := func() bool {
+		_, span := prov.TP.Tracer("test").Start(context.Background(), "op")
+		defer span.End()
+		return span.SpanContext().IsSampled()
+	}
+	waitFor := func(want bool) {
+		t.Helper()
+		deadline := time.Now().Add(2 * time.Second)
+		for sampled() != want {
+			if time.Now().After(deadline) {
+				t.Fatalf("remote strategy not applied, want sampled=%v", want)
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+	waitFor(false)
+	rate.Store("1")
+	waitFor(true)
+
+	if got, _ := service.Load().(string); got != "svc" {
+		t.Fatalf("expected the service name in the query, got %q", got)
+	}
+	if got := prov.Describe().Sampler; !strings.Contains(got, "JaegerRemoteSampler{endpoint="+srv.URL+"/sampling}") {
+		t.Fatalf("expected remote sampler in description, got %q", got)
+	}
+	if err := prov.SetSamplingRatio(0.5); !errors.Is(err, errRemoteSampling) {
+		t.Fatalf("expected ratio changes to be rejected, got %v", err)
+	}
+}
+
+func TestRemoteSamplingValidation(t *testing.T) {
+	cases := map[string]RemoteSamplingConfig{
+		"endpoint is required":        {},
+		"endpoint must be an http(s)": {Endpoint: "jaeger-agent:5778"},
+		"refreshInterval must not be": {Endpoint: "http://jaeger-agent:5778/sampling", RefreshInterval: -time.Second},
+	}
+	for want, rs := range cases {
+		cfg := Config{ServiceName: "svc", Exporter: ExporterMemory, RemoteSampling: &rs}
+		_, err := Setup(context.Background(), cfg, nil)
+		if err == nil || !strings.Contains(err.Error(), "otelx: remoteSampling: "+want) {
+			t.Fatalf("expected %q error, got %v", want, err)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package otelx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func TestSetSamplingRatio(t *testing.T) {
+	prov, err := Setup(context.Background(), Config{ServiceName: "svc", Exporter: ExporterMemory, SamplingRatio: Float64(0)}, noopLogger{})
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	defer prov.Shutdown(context.Background())
+
+	tracer := prov.TP.Tracer("test")
+	if _, span := tracer.Start(context.Background(), "before"); span.SpanContext().IsSampled() {
+		t.Fatalf("expected ratio 0 to drop spans")
+	}
+	if err := prov.SetSamplingRatio(1); err != nil {
+		t.Fatalf("set ratio: %v", err)
+	}
+	if _, span := tracer.Start(context.Background(), "after"); !span.SpanContext().IsSampled() {
+		t.Fatalf("expected ratio 1 to sample spans")
+	}
+	if err := prov.SetSamplingRatio(2); err == nil {
+		t.Fatalf("expected ratio outside [0,1] to be rejected")
+	}
+}
+
+func TestApplyRemoteConfig(t *testing.T) {
+	cfg := Config{ServiceName: "svc", SamplingRatio: Float64(1), Exporters: []ExporterConfig{{Exporter: ExporterMemory}}}
+	prov, err := Setup(context.Background(), cfg, noopLogger{})
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	defer prov.Shutdown(context.Background())
+
+	if err := prov.ApplyRemoteConfig(context.Background(), RemoteConfig{DropAttributes: []string{"secret"}}); err != nil {
+		t.Fatalf("apply failed: %v", err)
+	}
+	_, span := prov.TP.Tracer("test").Start(context.Background(), "op")
+	span.SetAttributes(attribute.String("secret", "x"), attribute.String("kept", "y"))
+	span.End()
+	spans := prov.RecordedSpans()
+	if len(spans) != 1 || spanHasAttribute(spans[0].Attributes, "secret", "x") || !spanHasAttribute(spans[0].Attributes, "kept", "y") {
+		t.Fatalf("expected dropped attribute to be removed, got %v", spans)
+	}
+
+	path := filepath.Join(t.TempDir(), "spans.jsonl")
+	rc := RemoteConfig{Exporters: []ExporterConfig{{Exporter: ExporterFile, FilePath: path}}}
+	if err := prov.ApplyRemoteConfig(context.Background(), rc); err != nil {
+		t.Fatalf("apply exporters failed: %v", err)
+	}
+	_, span = prov.TP.Tracer("test").Start(context.Background(), "op")
+	span.End()
+	_ = prov.TP.ForceFlush(context.Background())
+	if lines := readLines(t, path); len(lines) != 1 {
+		t.Fatalf("expected spans to reach the replacement exporter, got %d lines", len(lines))
+	}
+
+	for name, bad := range map[string]RemoteConfig{
+		"ratio":     {SamplingRatio: Float64(-1)},
+		"count":     {Exporters: []ExporterConfig{{Exporter: ExporterMemory}, {Exporter: ExporterMemory}}},
+		"validated": {Exporters: []ExporterConfig{{Exporter: ExporterCloudTrace}}},
+	} {
+		if err := prov.ApplyRemoteConfig(context.Background(), bad); err == nil {
+			t.Fatalf("%s: expected remote config to be rejected", name)
+		}
+	}
+}
+
+func TestStartRemoteControlPollsWithETag(t *testing.T) {
+	var polls, notModified atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		polls.Add(1)
+		if r.URL.Query().Get("service") != "svc" || r.URL.Query().Get("environment") != "prod" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		_, _ = w.Write([]byte(`{"samplingRatio": 1}`))
+	}))
+	defer srv.Close()
+
+	cfg := Config{ServiceName: "svc", Environment: "prod", Exporter: ExporterMemory, SamplingRatio: Float64(0)}
+	prov, err := Setup(context.Background(), cfg, noopLogger{})
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	defer prov.Shutdown(context.Background())
+
+	stop, err := prov.StartRemoteControl(srv.URL+"/config", 5*time.Millisecond, nil)
+	if err != nil {
+		t.Fatalf("start remote control: %v", err)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for notModified.Load() == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	stop()
+
+	if notModified.Load() == 0 {
+		t.Fatalf("expected later polls to send If-None-Match, got %d polls", polls.Load())
+	}
+	if _, span := prov.TP.Tracer("test").Start(context.Background(), "op"); !span.SpanContext().IsSampled() {
+		t.Fatalf("expected remote sampling ratio to be applied")
+	}
+}
+
+func TestApplyRemoteConfigChangesNothingWhenABuildFails(t *testing.T) {
+	cfg := Config{ServiceName: "svc", SamplingRatio: Float64(1), Exporters: []ExporterConfig{{Exporter: ExporterMemory}, {Exporter: ExporterMemory}}}
+	prov, err := Setup(context.Background(), cfg, noopLogger{})
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	defer prov.Shutdown(context.Background())
+
+	// A regular file where the second exporter needs a directory makes its build fail.
+	blocker := filepath.Join(t.TempDir(), "blocker")
+	if err := os.WriteFile(blocker, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	rc := RemoteConfig{
+		SamplingRatio:  Float64(0),
+		DropAttributes: []string{"secret"},
+		Exporters: []ExporterConfig{
+			{Exporter: ExporterFile, FilePath: filepath.Join(t.TempDir(), "spans.jsonl")},
+			{Exporter: ExporterFile, FilePath: filepath.Join(blocker, "spans.jsonl")},
+		},
+	}
+	if err := prov.ApplyRemoteConfig(context.Background(), rc); err == nil {
+		t.Fatal("expected the failing exporter build to be reported")
+	}
+	if prov.sampler.ratio() != 1 || prov.attrFilter.keys.Load() != nil {
+		t.Fatal("expected sampling ratio and dropped attributes to stay unchanged")
+	}
+	for i, pipeline := range prov.pipelines {
+		if got := pipeline.config().Exporter; got != ExporterMemory {
+			t.Fatalf("expected pipeline %d to stay unchanged, got %s", i, got)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package otelx
+
+import (
+	"fmt"
+	"strings"
+
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+)
+
+// applyResourceEnv fills ServiceName from OTEL_SERVICE_NAME (or service.name in
+// OTEL_RESOURCE_ATTRIBUTES) when it is empty and merges the other OTEL_RESOURCE_ATTRIBUTES entries
+// into ResourceAttrs; Config values take precedence. service.version and
+// deployment.environment.name only apply when ServiceVersion and Environment are unset. An
+// unparsable OTEL_RESOURCE_ATTRIBUTES is reported and ignored.
+func (cfg Config) applyResourceEnv(getenv func(string) string) (Config, error) {
+	var envAttrs map[string]string
+	var err error
+	if raw := strings.TrimSpace(getenv("OTEL_RESOURCE_ATTRIBUTES")); raw != "" {
+		if envAttrs, err = parseKeyValueList(raw); err != nil {
+			envAttrs, err = nil, fmt.Errorf("OTEL_RESOURCE_ATTRIBUTES: %w", err)
+		}
+	}
+
+	if cfg.ServiceName == "" {
+		cfg.ServiceName = strings.TrimSpace(getenv("OTEL_SERVICE_NAME"))
+	}
+	if cfg.ServiceName == "" {
+		cfg.ServiceName = strings.TrimSpace(envAttrs[string(semconv.ServiceNameKey)])
+	}
+	delete(envAttrs, string(semconv.ServiceNameKey))
+	if cfg.ServiceVersion != "" {
+		delete(envAttrs, string(semconv.ServiceVersionKey))
+	}
+	if cfg.Environment != "" {
+		delete(envAttrs, string(semconv.DeploymentEnvironmentNameKey))
+	}
+	if len(envAttrs) == 0 {
+		return cfg, err
+	}
+
+	merged := make(map[string]string, len(envAttrs)+len(cfg.ResourceAttrs))
+	for k, v := range envAttrs {
+		merged[k] = v
+	}
+	for k, v := range cfg.ResourceAttrs {
+		merged[k] = v
+	}
+	cfg.ResourceAttrs = merged
+	return cfg, err
+}
@@ -0,0 +1,65 @@
+package otelx
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+)
+
+func TestApplyResourceEnv(t *testing.T) {
+	cfg, err := Config{ResourceAttrs: map[string]string{"team": "core"}}.applyResourceEnv(envMap(map[string]string{
+		"OTEL_SERVICE_NAME":        "checkout",
+		"OTEL_RESOURCE_ATTRIBUTES": "service.name=ignored,service.version=1.2.3,team=payments,k8s.pod.name=pod%201",
+	}))
+	if err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	if cfg.ServiceName != "checkout" {
+		t.Fatalf("service name = %q, want checkout", cfg.ServiceName)
+	}
+	want := map[string]string{"team": "core", "service.version": "1.2.3", "k8s.pod.name": "pod 1"}
+	if !reflect.DeepEqual(cfg.ResourceAttrs, want) {
+		t.Fatalf("resource attrs = %v, want %v", cfg.ResourceAttrs, want)
+	}
+
+	cfg, _ = Config{ServiceName: "svc", ServiceVersion: "2.0.0"}.applyResourceEnv(envMap(map[string]string{
+		"OTEL_SERVICE_NAME":        "checkout",
+		"OTEL_RESOURCE_ATTRIBUTES": "service.version=1.2.3",
+	}))
+	if cfg.ServiceName != "svc" || cfg.ResourceAttrs != nil {
+		t.Fatalf("config values should win, got %q %v", cfg.ServiceName, cfg.ResourceAttrs)
+	}
+
+	cfg, _ = Config{}.applyResourceEnv(envMap(map[string]string{"OTEL_RESOURCE_ATTRIBUTES": "service.name=billing"}))
+	if cfg.ServiceName != "billing" {
+		t.Fatalf("service name = %q, want billing from OTEL_RESOURCE_ATTRIBUTES", cfg.ServiceName)
+	}
+
+	if _, err := (Config{ServiceName: "svc"}).applyResourceEnv(envMap(map[string]string{"OTEL_RESOURCE_ATTRIBUTES": "broken"})); err == nil {
+		t.Fatalf("expected error for malformed OTEL_RESOURCE_ATTRIBUTES")
+	}
+}
+
+func TestSetupServiceNameFromEnv(t *testing.T) {
+	restore := saveGlobal()
+	defer restore()
+	t.Setenv("OTEL_SERVICE_NAME", "checkout")
+	t.Setenv("OTEL_RESOURCE_ATTRIBUTES", "team=payments")
+
+	prov, err := Setup(context.Background(), Config{SamplingRatio: Float64(1)}, nil)
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	defer prov.Shutdown(context.Background())
+	capture := &resourceCapture{}
+	prov.TP.RegisterSpanProcessor(capture)
+	_, span := prov.TP.Tracer("test").Start(context.Background(), "op")
+	span.End()
+
+	res := capture.Resource()
+	if !hasAttribute(res, semconv.ServiceNameKey, "checkout") || !hasAttribute(res, "team", "payments") {
+		t.Fatalf("unexpected resource %v", res)
+	}
+}
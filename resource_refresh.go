@@ -0,0 +1,128 @@
+package otelx
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	logx "github.com/bionicotaku/lingo-utils-logx"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// dynamicResource re-runs resource detection periodically, see WithResourceRefresh.
+type dynamicResource struct {
+	base    *resource.Resource
+	current atomic.Pointer[resource.Resource]
+	detect  func(context.Context) (*resource.Resource, error)
+	logger  logx.Logger
+	stop    func()
+}
+
+func newDynamicResource(base *resource.Resource, detect func(context.Context) (*resource.Resource, error), logger logx.Logger) *dynamicResource {
+	r := &dynamicResource{base: base, detect: detect, logger: logger}
+	r.current.Store(base)
+	return r
+}
+
+// start refreshes the resource every interval until the returned stop function is called.
+func (r *dynamicResource) start(interval time.Duration) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				r.refresh(ctx)
+			}
+		}
+	}()
+	var once sync.Once
+	r.stop = func() {
+		once.Do(func() {
+			cancel()
+			<-done
+		})
+	}
+}
+
+func (r *dynamicResource) refresh(ctx context.Context) {
+	detected, err := r.detect(ctx)
+	if err != nil {
+		if r.logger != nil && ctx.Err() == nil {
+			r.logger.Warn(ctx, "otelx.resource.refresh.failed", logx.String("error", err.Error()))
+		}
+		return
+	}
+	previous := r.current.Load()
+	if detected.Equal(previous) {
+		return
+	}
+	r.current.Store(detected)
+	if r.logger != nil {
+		r.logger.Info(ctx, "otelx.resource.refreshed", logx.Any("attributes", detected.Len()))
+	}
+}
+
+// resourceProcessor attaches the resource current at span start to each span before handing it
+// to the export pipelines it wraps. Spans started before a refresh keep the resource they
+// started with; the TracerProvider's own resource is used until the first change.
+type resourceProcessor struct {
+	resource *dynamicResource
+	next     []sdktrace.SpanProcessor
+	starts   sync.Map // span id -> *resource.Resource, only when it differs from the base
+}
+
+func newResourceProcessor(r *dynamicResource, next []sdktrace.SpanProcessor) *resourceProcessor {
+	return &resourceProcessor{resource: r, next: next}
+}
+
+func (p *resourceProcessor) OnStart(ctx context.Context, s sdktrace.ReadWriteSpan) {
+	if current := p.resource.current.Load(); current != p.resource.base {
+		p.starts.Store(s.SpanContext().SpanID(), current)
+	}
+	for _, next := range p.next {
+		next.OnStart(ctx, s)
+	}
+}
+
+func (p *resourceProcessor) OnEnd(s sdktrace.ReadOnlySpan) {
+	var span sdktrace.ReadOnlySpan = s
+	if v, ok := p.starts.LoadAndDelete(s.SpanContext().SpanID()); ok {
+		o := override(s)
+		o.resource = v.(*resource.Resource)
+		span = o
+	}
+	for _, next := range p.next {
+		next.OnEnd(span)
+	}
+}
+
+func (p *resourceProcessor) Shutdown(ctx context.Context) error {
+	if p.resource.stop != nil {
+		p.resource.stop()
+	}
+	var firstErr error
+	for _, next := range p.next {
+		if err := next.Shutdown(ctx); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func (p *resourceProcessor) ForceFlush(ctx context.Context) error {
+	var firstErr error
+	for _, next := range p.next {
+		if err := next.ForceFlush(ctx); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
@@ -0,0 +1,57 @@
+package otelx
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+// flagDetector reports the current value of a flag as a resource attribute.
+type flagDetector struct{ value atomic.Value }
+
+func (d *flagDetector) Detect(context.Context) (*resource.Resource, error) {
+	return resource.NewSchemaless(attribute.String("cloud.preemption", d.value.Load().(string))), nil
+}
+
+func TestWithResourceRefreshAppliesToNewSpans(t *testing.T) {
+	detector := &flagDetector{}
+	detector.value.Store("none")
+	cfg := Config{ServiceName: "svc", Exporter: ExporterMemory, SamplingRatio: Float64(1)}
+	prov, err := Setup(context.Background(), cfg, nil,
+		WithResourceOptions(resource.WithDetectors(detector)),
+		WithResourceRefresh(10*time.Millisecond),
+	)
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	defer prov.Shutdown(context.Background())
+
+	tracer := prov.TP.Tracer("test")
+	_, before := tracer.Start(context.Background(), "before")
+	detector.value.Store("scheduled")
+	waitFor(t, func() bool {
+		_, probe := tracer.Start(context.Background(), "probe")
+		probe.End()
+		spans := prov.RecordedSpans()
+		v, _ := spans[len(spans)-1].Resource.Set().Value("cloud.preemption")
+		return v.AsString() == "scheduled"
+	})
+	before.End()
+
+	spans := prov.RecordedSpans()
+	last := spans[len(spans)-1]
+	if last.Name != "before" {
+		t.Fatalf("expected the before span last, got %q", last.Name)
+	}
+	if v, _ := last.Resource.Set().Value("cloud.preemption"); v.AsString() != "none" {
+		t.Fatalf("expected span started before the refresh to keep its resource, got %q", v.AsString())
+	}
+	refreshed := spans[len(spans)-2].Resource
+	if v, _ := refreshed.Set().Value("service.name"); v.AsString() != "svc" {
+		t.Fatalf("expected refreshed resource to keep service.name, got %q", v.AsString())
+	}
+}
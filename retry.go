@@ -0,0 +1,108 @@
+package otelx
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName identifies spans created by otelx helpers.
+const instrumentationName = "github.com/bionicotaku/lingo-utils-otelx"
+
+// Attribute keys recorded by Retry.
+const (
+	RetryAttemptKey     = attribute.Key("retry.attempt")
+	RetryAttemptsKey    = attribute.Key("retry.attempts")
+	RetryMaxAttemptsKey = attribute.Key("retry.max_attempts")
+	RetryBackoffKey     = attribute.Key("retry.backoff_ms")
+)
+
+// RetryPolicy controls how Retry re-runs an operation.
+type RetryPolicy struct {
+	// Name is used for the parent span; attempts are named "<Name> attempt". Defaults to "retry".
+	Name string
+	// MaxAttempts caps the number of attempts, including the first one. Defaults to 3.
+	MaxAttempts int
+	// InitialBackoff is the wait before the second attempt. Defaults to 100ms.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the wait between attempts. Zero means no cap.
+	MaxBackoff time.Duration
+	// Multiplier grows the backoff after every attempt. Defaults to 2.
+	Multiplier float64
+	// Retryable reports whether err is worth another attempt. Defaults to retrying every error.
+	Retryable func(error) bool
+}
+
+func (p RetryPolicy) withDefaults() RetryPolicy {
+	if p.Name == "" {
+		p.Name = "retry"
+	}
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = 3
+	}
+	if p.InitialBackoff <= 0 {
+		p.InitialBackoff = 100 * time.Millisecond
+	}
+	if p.Multiplier < 1 {
+		p.Multiplier = 2
+	}
+	return p
+}
+
+// Retry runs fn until it succeeds, returns a non-retryable error, the policy is exhausted or ctx is done.
+// When ctx is done, the returned error wraps ctx.Err() as well as the last attempt's error.
+// It creates a parent span with one child span per attempt, records backoff waits as events and marks
+// the parent span with the final outcome; errors are recorded through RecordError, so registered
+// ErrorMappings apply. Spans are created from the global TracerProvider.
+func Retry(ctx context.Context, policy RetryPolicy, fn func(ctx context.Context, attempt int) error) error {
+	policy = policy.withDefaults()
+	tracer := otel.Tracer(instrumentationName)
+
+	ctx, span := tracer.Start(ctx, policy.Name, trace.WithAttributes(RetryMaxAttemptsKey.Int(policy.MaxAttempts)))
+	defer span.End()
+
+	backoff := policy.InitialBackoff
+	var err error
+	attempt := 1
+	for ; ; attempt++ {
+		attemptCtx, attemptSpan := tracer.Start(ctx, policy.Name+" attempt", trace.WithAttributes(RetryAttemptKey.Int(attempt)))
+		err = fn(attemptCtx, attempt)
+		recordSpanError(attemptSpan, err)
+		attemptSpan.End()
+
+		if err == nil || attempt >= policy.MaxAttempts || (policy.Retryable != nil && !policy.Retryable(err)) {
+			break
+		}
+
+		span.AddEvent("retry.backoff", trace.WithAttributes(
+			RetryAttemptKey.Int(attempt),
+			RetryBackoffKey.Int64(backoff.Milliseconds()),
+		))
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+		case <-timer.C:
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		backoff = time.Duration(float64(backoff) * policy.Multiplier)
+		if policy.MaxBackoff > 0 && backoff > policy.MaxBackoff {
+			backoff = policy.MaxBackoff
+		}
+	}
+
+	// Whichever way the loop ended, a done ctx is part of the error so callers can match it.
+	if ctxErr := ctx.Err(); err != nil && ctxErr != nil && !errors.Is(err, ctxErr) {
+		err = errors.Join(err, ctxErr)
+	}
+	span.SetAttributes(RetryAttemptsKey.Int(attempt))
+	recordSpanError(span, err)
+	return err
+}
@@ -0,0 +1,88 @@
+package otelx
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestRetryRecordsAttemptsAndBackoff(t *testing.T) {
+	restore := saveGlobal()
+	defer restore()
+
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	otel.SetTracerProvider(tp)
+
+	calls := 0
+	err := Retry(context.Background(), RetryPolicy{Name: "fetch", InitialBackoff: time.Millisecond}, func(context.Context, int) error {
+		calls++
+		if calls < 2 {
+			return errors.New("transient")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+
+	ended := recorder.Ended()
+	if len(ended) != 3 {
+		t.Fatalf("expected 2 attempt spans and 1 parent span, got %d", len(ended))
+	}
+	parent := ended[2]
+	if parent.Name() != "fetch" {
+		t.Fatalf("unexpected parent span name %q", parent.Name())
+	}
+	if !spanHasAttribute(parent.Attributes(), RetryAttemptsKey, "2") {
+		t.Fatalf("expected retry.attempts=2, got %v", parent.Attributes())
+	}
+	if len(parent.Events()) != 1 || parent.Events()[0].Name != "retry.backoff" {
+		t.Fatalf("expected one backoff event, got %v", parent.Events())
+	}
+	if ended[0].Status().Code != codes.Error || ended[1].Status().Code == codes.Error {
+		t.Fatalf("expected only the first attempt to fail")
+	}
+	if ended[0].Parent().SpanID() != parent.SpanContext().SpanID() {
+		t.Fatalf("expected attempt span to be a child of the retry span")
+	}
+}
+
+func TestRetryStopsOnNonRetryableError(t *testing.T) {
+	permanent := errors.New("permanent")
+	calls := 0
+	err := Retry(context.Background(), RetryPolicy{
+		MaxAttempts: 5,
+		Retryable:   func(err error) bool { return !errors.Is(err, permanent) },
+	}, func(context.Context, int) error {
+		calls++
+		return permanent
+	})
+	if !errors.Is(err, permanent) {
+		t.Fatalf("expected permanent error, got %v", err)
+	}
+	if calls != 1 {
+		t.Fatalf("expected a single attempt, got %d", calls)
+	}
+}
+
+func TestRetryReportsContextDeadline(t *testing.T) {
+	failed := errors.New("unavailable")
+	for _, attempts := range []int{1, 3} {
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		err := Retry(ctx, RetryPolicy{MaxAttempts: attempts, InitialBackoff: time.Millisecond}, func(ctx context.Context, _ int) error {
+			<-ctx.Done()
+			return failed
+		})
+		cancel()
+		if !errors.Is(err, context.DeadlineExceeded) || !errors.Is(err, failed) {
+			t.Fatalf("attempts=%d: expected the deadline and the last error, got %v", attempts, err)
+		}
+	}
+}
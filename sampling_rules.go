@@ -0,0 +1,125 @@
+package otelx
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// SamplingRule samples the root spans it matches at Ratio instead of SamplingRatio. A rule
+// matches when every matcher that is set matches; child spans follow their parent's decision.
+type SamplingRule struct {
+	// SpanName matches the span name exactly, e.g. "POST /checkout".
+	SpanName string `json:"spanName"`
+	// SpanNameRegex matches the span name against a regular expression, e.g. "^GET /healthz".
+	SpanNameRegex string `json:"spanNameRegex"`
+	// Attributes match attributes passed when starting the span, compared as strings.
+	Attributes map[string]string `json:"attributes"`
+	// Baggage matches W3C baggage entries in the span's context, e.g. {"tenant": "canary"} to trace
+	// a canary tenant at 100%. Baggage usually arrives with the request, so it also matches on the
+	// service that starts the trace.
+	Baggage map[string]string `json:"baggage"`
+	Ratio   float64           `json:"ratio"`
+}
+
+func (r SamplingRule) validate() error {
+	if r.SpanName == "" && r.SpanNameRegex == "" && len(r.Attributes) == 0 && len(r.Baggage) == 0 {
+		return errors.New("spanName, spanNameRegex, attributes or baggage is required")
+	}
+	if r.Ratio < 0 || r.Ratio > 1 {
+		return fmt.Errorf("ratio must be within [0,1], got %v", r.Ratio)
+	}
+	if r.SpanNameRegex != "" {
+		if _, err := regexp.Compile(r.SpanNameRegex); err != nil {
+			return fmt.Errorf("spanNameRegex: %w", err)
+		}
+	}
+	return nil
+}
+
+type compiledSamplingRule struct {
+	name    string
+	pattern *regexp.Regexp
+	attrs   map[attribute.Key]string
+	baggage map[string]string
+	sampler sdktrace.Sampler
+}
+
+func (r compiledSamplingRule) matches(p sdktrace.SamplingParameters) bool {
+	if r.name != "" && p.Name != r.name {
+		return false
+	}
+	if r.pattern != nil && !r.pattern.MatchString(p.Name) {
+		return false
+	}
+	if len(r.baggage) > 0 {
+		if p.ParentContext == nil {
+			return false
+		}
+		bag := baggage.FromContext(p.ParentContext)
+		for k, want := range r.baggage {
+			if m := bag.Member(k); m.Key() == "" || m.Value() != want {
+				return false
+			}
+		}
+	}
+	if len(r.attrs) == 0 {
+		return true
+	}
+	found := 0
+	for _, kv := range p.Attributes {
+		if want, ok := r.attrs[kv.Key]; ok {
+			if kv.Value.Emit() != want {
+				return false
+			}
+			found++
+		}
+	}
+	return found == len(r.attrs)
+}
+
+// ruleSampler checks rules in order and uses the first match's ratio; spans matching no rule go
+// to fallback, the SamplingRatio sampler.
+type ruleSampler struct {
+	rules    []compiledSamplingRule
+	fallback sdktrace.Sampler
+}
+
+// newRuleSampler compiles validated rules; it returns fallback unchanged when there are none.
+func newRuleSampler(rules []SamplingRule, fallback sdktrace.Sampler) sdktrace.Sampler {
+	if len(rules) == 0 {
+		return fallback
+	}
+	compiled := make([]compiledSamplingRule, len(rules))
+	for i, rule := range rules {
+		c := compiledSamplingRule{name: rule.SpanName, baggage: rule.Baggage, sampler: sdktrace.TraceIDRatioBased(rule.Ratio)}
+		if rule.SpanNameRegex != "" {
+			c.pattern = regexp.MustCompile(rule.SpanNameRegex)
+		}
+		if len(rule.Attributes) > 0 {
+			c.attrs = make(map[attribute.Key]string, len(rule.Attributes))
+			for k, v := range rule.Attributes {
+				c.attrs[attribute.Key(k)] = v
+			}
+		}
+		compiled[i] = c
+	}
+	return &ruleSampler{rules: compiled, fallback: fallback}
+}
+
+func (s *ruleSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	for _, rule := range s.rules {
+		if rule.matches(p) {
+			return rule.sampler.ShouldSample(p)
+		}
+	}
+	return s.fallback.ShouldSample(p)
+}
+
+func (s *ruleSampler) Description() string {
+	return fmt.Sprintf("RuleBased{rules=%d,default=%s}", len(s.rules), s.fallback.Description())
+}
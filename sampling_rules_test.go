@@ -0,0 +1,107 @@
+package otelx
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestSamplingRules(t *testing.T) {
+	cfg := Config{
+		ServiceName:   "svc",
+		Exporter:      ExporterMemory,
+		SamplingRatio: Float64(0),
+		SamplingRules: []SamplingRule{
+			{SpanNameRegex: "^GET /healthz", Ratio: 0},
+			{SpanName: "POST /checkout", Ratio: 1},
+			{Attributes: map[string]string{"tenant.tier": "gold"}, Ratio: 1},
+		},
+	}
+	prov, err := Setup(context.Background(), cfg, nil)
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	defer prov.Shutdown(context.Background())
+
+	tracer := prov.TP.Tracer("test")
+	start := func(name string, opts ...trace.SpanStartOption) {
+		ctx, span := tracer.Start(context.Background(), name, opts...)
+		_, child := tracer.Start(ctx, name+"/child")
+		child.End()
+		span.End()
+	}
+	start("GET /healthz")
+	start("POST /checkout")
+	start("GET /videos", trace.WithAttributes(attribute.String("tenant.tier", "gold")))
+	start("GET /videos", trace.WithAttributes(attribute.String("tenant.tier", "free")))
+
+	var names []string
+	for _, span := range prov.RecordedSpans() {
+		names = append(names, span.Name)
+	}
+	if got := strings.Join(names, ","); got != "POST /checkout/child,POST /checkout,GET /videos/child,GET /videos" {
+		t.Fatalf("unexpected sampled spans: %s", got)
+	}
+
+	if got := prov.Describe().Sampler; !strings.Contains(got, "RuleBased{rules=3,default=TraceIDRatioBased{0}}") {
+		t.Fatalf("expected rule sampler in description, got %q", got)
+	}
+	if err := prov.SetSamplingRatio(1); err != nil {
+		t.Fatalf("set ratio failed: %v", err)
+	}
+	if got := prov.Describe().Sampler; !strings.Contains(got, "default=AlwaysOnSampler") {
+		t.Fatalf("expected SetSamplingRatio to change the default, got %q", got)
+	}
+}
+
+func TestSamplingRulesMatchBaggage(t *testing.T) {
+	cfg := Config{
+		ServiceName:   "svc",
+		Exporter:      ExporterMemory,
+		SamplingRatio: Float64(0),
+		SamplingRules: []SamplingRule{{Baggage: map[string]string{"tenant": "canary"}, Ratio: 1}},
+	}
+	prov, err := Setup(context.Background(), cfg, nil)
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	defer prov.Shutdown(context.Background())
+
+	start := func(tenant string) {
+		ctx := context.Background()
+		if tenant != "" {
+			m, _ := baggage.NewMember("tenant", tenant)
+			bag, _ := baggage.New(m)
+			ctx = baggage.ContextWithBaggage(ctx, bag)
+		}
+		_, span := prov.TP.Tracer("test").Start(ctx, tenant)
+		span.End()
+	}
+	start("")
+	start("stable")
+	start("canary")
+
+	spans := prov.RecordedSpans()
+	if len(spans) != 1 || spans[0].Name != "canary" {
+		t.Fatalf("expected only the canary tenant to be sampled, got %v", spans)
+	}
+}
+
+func TestSamplingRulesValidation(t *testing.T) {
+	cases := map[string]SamplingRule{
+		"spanName, spanNameRegex, attributes or baggage is required": {Ratio: 1},
+		"ratio must be within [0,1]":                                 {SpanName: "op", Ratio: 2},
+		"spanNameRegex":                                              {SpanNameRegex: "(", Ratio: 1},
+	}
+	for want, rule := range cases {
+		cfg := Config{ServiceName: "svc", Exporter: ExporterMemory, SamplingRules: []SamplingRule{rule}}
+		_, err := Setup(context.Background(), cfg, nil)
+		if err == nil || !strings.Contains(err.Error(), "otelx: samplingRules[0]: "+want) {
+			t.Fatalf("expected %q error, got %v", want, err)
+		}
+	}
+}
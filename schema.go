@@ -0,0 +1,211 @@
+package otelx
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	logx "github.com/bionicotaku/lingo-utils-logx"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// SchemaMode selects what happens when a span breaks its registered AttributeSchema.
+type SchemaMode string
+
+const (
+	// SchemaOff disables validation.
+	SchemaOff SchemaMode = ""
+	// SchemaLog logs each violation as otelx.schema.violation.
+	SchemaLog SchemaMode = "log"
+	// SchemaFail logs and then panics inside span.End, so tests fail at the offending call site.
+	SchemaFail SchemaMode = "fail"
+)
+
+// SchemaAnySpan registers attributes shared by every span that has a schema.
+const SchemaAnySpan = "*"
+
+// AttributeSchema declares the attributes expected on spans named SpanName. Attributes maps each
+// key to its type: string, bool, int64, float64, their slice forms (string[], ...) or any.
+// Spans without a schema are not validated.
+type AttributeSchema struct {
+	SpanName   string            `json:"spanName"`
+	Attributes map[string]string `json:"attributes"`
+	// Required keys must be present on every matching span.
+	Required []string `json:"required"`
+	// AllowUnknown stops keys missing from Attributes from being reported.
+	AllowUnknown bool `json:"allowUnknown"`
+}
+
+// SchemaViolation describes one attribute that does not match its schema.
+type SchemaViolation struct {
+	SpanName string
+	Key      string
+	// Problem is "unknown", "type" or "missing".
+	Problem  string
+	Expected string
+	Got      string
+}
+
+func (v SchemaViolation) String() string {
+	switch v.Problem {
+	case "type":
+		return fmt.Sprintf("span %q: attribute %q has type %s, want %s", v.SpanName, v.Key, v.Got, v.Expected)
+	case "missing":
+		return fmt.Sprintf("span %q: required attribute %q is missing", v.SpanName, v.Key)
+	default:
+		return fmt.Sprintf("span %q: unknown attribute %q", v.SpanName, v.Key)
+	}
+}
+
+var schemaTypes = map[string]attribute.Type{
+	"string":    attribute.STRING,
+	"bool":      attribute.BOOL,
+	"int64":     attribute.INT64,
+	"int":       attribute.INT64,
+	"float64":   attribute.FLOAT64,
+	"float":     attribute.FLOAT64,
+	"string[]":  attribute.STRINGSLICE,
+	"bool[]":    attribute.BOOLSLICE,
+	"int64[]":   attribute.INT64SLICE,
+	"float64[]": attribute.FLOAT64SLICE,
+	"any":       attribute.INVALID,
+}
+
+func (s AttributeSchema) validate() error {
+	if strings.TrimSpace(s.SpanName) == "" {
+		return fmt.Errorf("spanName is required")
+	}
+	for key, typ := range s.Attributes {
+		if _, ok := schemaTypes[strings.ToLower(typ)]; !ok {
+			return fmt.Errorf("attribute %q has unsupported type %q", key, typ)
+		}
+	}
+	return nil
+}
+
+// compiledSchema is an AttributeSchema merged with the SchemaAnySpan schema.
+type compiledSchema struct {
+	types        map[attribute.Key]attribute.Type
+	required     []attribute.Key
+	allowUnknown bool
+}
+
+// schemaProcessor validates ended spans against their schema.
+type schemaProcessor struct {
+	mode      SchemaMode
+	schemas   map[string]*compiledSchema
+	onViolate func(SchemaViolation)
+	logger    logx.Logger
+}
+
+func newSchemaProcessor(mode SchemaMode, schemas []AttributeSchema, onViolate func(SchemaViolation), logger logx.Logger) *schemaProcessor {
+	var shared *AttributeSchema
+	byName := map[string][]AttributeSchema{}
+	for i, s := range schemas {
+		if s.SpanName == SchemaAnySpan {
+			shared = &schemas[i]
+			continue
+		}
+		byName[s.SpanName] = append(byName[s.SpanName], s)
+	}
+	compiled := make(map[string]*compiledSchema, len(byName))
+	for name, list := range byName {
+		c := &compiledSchema{types: map[attribute.Key]attribute.Type{}}
+		if shared != nil {
+			list = append([]AttributeSchema{*shared}, list...)
+		}
+		for _, s := range list {
+			for key, typ := range s.Attributes {
+				c.types[attribute.Key(key)] = schemaTypes[strings.ToLower(typ)]
+			}
+			for _, key := range s.Required {
+				c.required = append(c.required, attribute.Key(key))
+			}
+			c.allowUnknown = c.allowUnknown || s.AllowUnknown
+		}
+		compiled[name] = c
+	}
+	return &schemaProcessor{mode: mode, schemas: compiled, onViolate: onViolate, logger: logger}
+}
+
+func (p *schemaProcessor) OnStart(context.Context, sdktrace.ReadWriteSpan) {}
+
+func (p *schemaProcessor) OnEnd(span sdktrace.ReadOnlySpan) {
+	violations := p.check(span)
+	if len(violations) == 0 {
+		return
+	}
+	for _, v := range violations {
+		if p.logger != nil {
+			p.logger.Warn(context.Background(), "otelx.schema.violation",
+				logx.String("span", v.SpanName),
+				logx.String("key", v.Key),
+				logx.String("problem", v.Problem),
+				logx.String("expected", v.Expected),
+				logx.String("got", v.Got),
+			)
+		}
+		if p.onViolate != nil {
+			p.onViolate(v)
+		}
+	}
+	if p.mode == SchemaFail {
+		panic("otelx: attribute schema violation: " + violations[0].String())
+	}
+}
+
+func (p *schemaProcessor) check(span sdktrace.ReadOnlySpan) []SchemaViolation {
+	schema, ok := p.schemas[span.Name()]
+	if !ok {
+		return nil
+	}
+	var (
+		violations []SchemaViolation
+		seen       = map[attribute.Key]bool{}
+	)
+	for _, kv := range span.Attributes() {
+		seen[kv.Key] = true
+		want, known := schema.types[kv.Key]
+		switch {
+		case !known && !schema.allowUnknown:
+			violations = append(violations, SchemaViolation{SpanName: span.Name(), Key: string(kv.Key), Problem: "unknown", Got: schemaTypeName(kv.Value.Type())})
+		case known && want != attribute.INVALID && want != kv.Value.Type():
+			violations = append(violations, SchemaViolation{SpanName: span.Name(), Key: string(kv.Key), Problem: "type", Expected: schemaTypeName(want), Got: schemaTypeName(kv.Value.Type())})
+		}
+	}
+	for _, key := range schema.required {
+		if !seen[key] {
+			violations = append(violations, SchemaViolation{SpanName: span.Name(), Key: string(key), Problem: "missing", Expected: schemaTypeName(schema.types[key])})
+		}
+	}
+	sort.SliceStable(violations, func(i, j int) bool { return violations[i].Key < violations[j].Key })
+	return violations
+}
+
+func (p *schemaProcessor) Shutdown(context.Context) error   { return nil }
+func (p *schemaProcessor) ForceFlush(context.Context) error { return nil }
+
+func schemaTypeName(t attribute.Type) string {
+	switch t {
+	case attribute.STRING:
+		return "string"
+	case attribute.BOOL:
+		return "bool"
+	case attribute.INT64:
+		return "int64"
+	case attribute.FLOAT64:
+		return "float64"
+	case attribute.STRINGSLICE:
+		return "string[]"
+	case attribute.BOOLSLICE:
+		return "bool[]"
+	case attribute.INT64SLICE:
+		return "int64[]"
+	case attribute.FLOAT64SLICE:
+		return "float64[]"
+	default:
+		return "any"
+	}
+}
@@ -0,0 +1,89 @@
+package otelx
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func TestSchemaValidationReportsViolations(t *testing.T) {
+	rec := &recordingLogger{}
+	var violations []SchemaViolation
+	cfg := Config{
+		ServiceName:      "svc",
+		Exporter:         ExporterMemory,
+		SamplingRatio:    Float64(1),
+		SchemaValidation: SchemaLog,
+		AttributeSchemas: []AttributeSchema{
+			{SpanName: SchemaAnySpan, Attributes: map[string]string{"enduser.id": "string"}},
+			{SpanName: "checkout", Attributes: map[string]string{"cart.items": "int64", "cart.id": "string"}, Required: []string{"cart.id"}},
+		},
+	}
+	prov, err := Setup(context.Background(), cfg, rec,
+		WithSchemaViolationHandler(func(v SchemaViolation) { violations = append(violations, v) }))
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	defer prov.Shutdown(context.Background())
+
+	tracer := prov.TP.Tracer("test")
+	_, span := tracer.Start(context.Background(), "checkout")
+	span.SetAttributes(
+		attribute.String("cart.items", "3"),
+		attribute.String("enduser.id", "u-1"),
+		attribute.Bool("debug", true),
+	)
+	span.End()
+	_, other := tracer.Start(context.Background(), "unregistered")
+	other.SetAttributes(attribute.Bool("anything", true))
+	other.End()
+
+	want := []SchemaViolation{
+		{SpanName: "checkout", Key: "cart.id", Problem: "missing", Expected: "string"},
+		{SpanName: "checkout", Key: "cart.items", Problem: "type", Expected: "int64", Got: "string"},
+		{SpanName: "checkout", Key: "debug", Problem: "unknown", Got: "bool"},
+	}
+	if !slices.Equal(violations, want) {
+		t.Fatalf("unexpected violations:\n got %+v\nwant %+v", violations, want)
+	}
+	if n := len(slices.DeleteFunc(rec.Entries(), func(e string) bool { return e != "warn:otelx.schema.violation" })); n != 3 {
+		t.Fatalf("expected a warning per violation, got %d", n)
+	}
+}
+
+func TestSchemaValidationFailModePanics(t *testing.T) {
+	cfg := Config{ServiceName: "svc", Exporter: ExporterMemory, SamplingRatio: Float64(1), SchemaValidation: SchemaFail}
+	prov, err := Setup(context.Background(), cfg, nil,
+		WithAttributeSchemas(AttributeSchema{SpanName: "op", Attributes: map[string]string{"id": "any"}}))
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	defer prov.Shutdown(context.Background())
+
+	_, ok := prov.TP.Tracer("test").Start(context.Background(), "op")
+	ok.SetAttributes(attribute.Int("id", 1))
+	ok.End()
+
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("expected span.End to panic on violation")
+		}
+	}()
+	_, bad := prov.TP.Tracer("test").Start(context.Background(), "op")
+	bad.SetAttributes(attribute.Int("unexpected", 1))
+	bad.End()
+}
+
+func TestSchemaValidationConfig(t *testing.T) {
+	for name, cfg := range map[string]Config{
+		"mode": {ServiceName: "svc", SchemaValidation: "strict"},
+		"type": {ServiceName: "svc", AttributeSchemas: []AttributeSchema{{SpanName: "op", Attributes: map[string]string{"k": "uuid"}}}},
+		"name": {ServiceName: "svc", AttributeSchemas: []AttributeSchema{{Attributes: map[string]string{"k": "string"}}}},
+	} {
+		if _, err := Setup(context.Background(), cfg, nil); err == nil {
+			t.Fatalf("%s: expected validation error", name)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package otelx
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	logx "github.com/bionicotaku/lingo-utils-logx"
+	"github.com/go-logr/logr"
+)
+
+// SDK log levels accepted by Config.SDKLogLevel, mapped to the logr verbosity used by the otel SDK.
+var sdkLogLevels = map[string]int{
+	"error": -1,
+	"warn":  1,
+	"info":  4,
+	"debug": 8,
+}
+
+// newSDKLogger returns a logr.Logger for otel.SetLogger that forwards SDK messages up to the given
+// level to logger: SDK errors become logx errors, warnings logx warnings and everything else debug.
+func newSDKLogger(logger logx.Logger, level string) logr.Logger {
+	return logr.New(&logxSink{logger: logger, verbosity: sdkLogLevels[level]})
+}
+
+type logxSink struct {
+	logger    logx.Logger
+	verbosity int
+	name      string
+	values    []logx.Attr
+}
+
+func (s *logxSink) Init(logr.RuntimeInfo) {}
+
+func (s *logxSink) Enabled(level int) bool {
+	return level <= s.verbosity
+}
+
+func (s *logxSink) Info(level int, msg string, keysAndValues ...any) {
+	attrs := s.attrs(keysAndValues)
+	if level <= sdkLogLevels["warn"] {
+		s.logger.Warn(context.Background(), msg, attrs...)
+		return
+	}
+	s.logger.Debug(context.Background(), msg, attrs...)
+}
+
+func (s *logxSink) Error(err error, msg string, keysAndValues ...any) {
+	s.logger.Error(context.Background(), msg, err, s.attrs(keysAndValues)...)
+}
+
+func (s *logxSink) WithValues(keysAndValues ...any) logr.LogSink {
+	clone := *s
+	clone.values = append(append([]logx.Attr{}, s.values...), kvAttrs(keysAndValues)...)
+	return &clone
+}
+
+func (s *logxSink) WithName(name string) logr.LogSink {
+	clone := *s
+	if clone.name != "" {
+		name = clone.name + "/" + name
+	}
+	clone.name = name
+	return &clone
+}
+
+func (s *logxSink) attrs(keysAndValues []any) []logx.Attr {
+	attrs := make([]logx.Attr, 0, len(s.values)+len(keysAndValues)/2+1)
+	attrs = append(attrs, logx.String("source", "otel.sdk"))
+	if s.name != "" {
+		attrs = append(attrs, logx.String("logger", s.name))
+	}
+	attrs = append(attrs, s.values...)
+	return append(attrs, kvAttrs(keysAndValues)...)
+}
+
+func kvAttrs(keysAndValues []any) []logx.Attr {
+	attrs := make([]logx.Attr, 0, len(keysAndValues)/2)
+	for i := 0; i+1 < len(keysAndValues); i += 2 {
+		attrs = append(attrs, logx.Any(fmt.Sprint(keysAndValues[i]), keysAndValues[i+1]))
+	}
+	return attrs
+}
+
+func validSDKLogLevel(level string) bool {
+	_, ok := sdkLogLevels[strings.ToLower(level)]
+	return ok
+}
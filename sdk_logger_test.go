@@ -0,0 +1,67 @@
+package otelx
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	logx "github.com/bionicotaku/lingo-utils-logx"
+)
+
+func TestSDKLoggerRespectsLevel(t *testing.T) {
+	rec := &recordingLogger{}
+	log := newSDKLogger(rec, "warn")
+
+	log.Error(errors.New("boom"), "export failed")
+	log.V(1).Info("queue full")
+	log.V(4).Info("info detail")
+	log.V(8).Info("debug detail")
+
+	got := rec.Entries()
+	want := []string{"error:export failed", "warn:queue full"}
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("expected %v, got %v", want, got)
+		}
+	}
+}
+
+func TestSetupRejectsUnknownSDKLogLevel(t *testing.T) {
+	if _, err := Setup(context.Background(), Config{ServiceName: "svc", SDKLogLevel: "verbose"}, nil); err == nil {
+		t.Fatalf("expected error for unknown sdk log level")
+	}
+}
+
+type recordingLogger struct {
+	mu      sync.Mutex
+	entries []string
+}
+
+func (l *recordingLogger) record(level, msg string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries = append(l.entries, level+":"+msg)
+}
+
+func (l *recordingLogger) Entries() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]string(nil), l.entries...)
+}
+
+func (l *recordingLogger) Debug(_ context.Context, msg string, _ ...logx.Attr) {
+	l.record("debug", msg)
+}
+func (l *recordingLogger) Info(_ context.Context, msg string, _ ...logx.Attr) { l.record("info", msg) }
+func (l *recordingLogger) Warn(_ context.Context, msg string, _ ...logx.Attr) { l.record("warn", msg) }
+func (l *recordingLogger) Error(_ context.Context, msg string, _ error, _ ...logx.Attr) {
+	l.record("error", msg)
+}
+func (l *recordingLogger) Fatal(_ context.Context, msg string, _ error, _ ...logx.Attr) {
+	l.record("fatal", msg)
+}
+func (l *recordingLogger) With(...logx.Attr) logx.Logger { return l }
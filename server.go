@@ -0,0 +1,111 @@
+package otelx
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+)
+
+// HTTPConnectionStateKey distinguishes active and idle connections on the open-connection metric.
+const HTTPConnectionStateKey = attribute.Key("http.connection.state")
+
+// serverFlushTimeout bounds the span flush triggered by http.Server.Shutdown.
+const serverFlushTimeout = 5 * time.Second
+
+// WrapServer instruments a whole http.Server in one call: the Handler (http.DefaultServeMux when
+// nil) is wrapped like HTTPHandler, ConnState feeds an http.server.open_connections up-down counter
+// split by http.connection.state (active|idle), and Shutdown force-flushes the global
+// TracerProvider so spans of the final requests are not lost. An existing ConnState hook keeps
+// running. WrapServer returns srv and must be called before the server starts.
+func WrapServer(srv *http.Server, operation string, opts ...otelhttp.Option) *http.Server {
+	handler := srv.Handler
+	if handler == nil {
+		handler = http.DefaultServeMux
+	}
+	srv.Handler = HTTPHandler(operation, handler, opts...)
+
+	tracker := newConnTracker()
+	next := srv.ConnState
+	srv.ConnState = func(conn net.Conn, state http.ConnState) {
+		tracker.track(conn, state)
+		if next != nil {
+			next(conn, state)
+		}
+	}
+
+	srv.RegisterOnShutdown(func() {
+		flusher, ok := otel.GetTracerProvider().(interface{ ForceFlush(context.Context) error })
+		if !ok {
+			return
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), serverFlushTimeout)
+		defer cancel()
+		_ = flusher.ForceFlush(ctx)
+	})
+	return srv
+}
+
+// connTracker keeps http.server.open_connections in sync with http.Server connection states.
+type connTracker struct {
+	open metric.Int64UpDownCounter
+
+	mu     sync.Mutex
+	states map[net.Conn]string
+}
+
+// connStateAttrs holds the attribute sets of the two connection states, so state changes do not
+// allocate.
+var connStateAttrs = map[string]AttributeSet{
+	"active": NewAttributeSet(HTTPConnectionStateKey.String("active")),
+	"idle":   NewAttributeSet(HTTPConnectionStateKey.String("idle")),
+}
+
+func newConnTracker() *connTracker {
+	var open metric.Int64UpDownCounter
+	open, err := otel.Meter(instrumentationName).Int64UpDownCounter("http.server.open_connections",
+		metric.WithDescription("Number of connections currently open on the server."),
+		metric.WithUnit("{connection}"),
+	)
+	if err != nil {
+		open = noop.Int64UpDownCounter{}
+	}
+	return &connTracker{open: open, states: map[net.Conn]string{}}
+}
+
+func (t *connTracker) track(conn net.Conn, state http.ConnState) {
+	var next string
+	switch state {
+	case http.StateActive:
+		next = "active"
+	case http.StateNew, http.StateIdle:
+		next = "idle"
+	}
+
+	t.mu.Lock()
+	prev, known := t.states[conn]
+	if next == "" {
+		delete(t.states, conn)
+	} else {
+		t.states[conn] = next
+	}
+	t.mu.Unlock()
+
+	if prev == next {
+		return
+	}
+	ctx := context.Background()
+	if known {
+		t.open.Add(ctx, -1, connStateAttrs[prev].Measurement())
+	}
+	if next != "" {
+		t.open.Add(ctx, 1, connStateAttrs[next].Measurement())
+	}
+}
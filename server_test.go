@@ -0,0 +1,93 @@
+package otelx
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestWrapServer(t *testing.T) {
+	defer saveGlobal()()
+	prevMP := otel.GetMeterProvider()
+	defer otel.SetMeterProvider(prevMP)
+
+	reader := sdkmetric.NewManualReader()
+	otel.SetMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+	mem := tracetest.NewInMemoryExporter()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithBatcher(mem, sdktrace.WithBatchTimeout(time.Hour))))
+
+	var hooked atomic.Bool
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, "ok")
+	}))
+	ts.Config.ConnState = func(_ net.Conn, _ http.ConnState) { hooked.Store(true) }
+	WrapServer(ts.Config, "server")
+	ts.Start()
+	defer ts.Close()
+
+	resp, err := ts.Client().Get(ts.URL)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	_, _ = io.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	if !hooked.Load() {
+		t.Fatalf("expected the existing ConnState hook to keep running")
+	}
+	waitFor(t, func() bool { return openConnections(t, reader, "idle") == 1 })
+
+	if err := ts.Config.Shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown failed: %v", err)
+	}
+	waitFor(t, func() bool { return len(mem.GetSpans()) == 1 })
+	if name := mem.GetSpans()[0].Name; name != "server" {
+		t.Fatalf("expected server span to be flushed on shutdown, got %q", name)
+	}
+}
+
+func openConnections(t *testing.T, reader *sdkmetric.ManualReader, state string) int64 {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("collect failed: %v", err)
+	}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name != "http.server.open_connections" {
+				continue
+			}
+			for _, dp := range m.Data.(metricdata.Sum[int64]).DataPoints {
+				if dp.Attributes.Equals(ptrSet(attribute.NewSet(HTTPConnectionStateKey.String(state)))) {
+					return dp.Value
+				}
+			}
+		}
+	}
+	return 0
+}
+
+func ptrSet(s attribute.Set) *attribute.Set { return &s }
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("condition not met in time")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
@@ -0,0 +1,100 @@
+package otelx
+
+import (
+	"context"
+	"sync/atomic"
+
+	logx "github.com/bionicotaku/lingo-utils-logx"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ShadowStats compares the decisions of the configured sampler with the shadow sampler registered
+// through WithShadowSampler. Only root spans are counted, so every decision stands for one trace.
+type ShadowStats struct {
+	Traces      int64 `json:"traces"`
+	BothSampled int64 `json:"bothSampled"`
+	// PrimaryOnly counts traces the live sampler kept and the shadow sampler would drop.
+	PrimaryOnly int64 `json:"primaryOnly"`
+	// ShadowOnly counts traces the shadow sampler would keep but the live sampler dropped.
+	ShadowOnly int64 `json:"shadowOnly"`
+}
+
+// Agreement returns the fraction of traces on which both samplers made the same decision.
+func (s ShadowStats) Agreement() float64 {
+	if s.Traces == 0 {
+		return 1
+	}
+	return float64(s.Traces-s.PrimaryOnly-s.ShadowOnly) / float64(s.Traces)
+}
+
+// shadowSampler returns the primary decision and records what the shadow sampler would have done.
+type shadowSampler struct {
+	sdktrace.Sampler
+	shadow sdktrace.Sampler
+
+	traces      atomic.Int64
+	bothSampled atomic.Int64
+	primaryOnly atomic.Int64
+	shadowOnly  atomic.Int64
+}
+
+func newShadowSampler(primary, shadow sdktrace.Sampler) *shadowSampler {
+	return &shadowSampler{Sampler: primary, shadow: shadow}
+}
+
+func (s *shadowSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	result := s.Sampler.ShouldSample(p)
+	if trace.SpanContextFromContext(p.ParentContext).IsValid() || p.ParentContext.Value(canaryContextKey{}) != nil {
+		return result
+	}
+	primary := result.Decision == sdktrace.RecordAndSample
+	shadow := s.shadow.ShouldSample(p).Decision == sdktrace.RecordAndSample
+	s.traces.Add(1)
+	switch {
+	case primary && shadow:
+		s.bothSampled.Add(1)
+	case primary:
+		s.primaryOnly.Add(1)
+	case shadow:
+		s.shadowOnly.Add(1)
+	}
+	return result
+}
+
+func (s *shadowSampler) Description() string {
+	return s.Sampler.Description() + "{shadow:" + s.shadow.Description() + "}"
+}
+
+func (s *shadowSampler) stats() ShadowStats {
+	return ShadowStats{
+		Traces:      s.traces.Load(),
+		BothSampled: s.bothSampled.Load(),
+		PrimaryOnly: s.primaryOnly.Load(),
+		ShadowOnly:  s.shadowOnly.Load(),
+	}
+}
+
+func (s *shadowSampler) logSummary(ctx context.Context, logger logx.Logger) {
+	if logger == nil {
+		return
+	}
+	stats := s.stats()
+	logger.Info(ctx, "otelx.sampler.shadow.summary",
+		logx.String("shadow", s.shadow.Description()),
+		logx.Any("traces", stats.Traces),
+		logx.Any("bothSampled", stats.BothSampled),
+		logx.Any("primaryOnly", stats.PrimaryOnly),
+		logx.Any("shadowOnly", stats.ShadowOnly),
+		logx.Any("agreement", stats.Agreement()),
+	)
+}
+
+// ShadowStats reports how the shadow sampler registered with WithShadowSampler compares with the
+// live sampler so far. It returns zero stats when no shadow sampler is configured.
+func (p *Provider) ShadowStats() ShadowStats {
+	if p == nil || p.shadow == nil {
+		return ShadowStats{}
+	}
+	return p.shadow.stats()
+}
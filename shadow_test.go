@@ -0,0 +1,55 @@
+package otelx
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func TestShadowSamplerComparesDecisions(t *testing.T) {
+	logger := &recordingLogger{}
+	cfg := Config{ServiceName: "svc", Exporter: ExporterMemory, SamplingRatio: Float64(1)}
+	prov, err := Setup(context.Background(), cfg, logger, WithShadowSampler(sdktrace.NeverSample()))
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+
+	tracer := prov.TP.Tracer("test")
+	for range 4 {
+		ctx, root := tracer.Start(context.Background(), "root")
+		_, child := tracer.Start(ctx, "child")
+		child.End()
+		root.End()
+	}
+
+	stats := prov.ShadowStats()
+	if stats.Traces != 4 || stats.PrimaryOnly != 4 || stats.ShadowOnly != 0 || stats.BothSampled != 0 {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+	if stats.Agreement() != 0 {
+		t.Fatalf("expected no agreement, got %v", stats.Agreement())
+	}
+	if len(prov.RecordedSpans()) != 8 {
+		t.Fatalf("expected the shadow sampler not to affect recording")
+	}
+
+	if err := prov.Shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown failed: %v", err)
+	}
+	if !slices.Contains(logger.Entries(), "info:otelx.sampler.shadow.summary") {
+		t.Fatalf("expected shadow summary on shutdown, got %v", logger.Entries())
+	}
+}
+
+func TestShadowStatsWithoutShadowSampler(t *testing.T) {
+	prov, err := Setup(context.Background(), Config{ServiceName: "svc", Exporter: ExporterMemory}, nil)
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	defer prov.Shutdown(context.Background())
+	if stats := prov.ShadowStats(); stats != (ShadowStats{}) || stats.Agreement() != 1 {
+		t.Fatalf("expected zero stats, got %+v", stats)
+	}
+}
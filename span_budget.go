@@ -0,0 +1,158 @@
+package otelx
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// SpanBudgetExceededEvent is added to the local root span of a request that started more
+	// child spans than Config.MaxSpansPerRequest.
+	SpanBudgetExceededEvent = "otelx.span_budget.exceeded"
+	// SpanBudgetLimitKey and SpanBudgetDroppedKey describe the budget and the spans it dropped.
+	SpanBudgetLimitKey   = attribute.Key("otelx.span_budget.limit")
+	SpanBudgetDroppedKey = attribute.Key("otelx.span_budget.dropped")
+
+	// maxBudgetRequests bounds the requests tracked at once; further requests are not limited.
+	maxBudgetRequests = 65536
+)
+
+type budgetEntry struct {
+	roots    int
+	children int
+	dropped  int
+	first    time.Time
+}
+
+// spanBudget caps the child spans started under each local root span (a request). Its sampler
+// drops spans past the cap, and its processor, wrapping the export processors, reports the drops
+// as one SpanBudgetExceededEvent on the local root when it ends.
+type spanBudget struct {
+	limit int
+	next  []sdktrace.SpanProcessor
+
+	mu       sync.Mutex
+	requests map[trace.TraceID]*budgetEntry
+}
+
+func newSpanBudget(limit int) *spanBudget {
+	return &spanBudget{limit: limit, requests: map[trace.TraceID]*budgetEntry{}}
+}
+
+// processor returns the budget as a span processor in front of the export processors next.
+func (b *spanBudget) processor(next []sdktrace.SpanProcessor) sdktrace.SpanProcessor {
+	b.next = next
+	return b
+}
+
+// isLocalRoot reports whether a span with parent starts a request in this process.
+func isLocalRoot(parent trace.SpanContext) bool {
+	return !parent.IsValid() || parent.IsRemote()
+}
+
+// sampler wraps next so spans past the budget are dropped before they are recorded.
+func (b *spanBudget) sampler(next sdktrace.Sampler) sdktrace.Sampler {
+	return budgetSampler{Sampler: next, budget: b}
+}
+
+type budgetSampler struct {
+	sdktrace.Sampler
+	budget *spanBudget
+}
+
+func (s budgetSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	parent := trace.SpanContextFromContext(p.ParentContext)
+	if !isLocalRoot(parent) && s.budget.exceeded(p.TraceID) {
+		return sdktrace.SamplingResult{Decision: sdktrace.Drop, Tracestate: parent.TraceState()}
+	}
+	return s.Sampler.ShouldSample(p)
+}
+
+// exceeded counts a child span of traceID and reports whether it is past the budget.
+func (b *spanBudget) exceeded(traceID trace.TraceID) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	e := b.requests[traceID]
+	if e == nil {
+		return false
+	}
+	e.children++
+	if e.children <= b.limit {
+		return false
+	}
+	if e.dropped == 0 {
+		e.first = time.Now()
+	}
+	e.dropped++
+	return true
+}
+
+func (b *spanBudget) OnStart(ctx context.Context, s sdktrace.ReadWriteSpan) {
+	if isLocalRoot(s.Parent()) {
+		id := s.SpanContext().TraceID()
+		b.mu.Lock()
+		if e := b.requests[id]; e != nil {
+			e.roots++
+		} else if len(b.requests) < maxBudgetRequests {
+			b.requests[id] = &budgetEntry{roots: 1}
+		}
+		b.mu.Unlock()
+	}
+	for _, next := range b.next {
+		next.OnStart(ctx, s)
+	}
+}
+
+func (b *spanBudget) OnEnd(s sdktrace.ReadOnlySpan) {
+	var span sdktrace.ReadOnlySpan = s
+	if isLocalRoot(s.Parent()) {
+		id := s.SpanContext().TraceID()
+		b.mu.Lock()
+		var dropped int
+		var first time.Time
+		if e := b.requests[id]; e != nil {
+			if e.roots--; e.roots <= 0 {
+				delete(b.requests, id)
+				dropped, first = e.dropped, e.first
+			}
+		}
+		b.mu.Unlock()
+		if dropped > 0 {
+			o := override(s)
+			o.events = append(append([]sdktrace.Event(nil), s.Events()...), sdktrace.Event{
+				Name:       SpanBudgetExceededEvent,
+				Time:       first,
+				Attributes: []attribute.KeyValue{SpanBudgetLimitKey.Int(b.limit), SpanBudgetDroppedKey.Int(dropped)},
+			})
+			span = o
+		}
+	}
+	for _, next := range b.next {
+		next.OnEnd(span)
+	}
+}
+
+func (b *spanBudget) Shutdown(ctx context.Context) error {
+	var firstErr error
+	for _, next := range b.next {
+		if err := next.Shutdown(ctx); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func (b *spanBudget) ForceFlush(ctx context.Context) error {
+	var firstErr error
+	for _, next := range b.next {
+		if err := next.ForceFlush(ctx); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
@@ -0,0 +1,59 @@
+package otelx
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func TestMaxSpansPerRequestAggregatesOverflow(t *testing.T) {
+	cfg := Config{ServiceName: "svc", Exporter: ExporterMemory, SamplingRatio: Float64(1), MaxSpansPerRequest: 3}
+	prov, err := Setup(context.Background(), cfg, nil)
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	defer prov.Shutdown(context.Background())
+
+	tracer := prov.TP.Tracer("test")
+	request := func() {
+		ctx, root := tracer.Start(context.Background(), "request")
+		for range 10 {
+			_, span := tracer.Start(ctx, "db.query")
+			span.End()
+		}
+		root.End()
+	}
+	request()
+	request()
+
+	spans := prov.RecordedSpans()
+	if len(spans) != 8 {
+		t.Fatalf("expected 3 children and the root per request, got %d spans", len(spans))
+	}
+	for _, s := range spans {
+		if s.Name != "request" {
+			if len(s.Events) != 0 {
+				t.Fatalf("expected no budget event on child spans")
+			}
+			continue
+		}
+		if len(s.Events) != 1 || s.Events[0].Name != SpanBudgetExceededEvent {
+			t.Fatalf("expected one %s event on the root, got %v", SpanBudgetExceededEvent, s.Events)
+		}
+		attrs := attribute.NewSet(s.Events[0].Attributes...)
+		limit, _ := attrs.Value(SpanBudgetLimitKey)
+		dropped, _ := attrs.Value(SpanBudgetDroppedKey)
+		if limit.AsInt64() != 3 || dropped.AsInt64() != 7 {
+			t.Fatalf("expected limit=3 dropped=7, got limit=%d dropped=%d", limit.AsInt64(), dropped.AsInt64())
+		}
+	}
+}
+
+func TestMaxSpansPerRequestValidation(t *testing.T) {
+	cfg := Config{ServiceName: "svc", Exporter: ExporterMemory, MaxSpansPerRequest: -1}
+	if _, err := Setup(context.Background(), cfg, nil); err == nil || !strings.Contains(err.Error(), "maxSpansPerRequest") {
+		t.Fatalf("expected maxSpansPerRequest error, got %v", err)
+	}
+}
@@ -0,0 +1,123 @@
+package otelx
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// SpanDropRule drops ended spans it matches before they reach the batcher. A rule matches when
+// every matcher that is set matches. Only the matching spans are dropped; to discard whole traces
+// use SamplingRules or ControlPlane instead.
+type SpanDropRule struct {
+	// Name matches the span name with a glob where "*" matches any run of characters, including
+	// "/" and spaces, e.g. "GET /healthz" or "grpc.health.v1.Health/*".
+	Name string `json:"name"`
+	// Attributes match the span's attributes, compared as strings.
+	Attributes map[string]string `json:"attributes"`
+}
+
+func (r SpanDropRule) validate() error {
+	if r.Name == "" && len(r.Attributes) == 0 {
+		return errors.New("name or attributes is required")
+	}
+	return nil
+}
+
+type compiledDropRule struct {
+	name  *regexp.Regexp
+	attrs map[attribute.Key]string
+}
+
+func (r compiledDropRule) matches(s sdktrace.ReadOnlySpan) bool {
+	if r.name != nil && !r.name.MatchString(s.Name()) {
+		return false
+	}
+	if len(r.attrs) == 0 {
+		return true
+	}
+	found := 0
+	for _, kv := range s.Attributes() {
+		if want, ok := r.attrs[kv.Key]; ok {
+			if kv.Value.Emit() != want {
+				return false
+			}
+			found++
+		}
+	}
+	return found == len(r.attrs)
+}
+
+// globPattern compiles a glob with "*" wildcards into an anchored regular expression.
+func globPattern(glob string) *regexp.Regexp {
+	parts := strings.Split(glob, "*")
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+	return regexp.MustCompile("^" + strings.Join(parts, ".*") + "$")
+}
+
+// spanDropProcessor wraps the export processors and withholds spans matching Config.DropSpans.
+type spanDropProcessor struct {
+	rules []compiledDropRule
+	next  []sdktrace.SpanProcessor
+}
+
+func newSpanDropProcessor(rules []SpanDropRule, next []sdktrace.SpanProcessor) *spanDropProcessor {
+	p := &spanDropProcessor{rules: make([]compiledDropRule, len(rules)), next: next}
+	for i, rule := range rules {
+		var c compiledDropRule
+		if rule.Name != "" {
+			c.name = globPattern(rule.Name)
+		}
+		if len(rule.Attributes) > 0 {
+			c.attrs = make(map[attribute.Key]string, len(rule.Attributes))
+			for k, v := range rule.Attributes {
+				c.attrs[attribute.Key(k)] = v
+			}
+		}
+		p.rules[i] = c
+	}
+	return p
+}
+
+func (p *spanDropProcessor) OnStart(ctx context.Context, s sdktrace.ReadWriteSpan) {
+	for _, next := range p.next {
+		next.OnStart(ctx, s)
+	}
+}
+
+func (p *spanDropProcessor) OnEnd(s sdktrace.ReadOnlySpan) {
+	for _, rule := range p.rules {
+		if rule.matches(s) {
+			return
+		}
+	}
+	for _, next := range p.next {
+		next.OnEnd(s)
+	}
+}
+
+func (p *spanDropProcessor) Shutdown(ctx context.Context) error {
+	var firstErr error
+	for _, next := range p.next {
+		if err := next.Shutdown(ctx); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func (p *spanDropProcessor) ForceFlush(ctx context.Context) error {
+	var firstErr error
+	for _, next := range p.next {
+		if err := next.ForceFlush(ctx); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
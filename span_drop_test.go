@@ -0,0 +1,70 @@
+package otelx
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestDropSpans(t *testing.T) {
+	cfg := Config{
+		ServiceName:   "svc",
+		Exporter:      ExporterMemory,
+		SamplingRatio: Float64(1),
+		DropSpans: []SpanDropRule{
+			{Name: "GET /healthz"},
+			{Name: "grpc.health.v1.Health/*"},
+			{Attributes: map[string]string{"http.route": "/metrics", "http.response.status_code": "200"}},
+		},
+	}
+	prov, err := Setup(context.Background(), cfg, nil)
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	defer prov.Shutdown(context.Background())
+
+	tracer := prov.TP.Tracer("test")
+	for _, s := range []struct {
+		name  string
+		attrs []attribute.KeyValue
+	}{
+		{name: "GET /healthz"},
+		{name: "GET /healthz/deep"},
+		{name: "grpc.health.v1.Health/Check"},
+		{name: "GET", attrs: []attribute.KeyValue{attribute.String("http.route", "/metrics"), attribute.Int("http.response.status_code", 200)}},
+		{name: "GET", attrs: []attribute.KeyValue{attribute.String("http.route", "/metrics"), attribute.Int("http.response.status_code", 500)}},
+		{name: "GET", attrs: []attribute.KeyValue{attribute.String("http.route", "/metrics")}},
+	} {
+		_, span := tracer.Start(context.Background(), s.name, trace.WithAttributes(s.attrs...))
+		span.End()
+	}
+
+	var got []string
+	for _, s := range prov.RecordedSpans() {
+		set := attribute.NewSet(s.Attributes...)
+		got = append(got, s.Name+"|"+set.Encoded(attribute.DefaultEncoder()))
+	}
+	want := "GET /healthz/deep|,GET|http.response.status_code=500,http.route=/metrics,GET|http.route=/metrics"
+	if strings.Join(got, ",") != want {
+		t.Fatalf("expected %q, got %q", want, strings.Join(got, ","))
+	}
+
+	found := false
+	for _, p := range prov.Describe().Processors {
+		found = found || (p.Name == "dropSpans" && p.Phase == PhaseFilter.String())
+	}
+	if !found {
+		t.Fatalf("expected dropSpans in the description")
+	}
+}
+
+func TestDropSpansValidation(t *testing.T) {
+	cfg := Config{ServiceName: "svc", Exporter: ExporterMemory, DropSpans: []SpanDropRule{{Name: "GET /"}, {}}}
+	_, err := Setup(context.Background(), cfg, nil)
+	if err == nil || !strings.Contains(err.Error(), "otelx: dropSpans[1]: name or attributes is required") {
+		t.Fatalf("expected a validation error, got %v", err)
+	}
+}
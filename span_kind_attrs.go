@@ -0,0 +1,66 @@
+package otelx
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var spanKindNames = map[string]trace.SpanKind{
+	"internal": trace.SpanKindInternal,
+	"server":   trace.SpanKindServer,
+	"client":   trace.SpanKindClient,
+	"producer": trace.SpanKindProducer,
+	"consumer": trace.SpanKindConsumer,
+}
+
+// parseSpanKindAttributes converts Config.SpanKindAttributes into attributes per span kind.
+func parseSpanKindAttributes(byKind map[string]map[string]string) (map[trace.SpanKind][]attribute.KeyValue, error) {
+	out := make(map[trace.SpanKind][]attribute.KeyValue, len(byKind))
+	for name, attrs := range byKind {
+		kind, ok := spanKindNames[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			return nil, fmt.Errorf("unsupported span kind %q (supported: internal, server, client, producer, consumer)", name)
+		}
+		for k, v := range attrs {
+			if strings.TrimSpace(k) == "" {
+				return nil, fmt.Errorf("span kind %s has an empty attribute key", name)
+			}
+			out[kind] = append(out[kind], attribute.String(k, v))
+		}
+	}
+	return out, nil
+}
+
+// spanKindAttributeProcessor adds default attributes to spans of a given kind on start. Attributes
+// already set by the caller win.
+type spanKindAttributeProcessor struct {
+	defaults map[trace.SpanKind][]attribute.KeyValue
+}
+
+func newSpanKindAttributeProcessor(defaults map[trace.SpanKind][]attribute.KeyValue) sdktrace.SpanProcessor {
+	return &spanKindAttributeProcessor{defaults: defaults}
+}
+
+func (p *spanKindAttributeProcessor) OnStart(_ context.Context, span sdktrace.ReadWriteSpan) {
+	defaults := p.defaults[span.SpanKind()]
+	if len(defaults) == 0 {
+		return
+	}
+	set := attribute.NewSet(span.Attributes()...)
+	for _, kv := range defaults {
+		if !set.HasValue(kv.Key) {
+			span.SetAttributes(kv)
+		}
+	}
+}
+
+func (p *spanKindAttributeProcessor) OnEnd(sdktrace.ReadOnlySpan) {}
+
+func (p *spanKindAttributeProcessor) Shutdown(context.Context) error { return nil }
+
+func (p *spanKindAttributeProcessor) ForceFlush(context.Context) error { return nil }
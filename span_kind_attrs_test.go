@@ -0,0 +1,56 @@
+package otelx
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestSpanKindAttributesApplyByKind(t *testing.T) {
+	cfg := Config{
+		ServiceName:   "svc",
+		Exporter:      ExporterMemory,
+		SamplingRatio: Float64(1),
+		SpanKindAttributes: map[string]map[string]string{
+			"client": {"net.transport": "ip_tcp"},
+			"Server": {"service.tier": "edge"},
+		},
+	}
+	prov, err := Setup(context.Background(), cfg, nil)
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	defer prov.Shutdown(context.Background())
+
+	tracer := prov.TP.Tracer("test")
+	_, client := tracer.Start(context.Background(), "call", trace.WithSpanKind(trace.SpanKindClient))
+	client.End()
+	_, server := tracer.Start(context.Background(), "handle", trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(attribute.String("service.tier", "core")))
+	server.End()
+	_, internal := tracer.Start(context.Background(), "work")
+	internal.End()
+
+	spans := prov.RecordedSpans()
+	if len(spans) != 3 {
+		t.Fatalf("expected 3 spans, got %d", len(spans))
+	}
+	if !spanHasAttribute(spans[0].Attributes, "net.transport", "ip_tcp") {
+		t.Fatalf("expected client default, got %v", spans[0].Attributes)
+	}
+	if !spanHasAttribute(spans[1].Attributes, "service.tier", "core") {
+		t.Fatalf("expected explicit attribute to win, got %v", spans[1].Attributes)
+	}
+	if len(spans[2].Attributes) != 0 {
+		t.Fatalf("expected no defaults on internal span, got %v", spans[2].Attributes)
+	}
+}
+
+func TestSpanKindAttributesRejectUnknownKind(t *testing.T) {
+	cfg := Config{ServiceName: "svc", SpanKindAttributes: map[string]map[string]string{"rpc": {"a": "b"}}}
+	if _, err := Setup(context.Background(), cfg, nil); err == nil {
+		t.Fatalf("expected error for unknown span kind")
+	}
+}
@@ -0,0 +1,73 @@
+package otelx
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// SpanKindPrefix makes StartSpan give spans whose name starts with Prefix the kind Kind.
+type SpanKindPrefix struct {
+	Prefix string
+	Kind   trace.SpanKind
+}
+
+// defaultSpanKindPrefixes follow the messaging semantic conventions' "{operation} {destination}"
+// span names.
+var defaultSpanKindPrefixes = []SpanKindPrefix{
+	{Prefix: "publish ", Kind: trace.SpanKindProducer},
+	{Prefix: "send ", Kind: trace.SpanKindProducer},
+	{Prefix: "create ", Kind: trace.SpanKindProducer},
+	{Prefix: "consume ", Kind: trace.SpanKindConsumer},
+	{Prefix: "receive ", Kind: trace.SpanKindConsumer},
+	{Prefix: "process ", Kind: trace.SpanKindConsumer},
+}
+
+var spanKindPrefixes struct {
+	sync.RWMutex
+	list []SpanKindPrefix
+}
+
+// RegisterSpanKindPrefixes adds process-wide naming conventions for StartSpan, usually during
+// start-up, e.g. {"call ", trace.SpanKindClient}. They are checked in registration order before
+// the built-in messaging prefixes (publish/send/create for producers, consume/receive/process for
+// consumers).
+func RegisterSpanKindPrefixes(prefixes ...SpanKindPrefix) {
+	spanKindPrefixes.Lock()
+	defer spanKindPrefixes.Unlock()
+	for _, p := range prefixes {
+		if p.Prefix != "" && p.Kind != trace.SpanKindUnspecified {
+			spanKindPrefixes.list = append(spanKindPrefixes.list, p)
+		}
+	}
+}
+
+// InferSpanKind returns the kind the naming conventions give name, or trace.SpanKindInternal.
+func InferSpanKind(name string) trace.SpanKind {
+	spanKindPrefixes.RLock()
+	defer spanKindPrefixes.RUnlock()
+	for _, p := range spanKindPrefixes.list {
+		if strings.HasPrefix(name, p.Prefix) {
+			return p.Kind
+		}
+	}
+	for _, p := range defaultSpanKindPrefixes {
+		if strings.HasPrefix(name, p.Prefix) {
+			return p.Kind
+		}
+	}
+	return trace.SpanKindInternal
+}
+
+// StartSpan starts a span on the global tracer provider and, unless opts carry
+// trace.WithSpanKind, sets its kind with InferSpanKind, so "publish orders" becomes a producer
+// span and the backend's service map gets the edge without manual kind plumbing.
+func StartSpan(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	if cfg := trace.NewSpanStartConfig(opts...); cfg.SpanKind() == trace.SpanKindUnspecified {
+		opts = append(opts, trace.WithSpanKind(InferSpanKind(name)))
+	}
+	return otel.Tracer(instrumentationName).Start(ctx, name, opts...)
+}
@@ -0,0 +1,43 @@
+package otelx
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestStartSpanInfersKind(t *testing.T) {
+	defer saveGlobal()()
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	defer tp.Shutdown(context.Background())
+	otel.SetTracerProvider(tp)
+
+	RegisterSpanKindPrefixes(SpanKindPrefix{Prefix: "call ", Kind: trace.SpanKindClient})
+
+	cases := []struct {
+		name string
+		opts []trace.SpanStartOption
+		want trace.SpanKind
+	}{
+		{name: "publish video.uploaded", want: trace.SpanKindProducer},
+		{name: "process video.uploaded", want: trace.SpanKindConsumer},
+		{name: "call transcoder", want: trace.SpanKindClient},
+		{name: "render thumbnail", want: trace.SpanKindInternal},
+		{name: "publish audit", opts: []trace.SpanStartOption{trace.WithSpanKind(trace.SpanKindInternal)}, want: trace.SpanKindInternal},
+	}
+	for _, tc := range cases {
+		_, span := StartSpan(context.Background(), tc.name, tc.opts...)
+		span.End()
+	}
+	ended := recorder.Ended()
+	for i, tc := range cases {
+		if got := ended[i].SpanKind(); got != tc.want {
+			t.Fatalf("%s: expected %v, got %v", tc.name, tc.want, got)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package otelx
+
+import (
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// SpanLimitsConfig caps what a single span may hold, bounding the memory of chatty
+// instrumentations. Zero keeps the SDK default (128 per count, unlimited value length, or the
+// OTEL_SPAN_*_LIMIT environment variables) and a negative value means unlimited.
+type SpanLimitsConfig struct {
+	// AttributeValueLength truncates string attribute values (and strings in slices) to this many
+	// characters.
+	AttributeValueLength   int `json:"attributeValueLength"`
+	AttributeCount         int `json:"attributeCount"`
+	EventCount             int `json:"eventCount"`
+	LinkCount              int `json:"linkCount"`
+	AttributePerEventCount int `json:"attributePerEventCount"`
+	AttributePerLinkCount  int `json:"attributePerLinkCount"`
+}
+
+// spanLimits merges c into the SDK defaults.
+func (c SpanLimitsConfig) spanLimits() sdktrace.SpanLimits {
+	limits := sdktrace.NewSpanLimits()
+	set := func(dst *int, v int) {
+		switch {
+		case v > 0:
+			*dst = v
+		case v < 0:
+			*dst = -1
+		}
+	}
+	set(&limits.AttributeValueLengthLimit, c.AttributeValueLength)
+	set(&limits.AttributeCountLimit, c.AttributeCount)
+	set(&limits.EventCountLimit, c.EventCount)
+	set(&limits.LinkCountLimit, c.LinkCount)
+	set(&limits.AttributePerEventCountLimit, c.AttributePerEventCount)
+	set(&limits.AttributePerLinkCountLimit, c.AttributePerLinkCount)
+	return limits
+}
@@ -0,0 +1,52 @@
+package otelx
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestSpanLimits(t *testing.T) {
+	cfg := Config{
+		ServiceName:   "svc",
+		Exporter:      ExporterMemory,
+		SamplingRatio: Float64(1),
+		SpanLimits:    &SpanLimitsConfig{AttributeValueLength: 4, AttributeCount: 2, EventCount: 1, LinkCount: -1},
+	}
+	prov, err := Setup(context.Background(), cfg, nil)
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	defer prov.Shutdown(context.Background())
+
+	_, span := prov.TP.Tracer("test").Start(context.Background(), "op", trace.WithAttributes(
+		attribute.String("a", "abcdefgh"),
+		attribute.Int("b", 1),
+		attribute.Int("c", 2),
+	))
+	span.AddEvent("first")
+	span.AddEvent("second")
+	span.End()
+
+	spans := prov.RecordedSpans()
+	if len(spans) != 1 {
+		t.Fatalf("expected one span, got %d", len(spans))
+	}
+	s := spans[0]
+	if len(s.Attributes) != 2 || s.DroppedAttributes != 1 {
+		t.Fatalf("expected 2 attributes and 1 dropped, got %v (%d dropped)", s.Attributes, s.DroppedAttributes)
+	}
+	if v := s.Attributes[0].Value.AsString(); v != "abcd" {
+		t.Fatalf("expected a truncated value, got %q", v)
+	}
+	if len(s.Events) != 1 || s.DroppedEvents != 1 {
+		t.Fatalf("expected 1 event and 1 dropped, got %d (%d dropped)", len(s.Events), s.DroppedEvents)
+	}
+
+	limits := cfg.SpanLimits.spanLimits()
+	if limits.LinkCountLimit != -1 || limits.AttributePerEventCountLimit != 128 {
+		t.Fatalf("expected unlimited links and default per-event attributes, got %+v", limits)
+	}
+}
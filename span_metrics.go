@@ -0,0 +1,133 @@
+package otelx
+
+import (
+	"context"
+	"fmt"
+
+	logx "github.com/bionicotaku/lingo-utils-logx"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Instruments and attributes recorded by WithSpanMetrics, named after the OpenTelemetry
+// Collector's spanmetrics connector so existing dashboards apply.
+const (
+	SpanMetricsCallsName    = "traces.span.metrics.calls"
+	SpanMetricsDurationName = "traces.span.metrics.duration"
+
+	SpanMetricsNameKey   = attribute.Key("span.name")
+	SpanMetricsKindKey   = attribute.Key("span.kind")
+	SpanMetricsStatusKey = attribute.Key("status.code")
+)
+
+// spanMetricsProcessor derives request rate, error rate and duration (RED) metrics per span
+// name, kind and status from every ended span, sampled or not.
+type spanMetricsProcessor struct {
+	calls    metric.Int64Counter
+	duration metric.Float64Histogram
+	attrs    *AttributeSetCache[spanMetricsKey]
+}
+
+type spanMetricsKey struct {
+	name string
+	kind trace.SpanKind
+	code codes.Code
+}
+
+func spanMetricsAttributes(k spanMetricsKey) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		SpanMetricsNameKey.String(k.name),
+		SpanMetricsKindKey.String(spanKindMetricValue(k.kind)),
+		SpanMetricsStatusKey.String(statusCodeMetricValue(k.code)),
+	}
+}
+
+// newSpanMetricsProcessor creates the processor enabled by WithSpanMetrics. It returns nil when
+// mp is nil, i.e. metrics are off.
+func newSpanMetricsProcessor(ctx context.Context, mp *sdkmetric.MeterProvider, logger logx.Logger) (*spanMetricsProcessor, error) {
+	if mp == nil {
+		if logger != nil {
+			logger.Warn(ctx, "otelx.metrics.instrumentation.skipped", logx.String("reason", "metrics disabled"))
+		}
+		return nil, nil
+	}
+	meter := mp.Meter(instrumentationName)
+	calls, err := meter.Int64Counter(SpanMetricsCallsName,
+		metric.WithDescription("Number of spans ended, by span name, kind and status."),
+		metric.WithUnit("{call}"))
+	if err != nil {
+		return nil, fmt.Errorf("otelx: create span metrics: %w", err)
+	}
+	duration, err := meter.Float64Histogram(SpanMetricsDurationName,
+		metric.WithDescription("Duration of spans, by span name, kind and status."),
+		metric.WithUnit("s"))
+	if err != nil {
+		return nil, fmt.Errorf("otelx: create span metrics: %w", err)
+	}
+	return &spanMetricsProcessor{
+		calls:    calls,
+		duration: duration,
+		attrs:    NewAttributeSetCache(0, spanMetricsAttributes),
+	}, nil
+}
+
+func (p *spanMetricsProcessor) OnStart(context.Context, sdktrace.ReadWriteSpan) {}
+
+func (p *spanMetricsProcessor) OnEnd(span sdktrace.ReadOnlySpan) {
+	if isCanarySpan(span) {
+		return
+	}
+	attrs := p.attrs.Get(spanMetricsKey{span.Name(), span.SpanKind(), span.Status().Code}).Measurement()
+	// The span context lets exemplar-aware readers link measurements to the trace.
+	ctx := trace.ContextWithSpanContext(context.Background(), span.SpanContext())
+	p.calls.Add(ctx, 1, attrs)
+	p.duration.Record(ctx, span.EndTime().Sub(span.StartTime()).Seconds(), attrs)
+}
+
+func (p *spanMetricsProcessor) Shutdown(context.Context) error { return nil }
+
+func (p *spanMetricsProcessor) ForceFlush(context.Context) error { return nil }
+
+func spanKindMetricValue(kind trace.SpanKind) string {
+	switch kind {
+	case trace.SpanKindServer:
+		return "SPAN_KIND_SERVER"
+	case trace.SpanKindClient:
+		return "SPAN_KIND_CLIENT"
+	case trace.SpanKindProducer:
+		return "SPAN_KIND_PRODUCER"
+	case trace.SpanKindConsumer:
+		return "SPAN_KIND_CONSUMER"
+	default:
+		return "SPAN_KIND_INTERNAL"
+	}
+}
+
+func statusCodeMetricValue(code codes.Code) string {
+	switch code {
+	case codes.Ok:
+		return "STATUS_CODE_OK"
+	case codes.Error:
+		return "STATUS_CODE_ERROR"
+	default:
+		return "STATUS_CODE_UNSET"
+	}
+}
+
+// recordDroppedSampler turns Drop decisions into RecordOnly, so processors such as span metrics
+// see every span while only sampled spans are exported.
+type recordDroppedSampler struct {
+	sdktrace.Sampler
+}
+
+func (s recordDroppedSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	result := s.Sampler.ShouldSample(p)
+	if result.Decision == sdktrace.Drop {
+		result.Decision = sdktrace.RecordOnly
+	}
+	return result
+}
@@ -0,0 +1,93 @@
+package otelx
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestSpanMetricsCountUnsampledSpans(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	cfg := Config{ServiceName: "svc", Exporter: ExporterMemory, SamplingRatio: Float64(0)}
+	prov, err := Setup(context.Background(), cfg, nil, WithMetricReader(reader), WithSpanMetrics())
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	defer prov.Shutdown(context.Background())
+
+	tracer := prov.TP.Tracer("test")
+	for i := 0; i < 2; i++ {
+		_, span := tracer.Start(context.Background(), "GET /videos", trace.WithSpanKind(trace.SpanKindServer))
+		span.End()
+	}
+	_, failed := tracer.Start(context.Background(), "GET /videos", trace.WithSpanKind(trace.SpanKindServer))
+	failed.SetStatus(codes.Error, "boom")
+	failed.End()
+
+	if spans := prov.RecordedSpans(); len(spans) != 0 {
+		t.Fatalf("expected unsampled spans to stay unexported, got %d", len(spans))
+	}
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("collect failed: %v", err)
+	}
+	calls := map[string]int64{}
+	var durations uint64
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			switch data := m.Data.(type) {
+			case metricdata.Sum[int64]:
+				if m.Name != SpanMetricsCallsName {
+					continue
+				}
+				for _, dp := range data.DataPoints {
+					if name, _ := dp.Attributes.Value(SpanMetricsNameKey); name.AsString() != "GET /videos" {
+						t.Fatalf("unexpected span.name %v", name)
+					}
+					if kind, _ := dp.Attributes.Value(SpanMetricsKindKey); kind.AsString() != "SPAN_KIND_SERVER" {
+						t.Fatalf("unexpected span.kind %v", kind)
+					}
+					status, _ := dp.Attributes.Value(SpanMetricsStatusKey)
+					calls[status.AsString()] += dp.Value
+				}
+			case metricdata.Histogram[float64]:
+				if m.Name == SpanMetricsDurationName {
+					for _, dp := range data.DataPoints {
+						durations += dp.Count
+					}
+				}
+			}
+		}
+	}
+	if calls["STATUS_CODE_UNSET"] != 2 || calls["STATUS_CODE_ERROR"] != 1 {
+		t.Fatalf("unexpected calls by status: %v", calls)
+	}
+	if durations != 3 {
+		t.Fatalf("expected 3 duration measurements, got %d", durations)
+	}
+}
+
+func TestSpanMetricsRequireMeterProvider(t *testing.T) {
+	logger := &recordingLogger{}
+	cfg := Config{ServiceName: "svc", Exporter: ExporterMemory, SamplingRatio: Float64(0)}
+	prov, err := Setup(context.Background(), cfg, logger, WithSpanMetrics())
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	defer prov.Shutdown(context.Background())
+
+	_, span := prov.TP.Tracer("test").Start(context.Background(), "op")
+	defer span.End()
+	if span.IsRecording() {
+		t.Fatalf("expected dropped spans to stay non-recording without metrics")
+	}
+	if !slices.Contains(logger.Entries(), "warn:otelx.metrics.instrumentation.skipped") {
+		t.Fatalf("expected skipped warning, got %v", logger.Entries())
+	}
+}
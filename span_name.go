@@ -0,0 +1,142 @@
+package otelx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// SpanNameNormalizer rewrites high-cardinality parts of a span name, such as ids in URL paths,
+// into placeholders so that names stay groupable in the backend.
+type SpanNameNormalizer interface {
+	NormalizeSpanName(name string) string
+}
+
+// SpanNameNormalizerFunc adapts a function to SpanNameNormalizer.
+type SpanNameNormalizerFunc func(name string) string
+
+// NormalizeSpanName calls f(name).
+func (f SpanNameNormalizerFunc) NormalizeSpanName(name string) string { return f(name) }
+
+// Built-in normalizers. Each replaces whole "/"-separated segments that match, so
+// "GET /users/42/orders" becomes "GET /users/{id}/orders".
+var (
+	UUIDNormalizer      = SegmentNormalizer(regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`), "{uuid}")
+	NumericIDNormalizer = SegmentNormalizer(regexp.MustCompile(`^[0-9]+$`), "{id}")
+	ULIDNormalizer      = SegmentNormalizer(regexp.MustCompile(`^[0-7][0-9A-HJKMNP-TV-Za-hjkmnp-tv-z]{25}$`), "{ulid}")
+	EmailNormalizer     = SegmentNormalizer(regexp.MustCompile(`^[^@\s]+@[^@\s]+\.[^@\s]+$`), "{email}")
+)
+
+// DefaultSpanNameNormalizers returns the built-in normalizers.
+func DefaultSpanNameNormalizers() []SpanNameNormalizer {
+	return []SpanNameNormalizer{UUIDNormalizer, ULIDNormalizer, NumericIDNormalizer, EmailNormalizer}
+}
+
+// SegmentNormalizer replaces every "/"-separated segment of a span name that fully matches
+// pattern with placeholder.
+func SegmentNormalizer(pattern *regexp.Regexp, placeholder string) SpanNameNormalizer {
+	return SpanNameNormalizerFunc(func(name string) string {
+		if !strings.Contains(name, "/") {
+			return name
+		}
+		segments := strings.Split(name, "/")
+		changed := false
+		for i, segment := range segments {
+			if segment != "" && pattern.MatchString(segment) {
+				segments[i] = placeholder
+				changed = true
+			}
+		}
+		if !changed {
+			return name
+		}
+		return strings.Join(segments, "/")
+	})
+}
+
+// RegexNormalizer replaces every match of pattern in a span name with replacement, which may
+// refer to submatches as in regexp.Regexp.ReplaceAllString, e.g. `^GET /users/[^/]+$` to
+// "GET /users/{id}".
+func RegexNormalizer(pattern *regexp.Regexp, replacement string) SpanNameNormalizer {
+	return SpanNameNormalizerFunc(func(name string) string {
+		return pattern.ReplaceAllString(name, replacement)
+	})
+}
+
+// SpanNameRule is a Config-driven RegexNormalizer.
+type SpanNameRule struct {
+	Pattern     string `json:"pattern"`
+	Replacement string `json:"replacement"`
+}
+
+func (r SpanNameRule) validate() error {
+	if r.Pattern == "" {
+		return errors.New("pattern is required")
+	}
+	if _, err := regexp.Compile(r.Pattern); err != nil {
+		return fmt.Errorf("pattern: %w", err)
+	}
+	return nil
+}
+
+// spanNameRuleNormalizers compiles validated rules.
+func spanNameRuleNormalizers(rules []SpanNameRule) []SpanNameNormalizer {
+	normalizers := make([]SpanNameNormalizer, len(rules))
+	for i, rule := range rules {
+		normalizers[i] = RegexNormalizer(regexp.MustCompile(rule.Pattern), rule.Replacement)
+	}
+	return normalizers
+}
+
+func normalizeSpanName(normalizers []SpanNameNormalizer, name string) string {
+	for _, n := range normalizers {
+		name = n.NormalizeSpanName(name)
+	}
+	return name
+}
+
+// HTTPSpanNames names otelhttp spans "<METHOD> <path>", passing the path through normalizers.
+// Requests routed by http.ServeMux use the matched pattern instead, which is already templated.
+// Use it with HTTPHandler or HTTPTransport; the defaults are DefaultSpanNameNormalizers.
+func HTTPSpanNames(normalizers ...SpanNameNormalizer) otelhttp.Option {
+	if len(normalizers) == 0 {
+		normalizers = DefaultSpanNameNormalizers()
+	}
+	return otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
+		if r.Pattern != "" {
+			if _, path, ok := strings.Cut(r.Pattern, " "); ok {
+				return r.Method + " " + path
+			}
+			return r.Method + " " + r.Pattern
+		}
+		return r.Method + " " + normalizeSpanName(normalizers, r.URL.Path)
+	})
+}
+
+// spanNameProcessor renames spans on start. It covers spans whose instrumentation has no naming
+// hook, such as otelgrpc, and names built by hand.
+type spanNameProcessor struct {
+	normalizers []SpanNameNormalizer
+}
+
+func newSpanNameProcessor(normalizers []SpanNameNormalizer) sdktrace.SpanProcessor {
+	return &spanNameProcessor{normalizers: normalizers}
+}
+
+func (p *spanNameProcessor) OnStart(_ context.Context, span sdktrace.ReadWriteSpan) {
+	if name := normalizeSpanName(p.normalizers, span.Name()); name != span.Name() {
+		span.SetName(name)
+	}
+}
+
+func (p *spanNameProcessor) OnEnd(sdktrace.ReadOnlySpan) {}
+
+func (p *spanNameProcessor) Shutdown(context.Context) error { return nil }
+
+func (p *spanNameProcessor) ForceFlush(context.Context) error { return nil }
@@ -0,0 +1,129 @@
+package otelx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+)
+
+func TestDefaultSpanNameNormalizers(t *testing.T) {
+	cases := map[string]string{
+		"GET /users/42/orders":                             "GET /users/{id}/orders",
+		"GET /orders/3f2504e0-4f89-11d3-9a0c-0305e82c3301": "GET /orders/{uuid}",
+		"GET /jobs/01ARZ3NDEKTSV4RRFFQ69G5FAV":             "GET /jobs/{ulid}",
+		"POST /accounts/jane.doe@example.com/reset":        "POST /accounts/{email}/reset",
+		"GET /users/me":                                    "GET /users/me",
+		"/lingo.video.v1.VideoService/GetVideo":            "/lingo.video.v1.VideoService/GetVideo",
+		"process 42":                                       "process 42",
+	}
+	normalizers := DefaultSpanNameNormalizers()
+	for in, want := range cases {
+		if got := normalizeSpanName(normalizers, in); got != want {
+			t.Errorf("normalize(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestSpanNameNormalizersRenameSpans(t *testing.T) {
+	cfg := Config{ServiceName: "svc", Exporter: ExporterMemory, SamplingRatio: Float64(1)}
+	tenant := SpanNameNormalizerFunc(func(name string) string {
+		return strings.ReplaceAll(name, "/t/acme/", "/t/{tenant}/")
+	})
+	prov, err := Setup(context.Background(), cfg, nil, WithSpanNameNormalizers(NumericIDNormalizer, tenant))
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	defer prov.Shutdown(context.Background())
+
+	_, span := prov.TP.Tracer("test").Start(context.Background(), "GET /t/acme/users/7")
+	span.End()
+
+	spans := prov.RecordedSpans()
+	if len(spans) != 1 || spans[0].Name != "GET /t/{tenant}/users/{id}" {
+		t.Fatalf("expected normalized span name, got %+v", spans)
+	}
+}
+
+func TestHTTPSpanNames(t *testing.T) {
+	cfg := Config{ServiceName: "svc", Exporter: ExporterMemory, SamplingRatio: Float64(1)}
+	prov, err := Setup(context.Background(), cfg, nil)
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	defer prov.Shutdown(context.Background())
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /videos/{id}", func(http.ResponseWriter, *http.Request) {})
+	ok := http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})
+	routed := HTTPHandler("api", mux, HTTPSpanNames(), otelhttp.WithTracerProvider(prov.TP))
+	raw := HTTPHandler("api", ok, HTTPSpanNames(), otelhttp.WithTracerProvider(prov.TP))
+
+	routed.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/videos/abc", nil))
+	raw.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodDelete, "/users/42", nil))
+
+	spans := prov.RecordedSpans()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+	if spans[0].Name != "GET /videos/{id}" {
+		t.Fatalf("expected mux pattern as span name, got %q", spans[0].Name)
+	}
+	if spans[1].Name != "DELETE /users/{id}" {
+		t.Fatalf("expected normalized path as span name, got %q", spans[1].Name)
+	}
+}
+
+func TestSpanNameRules(t *testing.T) {
+	cfg := Config{
+		ServiceName:   "svc",
+		Exporter:      ExporterMemory,
+		SamplingRatio: Float64(1),
+		SpanNameRules: []SpanNameRule{
+			{Pattern: `^(GET|DELETE) /users/[^/]+$`, Replacement: "$1 /users/{id}"},
+			{Pattern: `/orders/[0-9]+`, Replacement: "/orders/{id}"},
+		},
+	}
+	prov, err := Setup(context.Background(), cfg, nil, WithSpanNameNormalizers(UUIDNormalizer))
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	defer prov.Shutdown(context.Background())
+
+	tracer := prov.TP.Tracer("test")
+	for _, name := range []string{
+		"DELETE /users/alice",
+		"GET /shops/7/orders/1234/items",
+		"GET /files/9f1c2b7e-1d2a-4c3b-8e9f-0a1b2c3d4e5f",
+		"POST /users/alice",
+	} {
+		_, span := tracer.Start(context.Background(), name)
+		span.End()
+	}
+	want := []string{"DELETE /users/{id}", "GET /shops/7/orders/{id}/items", "GET /files/{uuid}", "POST /users/alice"}
+	spans := prov.RecordedSpans()
+	if len(spans) != len(want) {
+		t.Fatalf("expected %d spans, got %d", len(want), len(spans))
+	}
+	for i, s := range spans {
+		if s.Name != want[i] {
+			t.Fatalf("expected %q, got %q", want[i], s.Name)
+		}
+	}
+}
+
+func TestSpanNameRulesValidation(t *testing.T) {
+	for want, rule := range map[string]SpanNameRule{
+		"pattern is required": {Replacement: "x"},
+		"pattern:":            {Pattern: "("},
+	} {
+		cfg := Config{ServiceName: "svc", Exporter: ExporterMemory, SpanNameRules: []SpanNameRule{rule}}
+		_, err := Setup(context.Background(), cfg, nil)
+		if err == nil || !strings.Contains(err.Error(), "otelx: spanNameRules[0]: "+want) {
+			t.Fatalf("expected %q error, got %v", want, err)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package otelx
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+type tagProcessor struct{}
+
+func (tagProcessor) OnStart(_ context.Context, s sdktrace.ReadWriteSpan) {
+	s.SetAttributes(attribute.String("team", "video"))
+}
+func (tagProcessor) OnEnd(sdktrace.ReadOnlySpan)      {}
+func (tagProcessor) Shutdown(context.Context) error   { return nil }
+func (tagProcessor) ForceFlush(context.Context) error { return nil }
+
+func TestWithSpanProcessor(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	cfg := Config{ServiceName: "svc", Exporter: ExporterMemory, SamplingRatio: Float64(1)}
+	prov, err := Setup(context.Background(), cfg, nil,
+		WithSpanProcessor(tagProcessor{}),
+		WithSpanProcessor(recorder))
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+
+	_, span := prov.TP.Tracer("test").Start(context.Background(), "op")
+	span.End()
+
+	if got := len(recorder.Ended()); got != 1 {
+		t.Fatalf("expected the processor to see the span, got %d", got)
+	}
+	exported := prov.RecordedSpans()
+	if len(exported) != 1 || !spanHasAttribute(exported[0].Attributes, "team", "video") {
+		t.Fatalf("expected the attribute set by the processor to be exported")
+	}
+
+	var names []string
+	for _, p := range prov.Describe().Processors {
+		names = append(names, p.Name)
+	}
+	if len(names) < 2 || names[0] != "spanProcessor" || names[1] != "spanProcessor" {
+		t.Fatalf("expected two spanProcessor entries first, got %v", names)
+	}
+
+	if err := prov.Shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown failed: %v", err)
+	}
+	_, span = prov.TP.Tracer("test").Start(context.Background(), "late")
+	span.End()
+	if got := len(recorder.Ended()); got != 1 {
+		t.Fatalf("expected the processor to shut down with the provider, got %d spans", got)
+	}
+}
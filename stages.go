@@ -0,0 +1,109 @@
+package otelx
+
+import (
+	"fmt"
+	"slices"
+)
+
+// ProcessorPhase groups span processing stages. Phases always run in declaration order: spans are
+// enriched when they start, then filtered, redacted and finally exported.
+type ProcessorPhase int
+
+const (
+	// PhaseEnrich adds data when a span starts (context extractors, span kind defaults, name
+	// normalization).
+	PhaseEnrich ProcessorPhase = iota
+	// PhaseFilter drops spans or parts of them before export.
+	PhaseFilter
+	// PhaseRedact rewrites or removes attribute values before export.
+	PhaseRedact
+	// PhaseExport batches and exports spans.
+	PhaseExport
+)
+
+func (p ProcessorPhase) String() string {
+	switch p {
+	case PhaseEnrich:
+		return "enrich"
+	case PhaseFilter:
+		return "filter"
+	case PhaseRedact:
+		return "redact"
+	case PhaseExport:
+		return "export"
+	default:
+		return fmt.Sprintf("phase(%d)", int(p))
+	}
+}
+
+// SpanStage names a built-in export-time processing step that WithStageOrder can reorder.
+type SpanStage string
+
+const (
+	// StageEventLimits applies WithEventLimits.
+	StageEventLimits SpanStage = "eventLimits"
+	// StageAttributeDrop removes RemoteConfig.DropAttributes.
+	StageAttributeDrop SpanStage = "attributeDrop"
+	// StageRedaction applies Config.ScrubPII and Config.RedactionRules.
+	StageRedaction SpanStage = "redaction"
+	// StageTruncation applies WithAttributeTruncation or Config.Truncation.
+	StageTruncation SpanStage = "truncation"
+	// StageCardinality applies WithCardinalityGuard.
+	StageCardinality SpanStage = "cardinality"
+)
+
+// stagePhases assigns every stage its phase; defaultStageOrder is the order used when
+// WithStageOrder does not say otherwise.
+var (
+	stagePhases = map[SpanStage]ProcessorPhase{
+		StageEventLimits:   PhaseFilter,
+		StageAttributeDrop: PhaseRedact,
+		StageRedaction:     PhaseRedact,
+		StageTruncation:    PhaseRedact,
+		StageCardinality:   PhaseRedact,
+	}
+	defaultStageOrder = []SpanStage{StageEventLimits, StageAttributeDrop, StageRedaction, StageTruncation, StageCardinality}
+)
+
+// WithStageOrder declares the order of the export-time stages. Stages may only be reordered within
+// their phase; unlisted stages keep their default position after the listed ones of the same phase.
+// Setup rejects unknown or repeated stages and orders that cross phases, e.g. redaction before
+// filtering.
+func WithStageOrder(stages ...SpanStage) Option {
+	return func(o *setupOptions) {
+		o.stageOrder = append(o.stageOrder, stages...)
+	}
+}
+
+// resolveStageOrder merges declared into the default order, validating it against the phases.
+func resolveStageOrder(declared []SpanStage) ([]SpanStage, error) {
+	for i, stage := range declared {
+		phase, ok := stagePhases[stage]
+		if !ok {
+			return nil, fmt.Errorf("unknown stage %q", stage)
+		}
+		if slices.Contains(declared[:i], stage) {
+			return nil, fmt.Errorf("stage %q listed twice", stage)
+		}
+		if i > 0 {
+			prev := declared[i-1]
+			if prevPhase := stagePhases[prev]; prevPhase > phase {
+				return nil, fmt.Errorf("stage %q (%s) cannot run before %q (%s)", prev, prevPhase, stage, phase)
+			}
+		}
+	}
+	order := make([]SpanStage, 0, len(defaultStageOrder))
+	for phase := PhaseEnrich; phase <= PhaseExport; phase++ {
+		for _, stage := range declared {
+			if stagePhases[stage] == phase {
+				order = append(order, stage)
+			}
+		}
+		for _, stage := range defaultStageOrder {
+			if stagePhases[stage] == phase && !slices.Contains(declared, stage) {
+				order = append(order, stage)
+			}
+		}
+	}
+	return order, nil
+}
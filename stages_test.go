@@ -0,0 +1,74 @@
+package otelx
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func TestResolveStageOrder(t *testing.T) {
+	order, err := resolveStageOrder(nil)
+	if err != nil || !reflect.DeepEqual(order, defaultStageOrder) {
+		t.Fatalf("expected default order, got %v (%v)", order, err)
+	}
+
+	order, err = resolveStageOrder([]SpanStage{StageCardinality, StageAttributeDrop})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []SpanStage{StageEventLimits, StageCardinality, StageAttributeDrop, StageRedaction, StageTruncation}
+	if !reflect.DeepEqual(order, want) {
+		t.Fatalf("expected %v, got %v", want, order)
+	}
+
+	cases := map[string][]SpanStage{
+		"unknown stage":     {"scrub"},
+		"listed twice":      {StageTruncation, StageTruncation},
+		"cannot run before": {StageTruncation, StageEventLimits},
+	}
+	for want, declared := range cases {
+		if _, err := resolveStageOrder(declared); err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected %q error for %v, got %v", want, declared, err)
+		}
+	}
+}
+
+func TestSetupRejectsConflictingStageOrder(t *testing.T) {
+	cfg := Config{ServiceName: "svc", Exporter: ExporterMemory}
+	_, err := Setup(context.Background(), cfg, nil, WithStageOrder(StageCardinality, StageEventLimits))
+	if err == nil || !strings.Contains(err.Error(), "WithStageOrder") {
+		t.Fatalf("expected stage order error, got %v", err)
+	}
+}
+
+func TestSetupAppliesStageOrder(t *testing.T) {
+	cfg := Config{ServiceName: "svc", Exporter: ExporterMemory, SamplingRatio: Float64(1)}
+	prov, err := Setup(context.Background(), cfg, nil,
+		WithAttributeTruncation(4),
+		WithCardinalityGuard(CardinalityLimits{MaxValues: 1, Keys: []string{"user.id"}}),
+		WithStageOrder(StageCardinality, StageTruncation),
+	)
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	defer prov.Shutdown(context.Background())
+
+	tracer := prov.TP.Tracer("test")
+	for _, id := range []string{"alice-1", "alice-2"} {
+		_, span := tracer.Start(context.Background(), "op")
+		span.SetAttributes(attribute.String("user.id", id))
+		span.End()
+	}
+
+	spans := prov.RecordedSpans()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+	// Truncated first, both ids would read "alic…" and stay within the guard's budget.
+	if got := attributeValue(spans[1].Attributes, "user.id"); !strings.HasPrefix(got, "hash") {
+		t.Fatalf("expected guarded value, got %q", got)
+	}
+}
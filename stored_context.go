@@ -0,0 +1,89 @@
+package otelx
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// storedContextVersion is the first byte of every encoded span context. Decoders reject versions
+// they do not know, so the layout can evolve without misreading old rows.
+const storedContextVersion byte = 1
+
+// storedContextSize is the fixed part of version 1: version, trace id, span id and trace flags.
+// Any remaining bytes carry the W3C tracestate.
+const storedContextSize = 1 + 16 + 8 + 1
+
+// ResumedKey marks spans started by StartResumedSpan.
+const ResumedKey = attribute.Key("otelx.resumed")
+
+// ErrNoSpanContext is returned by EncodeSpanContext when ctx carries no valid span.
+var ErrNoSpanContext = errors.New("otelx: no valid span context")
+
+// EncodeSpanContext encodes the span context active in ctx as a compact, versioned URL-safe base64
+// string (35 characters without tracestate) for storage in a database column, so work resumed
+// later can be linked back to the trace that scheduled it.
+func EncodeSpanContext(ctx context.Context) (string, error) {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return "", ErrNoSpanContext
+	}
+	state := sc.TraceState().String()
+	buf := make([]byte, storedContextSize, storedContextSize+len(state))
+	buf[0] = storedContextVersion
+	traceID, spanID := sc.TraceID(), sc.SpanID()
+	copy(buf[1:17], traceID[:])
+	copy(buf[17:25], spanID[:])
+	buf[25] = byte(sc.TraceFlags())
+	buf = append(buf, state...)
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// DecodeSpanContext decodes a value produced by EncodeSpanContext into a remote span context.
+func DecodeSpanContext(encoded string) (trace.SpanContext, error) {
+	buf, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return trace.SpanContext{}, fmt.Errorf("otelx: decode span context: %w", err)
+	}
+	if len(buf) == 0 || buf[0] != storedContextVersion {
+		return trace.SpanContext{}, fmt.Errorf("otelx: unsupported span context encoding")
+	}
+	if len(buf) < storedContextSize {
+		return trace.SpanContext{}, fmt.Errorf("otelx: truncated span context")
+	}
+	cfg := trace.SpanContextConfig{
+		TraceFlags: trace.TraceFlags(buf[25]),
+		Remote:     true,
+	}
+	copy(cfg.TraceID[:], buf[1:17])
+	copy(cfg.SpanID[:], buf[17:25])
+	if len(buf) > storedContextSize {
+		state, err := trace.ParseTraceState(string(buf[storedContextSize:]))
+		if err != nil {
+			return trace.SpanContext{}, fmt.Errorf("otelx: decode span context: %w", err)
+		}
+		cfg.TraceState = state
+	}
+	sc := trace.NewSpanContext(cfg)
+	if !sc.IsValid() {
+		return trace.SpanContext{}, fmt.Errorf("otelx: invalid span context")
+	}
+	return sc, nil
+}
+
+// StartResumedSpan starts a new root span named name for work resumed from storage and links it to
+// the span context encoded in stored. Like webhooks, resumed work can run days later, so the
+// original trace is linked rather than continued. An empty or undecodable stored value yields an
+// unlinked root span.
+func StartResumedSpan(ctx context.Context, name, stored string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	opts = append([]trace.SpanStartOption{trace.WithNewRoot(), trace.WithAttributes(ResumedKey.Bool(true))}, opts...)
+	if sc, err := DecodeSpanContext(stored); err == nil {
+		opts = append(opts, trace.WithLinks(trace.Link{SpanContext: sc}))
+	}
+	return otel.Tracer(instrumentationName).Start(ctx, name, opts...)
+}
@@ -0,0 +1,80 @@
+package otelx
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestEncodeDecodeSpanContext(t *testing.T) {
+	state, _ := trace.ParseTraceState("vendor=abc")
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x01, 0x02, 0x03},
+		SpanID:     trace.SpanID{0x04, 0x05},
+		TraceFlags: trace.FlagsSampled,
+		TraceState: state,
+	})
+	encoded, err := EncodeSpanContext(trace.ContextWithSpanContext(context.Background(), sc))
+	if err != nil {
+		t.Fatalf("encode failed: %v", err)
+	}
+	decoded, err := DecodeSpanContext(encoded)
+	if err != nil {
+		t.Fatalf("decode failed: %v", err)
+	}
+	if !decoded.Equal(sc.WithRemote(true)) {
+		t.Fatalf("round trip mismatch: %v != %v", decoded, sc)
+	}
+
+	plain, _ := EncodeSpanContext(trace.ContextWithSpanContext(context.Background(), sc.WithTraceState(trace.TraceState{})))
+	if len(plain) != 35 {
+		t.Fatalf("expected 35 character encoding without tracestate, got %d", len(plain))
+	}
+}
+
+func TestDecodeSpanContextRejectsBadInput(t *testing.T) {
+	if _, err := EncodeSpanContext(context.Background()); !errors.Is(err, ErrNoSpanContext) {
+		t.Fatalf("expected ErrNoSpanContext, got %v", err)
+	}
+	for name, encoded := range map[string]string{
+		"not base64": "%%%",
+		"version":    "AgAAAAAAAAAAAAAAAAAAAAAAAAAA",
+		"truncated":  "AQID",
+		"zero ids":   "AQAAAAAAAAAAAAAAAAAAAAAAAAAA",
+	} {
+		if _, err := DecodeSpanContext(encoded); err == nil {
+			t.Fatalf("%s: expected decode error", name)
+		}
+	}
+}
+
+func TestStartResumedSpanLinksStoredContext(t *testing.T) {
+	defer saveGlobal()()
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	otel.SetTracerProvider(tp)
+
+	ctx, scheduler := tp.Tracer("test").Start(context.Background(), "schedule")
+	stored, err := EncodeSpanContext(ctx)
+	if err != nil {
+		t.Fatalf("encode failed: %v", err)
+	}
+	scheduler.End()
+
+	_, resumed := StartResumedSpan(context.Background(), "resume", stored)
+	resumed.End()
+
+	spans := recorder.Ended()
+	got := spans[len(spans)-1]
+	if got.Parent().IsValid() || len(got.Links()) != 1 || got.Links()[0].SpanContext.SpanID() != scheduler.SpanContext().SpanID() {
+		t.Fatalf("expected a root span linked to the scheduler, got parent=%v links=%v", got.Parent(), got.Links())
+	}
+	if !spanHasAttribute(got.Attributes(), ResumedKey, "true") {
+		t.Fatalf("expected %s attribute", ResumedKey)
+	}
+}
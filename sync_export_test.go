@@ -0,0 +1,37 @@
+package otelx
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestWithSyncExport(t *testing.T) {
+	rec := &recordingLogger{}
+	mem := tracetest.NewInMemoryExporter()
+	cfg := Config{ServiceName: "svc", SamplingRatio: Float64(1), Batch: &BatchConfig{MaxQueueSize: 4096}}
+	prov, err := Setup(context.Background(), cfg, rec, WithSpanExporter(mem), WithSyncExport())
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	defer prov.Shutdown(context.Background())
+
+	_, span := prov.TP.Tracer("test").Start(context.Background(), "op")
+	span.End()
+	if spans := mem.GetSpans(); len(spans) != 1 || spans[0].Name != "op" {
+		t.Fatalf("expected the span to be exported when it ended, got %v", spans)
+	}
+
+	found := false
+	for _, e := range rec.Entries() {
+		found = found || e == "warn:otelx.export.batch.ignored"
+	}
+	if !found {
+		t.Fatalf("expected a warning about the ignored batch config, got %v", rec.Entries())
+	}
+	processors := prov.Describe().Processors
+	if last := processors[len(processors)-1]; last.Name != "syncExport" {
+		t.Fatalf("expected syncExport in the description, got %v", processors)
+	}
+}
@@ -0,0 +1,209 @@
+package otelx
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// DefaultTailSamplingMaxTraces bounds the traces TailSamplingConfig buffers when MaxTraces is unset.
+	DefaultTailSamplingMaxTraces = 10000
+	// tailSamplingMaxAge is how long a trace may wait for its local root before it is decided on
+	// errors alone, e.g. when the root span is never ended.
+	tailSamplingMaxAge = time.Minute
+	// tailSamplingDecisions is how many decided traces are remembered for spans ending after
+	// their root.
+	tailSamplingDecisions = 4096
+)
+
+// TailSamplingConfig keeps only slow or failed traces: the spans of each sampled trace are held
+// in memory until its local root span ends and exported only when the root took at least
+// LatencyThreshold or any span ended with an Error status. Force-sampled traces (ForceSample,
+// X-Debug-Trace) and Provider.StartCanary spans are always kept. It filters what head sampling
+// kept, so pair it with a high SamplingRatio.
+type TailSamplingConfig struct {
+	LatencyThreshold time.Duration `json:"latencyThreshold"`
+	// MaxTraces bounds the traces waiting for their root (default DefaultTailSamplingMaxTraces);
+	// spans of further traces are exported unfiltered.
+	MaxTraces int `json:"maxTraces"`
+}
+
+func (c TailSamplingConfig) validate() error {
+	if c.LatencyThreshold <= 0 {
+		return errors.New("latencyThreshold must be positive")
+	}
+	if c.MaxTraces < 0 {
+		return errors.New("maxTraces must not be negative")
+	}
+	return nil
+}
+
+type pendingTrace struct {
+	first  time.Time
+	spans  []sdktrace.ReadOnlySpan
+	failed bool
+}
+
+// tailSamplingProcessor wraps the export processors and forwards a trace's spans once its local
+// root ended and the trace qualified; other traces are discarded.
+type tailSamplingProcessor struct {
+	threshold time.Duration
+	maxTraces int
+	next      []sdktrace.SpanProcessor
+	now       func() time.Time
+
+	mu      sync.Mutex
+	pending map[trace.TraceID]*pendingTrace
+	// decided remembers recent decisions in a ring so late children follow their root.
+	decided map[trace.TraceID]bool
+	ring    []trace.TraceID
+	ringPos int
+}
+
+func newTailSamplingProcessor(cfg TailSamplingConfig, next []sdktrace.SpanProcessor) *tailSamplingProcessor {
+	maxTraces := cfg.MaxTraces
+	if maxTraces == 0 {
+		maxTraces = DefaultTailSamplingMaxTraces
+	}
+	return &tailSamplingProcessor{
+		threshold: cfg.LatencyThreshold,
+		maxTraces: maxTraces,
+		next:      next,
+		now:       time.Now,
+		pending:   map[trace.TraceID]*pendingTrace{},
+		decided:   map[trace.TraceID]bool{},
+		ring:      make([]trace.TraceID, tailSamplingDecisions),
+	}
+}
+
+func (p *tailSamplingProcessor) OnStart(ctx context.Context, s sdktrace.ReadWriteSpan) {
+	for _, next := range p.next {
+		next.OnStart(ctx, s)
+	}
+}
+
+func (p *tailSamplingProcessor) OnEnd(s sdktrace.ReadOnlySpan) {
+	if !s.SpanContext().IsSampled() {
+		p.forward(s)
+		return
+	}
+	id := s.SpanContext().TraceID()
+	failed := s.Status().Code == codes.Error
+	root := isLocalRoot(s.Parent())
+
+	p.mu.Lock()
+	if keep, ok := p.decided[id]; ok {
+		p.mu.Unlock()
+		if keep || failed {
+			p.forward(s)
+		}
+		return
+	}
+	t := p.pending[id]
+	var evicted []sdktrace.ReadOnlySpan
+	if t == nil {
+		if !root && len(p.pending) >= p.maxTraces {
+			var ok bool
+			if evicted, ok = p.evictStale(); !ok {
+				p.mu.Unlock()
+				p.forward(s)
+				return
+			}
+		}
+		t = &pendingTrace{first: p.now()}
+		if !root {
+			p.pending[id] = t
+		}
+	}
+	t.spans = append(t.spans, s)
+	t.failed = t.failed || failed
+	if !root {
+		p.mu.Unlock()
+		p.forward(evicted...)
+		return
+	}
+	keep := t.failed || s.EndTime().Sub(s.StartTime()) >= p.threshold || isCanarySpan(s) || isForceSampled(s)
+	delete(p.pending, id)
+	p.remember(id, keep)
+	p.mu.Unlock()
+
+	if keep {
+		p.forward(t.spans...)
+	}
+}
+
+// evictStale decides traces older than tailSamplingMaxAge on errors alone and reports whether room
+// was made. It returns the spans of the kept traces, which the caller forwards after releasing
+// p.mu. Callers hold p.mu.
+func (p *tailSamplingProcessor) evictStale() (keep []sdktrace.ReadOnlySpan, evicted bool) {
+	cutoff := p.now().Add(-tailSamplingMaxAge)
+	for id, t := range p.pending {
+		if t.first.After(cutoff) {
+			continue
+		}
+		delete(p.pending, id)
+		p.remember(id, t.failed)
+		if t.failed {
+			keep = append(keep, t.spans...)
+		}
+		evicted = true
+	}
+	return keep, evicted
+}
+
+// remember records a decision, forgetting the oldest one. Callers hold p.mu.
+func (p *tailSamplingProcessor) remember(id trace.TraceID, keep bool) {
+	if old := p.ring[p.ringPos]; old.IsValid() {
+		delete(p.decided, old)
+	}
+	p.ring[p.ringPos] = id
+	p.ringPos = (p.ringPos + 1) % len(p.ring)
+	p.decided[id] = keep
+}
+
+func (p *tailSamplingProcessor) forward(spans ...sdktrace.ReadOnlySpan) {
+	for _, s := range spans {
+		for _, next := range p.next {
+			next.OnEnd(s)
+		}
+	}
+}
+
+// Shutdown drops traces still waiting for their root, unless they failed, and shuts down the
+// export processors.
+func (p *tailSamplingProcessor) Shutdown(ctx context.Context) error {
+	p.mu.Lock()
+	var keep []sdktrace.ReadOnlySpan
+	for id, t := range p.pending {
+		delete(p.pending, id)
+		if t.failed {
+			keep = append(keep, t.spans...)
+		}
+	}
+	p.mu.Unlock()
+	p.forward(keep...)
+
+	var firstErr error
+	for _, next := range p.next {
+		if err := next.Shutdown(ctx); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func (p *tailSamplingProcessor) ForceFlush(ctx context.Context) error {
+	var firstErr error
+	for _, next := range p.next {
+		if err := next.ForceFlush(ctx); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
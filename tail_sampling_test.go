@@ -0,0 +1,187 @@
+package otelx
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTailSamplingKeepsSlowAndFailedTraces(t *testing.T) {
+	cfg := Config{
+		ServiceName:   "svc",
+		Exporter:      ExporterMemory,
+		SamplingRatio: Float64(1),
+		TailSampling:  &TailSamplingConfig{LatencyThreshold: time.Second},
+	}
+	prov, err := Setup(context.Background(), cfg, nil)
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	defer prov.Shutdown(context.Background())
+
+	tracer := prov.TP.Tracer("test")
+	run := func(name string, took time.Duration, fail bool) {
+		start := time.Now()
+		ctx, root := tracer.Start(context.Background(), name, trace.WithTimestamp(start))
+		_, child := tracer.Start(ctx, name+"/child")
+		if fail {
+			child.SetStatus(codes.Error, "boom")
+		}
+		child.End()
+		root.End(trace.WithTimestamp(start.Add(took)))
+	}
+	run("fast", 10*time.Millisecond, false)
+	run("slow", 2*time.Second, false)
+	run("failed", 10*time.Millisecond, true)
+
+	if err := prov.TP.ForceFlush(context.Background()); err != nil {
+		t.Fatalf("flush failed: %v", err)
+	}
+	var names []string
+	for _, span := range prov.RecordedSpans() {
+		names = append(names, span.Name)
+	}
+	if got := strings.Join(names, ","); got != "slow/child,slow,failed/child,failed" {
+		t.Fatalf("unexpected exported spans: %s", got)
+	}
+
+	found := false
+	for _, p := range prov.Describe().Processors {
+		found = found || (p.Name == "tailSampling" && p.Phase == "filter")
+	}
+	if !found {
+		t.Fatalf("expected tailSampling in the description")
+	}
+}
+
+func TestTailSamplingLateChildFollowsRoot(t *testing.T) {
+	cfg := Config{
+		ServiceName:   "svc",
+		Exporter:      ExporterMemory,
+		SamplingRatio: Float64(1),
+		TailSampling:  &TailSamplingConfig{LatencyThreshold: time.Hour},
+	}
+	prov, err := Setup(context.Background(), cfg, nil)
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	defer prov.Shutdown(context.Background())
+
+	ctx, root := prov.TP.Tracer("test").Start(context.Background(), "root")
+	_, late := prov.TP.Tracer("test").Start(ctx, "late")
+	root.End()
+	late.End()
+
+	if err := prov.TP.ForceFlush(context.Background()); err != nil {
+		t.Fatalf("flush failed: %v", err)
+	}
+	if got := len(prov.RecordedSpans()); got != 0 {
+		t.Fatalf("expected the fast trace to be dropped entirely, got %d spans", got)
+	}
+}
+
+func TestTailSamplingValidation(t *testing.T) {
+	cfg := Config{ServiceName: "svc", Exporter: ExporterMemory, TailSampling: &TailSamplingConfig{}}
+	_, err := Setup(context.Background(), cfg, nil)
+	if err == nil || !strings.Contains(err.Error(), "otelx: tailSampling: latencyThreshold must be positive") {
+		t.Fatalf("expected latencyThreshold error, got %v", err)
+	}
+}
+
+// lockProbe records whether the tail sampler's mutex was held while OnEnd forwarded a span.
+type lockProbe struct {
+	sdktrace.SpanProcessor
+	tail   *tailSamplingProcessor
+	locked []bool
+}
+
+func (p *lockProbe) OnEnd(sdktrace.ReadOnlySpan) {
+	free := p.tail.mu.TryLock()
+	if free {
+		p.tail.mu.Unlock()
+	}
+	p.locked = append(p.locked, !free)
+}
+
+func (p *lockProbe) Shutdown(context.Context) error { return nil }
+
+func TestTailSamplingForwardsWithoutLock(t *testing.T) {
+	probe := &lockProbe{SpanProcessor: sdktrace.NewSimpleSpanProcessor(tracetest.NewInMemoryExporter())}
+	tail := newTailSamplingProcessor(TailSamplingConfig{LatencyThreshold: time.Hour, MaxTraces: 1}, []sdktrace.SpanProcessor{probe})
+	probe.tail = tail
+	now := time.Now()
+	tail.now = func() time.Time { return now }
+
+	// endedSpans returns a failed child first, so both traces wait for their root.
+	tail.OnEnd(endedSpans(t)[0])
+	now = now.Add(tailSamplingMaxAge + time.Second)
+	tail.OnEnd(endedSpans(t)[0])
+	if len(probe.locked) != 1 {
+		t.Fatalf("expected the stale failed trace to be forwarded on eviction, got %d spans", len(probe.locked))
+	}
+	if err := tail.Shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+	if len(probe.locked) != 2 {
+		t.Fatalf("expected the pending failed trace to be forwarded on shutdown, got %d spans", len(probe.locked))
+	}
+	for i, locked := range probe.locked {
+		if locked {
+			t.Fatalf("span %d was forwarded while holding the tail sampler lock", i)
+		}
+	}
+}
+
+func TestTailSamplingKeepsForceSampledTraces(t *testing.T) {
+	cfg := Config{
+		ServiceName:   "svc",
+		Exporter:      ExporterMemory,
+		SamplingRatio: Float64(0),
+		TailSampling:  &TailSamplingConfig{LatencyThreshold: time.Hour},
+	}
+	prov, err := Setup(context.Background(), cfg, nil)
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	defer prov.Shutdown(context.Background())
+
+	ctx, root := prov.TP.Tracer("test").Start(ForceSample(context.Background()), "debug")
+	_, child := prov.TP.Tracer("test").Start(ctx, "debug/child")
+	child.End()
+	root.End()
+	_ = prov.TP.ForceFlush(context.Background())
+	if got := len(prov.RecordedSpans()); got != 2 {
+		t.Fatalf("expected the fast force-sampled trace to be kept in full, got %d spans", got)
+	}
+}
+
+func TestTailSamplingKeepsCanary(t *testing.T) {
+	cfg := Config{
+		ServiceName:   "svc",
+		Exporter:      ExporterMemory,
+		SamplingRatio: Float64(1),
+		TailSampling:  &TailSamplingConfig{LatencyThreshold: time.Hour},
+	}
+	prov, err := Setup(context.Background(), cfg, nil)
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	defer prov.Shutdown(context.Background())
+
+	results := make(chan CanaryResult, 4)
+	stop, err := prov.StartCanary(10*time.Millisecond, func(r CanaryResult) { results <- r })
+	if err != nil {
+		t.Fatalf("start canary: %v", err)
+	}
+	result := <-results
+	stop()
+	if result.Err != nil {
+		t.Fatalf("expected the canary to pass tail sampling, got %v", result.Err)
+	}
+}
@@ -0,0 +1,28 @@
+package otelx
+
+import (
+	"context"
+
+	logx "github.com/bionicotaku/lingo-utils-logx"
+)
+
+// Telemetry bundles the traces, metrics and logs providers created by SetupTelemetry. Its
+// Shutdown flushes and closes traces, then metrics, then logs, so records logged while spans and
+// metrics drain are still exported. All Provider methods are available.
+type Telemetry struct {
+	*Provider
+}
+
+// SetupTelemetry is Setup with Config.Metrics and Config.Logs turned on: one call builds TP, MP
+// and LP over a shared resource and exporter, and one deferred Shutdown releases all three. MP and
+// LP stay nil when telemetry is disabled or an external TracerProvider is used via
+// WithTracerProvider.
+func SetupTelemetry(ctx context.Context, cfg Config, logger logx.Logger, opts ...Option) (*Telemetry, error) {
+	cfg.Metrics = true
+	cfg.Logs = true
+	p, err := Setup(ctx, cfg, logger, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &Telemetry{Provider: p}, nil
+}
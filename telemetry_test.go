@@ -0,0 +1,48 @@
+package otelx
+
+import (
+	"context"
+	"testing"
+
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestSetupTelemetryBuildsAllSignals(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	logs := &memoryLogExporter{}
+	cfg := Config{ServiceName: "svc", Exporter: ExporterMemory, SamplingRatio: Float64(1)}
+	tel, err := SetupTelemetry(context.Background(), cfg, nil,
+		WithMetricReader(reader), WithLogProcessor(sdklog.NewSimpleProcessor(logs)))
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	if tel.TP == nil || tel.MP == nil || tel.LP == nil || tel.Propagator == nil {
+		t.Fatalf("expected every signal, got %+v", tel.Provider)
+	}
+
+	if err := tel.Shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown failed: %v", err)
+	}
+	if err := reader.Collect(context.Background(), &metricdata.ResourceMetrics{}); err == nil {
+		t.Fatalf("expected metric reader to shut down with telemetry")
+	}
+	if !logs.shutdown {
+		t.Fatalf("expected log pipeline to shut down with telemetry")
+	}
+}
+
+func TestSetupTelemetryUsesTraceEndpoint(t *testing.T) {
+	cfg := Config{ServiceName: "svc", Exporter: ExporterOTLP, Endpoint: "localhost:4317"}
+	tel, err := SetupTelemetry(context.Background(), cfg, nil)
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	if tel.MP == nil || tel.LP == nil {
+		t.Fatalf("expected MeterProvider and LoggerProvider")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_ = tel.Shutdown(ctx)
+}
@@ -0,0 +1,36 @@
+package otelx
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TimerDelayKey records the scheduled delay of a timer span in milliseconds.
+const TimerDelayKey = attribute.Key("timer.delay_ms")
+
+// AfterFunc waits for d and then calls fn in its own goroutine, like time.AfterFunc. fn runs inside a
+// new root span linked to the span active in ctx when the timer was scheduled, so delayed work
+// (session expiry, delayed retries) stays attributable to the trace that scheduled it. The context
+// passed to fn carries ctx's baggage but not its deadline or cancellation.
+func AfterFunc(ctx context.Context, d time.Duration, fn func(ctx context.Context)) *time.Timer {
+	scheduler := trace.SpanContextFromContext(ctx)
+	bag := baggage.FromContext(ctx)
+	return time.AfterFunc(d, func() {
+		opts := []trace.SpanStartOption{
+			trace.WithNewRoot(),
+			trace.WithAttributes(TimerDelayKey.Int64(d.Milliseconds())),
+		}
+		if scheduler.IsValid() {
+			opts = append(opts, trace.WithLinks(trace.Link{SpanContext: scheduler}))
+		}
+		runCtx := baggage.ContextWithBaggage(context.Background(), bag)
+		runCtx, span := otel.Tracer(instrumentationName).Start(runCtx, "AfterFunc", opts...)
+		defer span.End()
+		fn(runCtx)
+	})
+}
@@ -0,0 +1,51 @@
+package otelx
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestAfterFuncLinksToSchedulingSpan(t *testing.T) {
+	restore := saveGlobal()
+	defer restore()
+
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	otel.SetTracerProvider(tp)
+
+	ctx, parent := tp.Tracer("test").Start(context.Background(), "schedule")
+	done := make(chan trace.SpanContext, 1)
+	AfterFunc(ctx, time.Millisecond, func(ctx context.Context) {
+		done <- trace.SpanContextFromContext(ctx)
+	})
+	parent.End()
+
+	var fired trace.SpanContext
+	select {
+	case fired = <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("timer did not fire")
+	}
+	if fired.TraceID() == parent.SpanContext().TraceID() {
+		t.Fatalf("expected timer span to start a new trace")
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for len(recorder.Ended()) < 2 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	ended := recorder.Ended()
+	if len(ended) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(ended))
+	}
+	links := ended[1].Links()
+	if len(links) != 1 || links[0].SpanContext.SpanID() != parent.SpanContext().SpanID() {
+		t.Fatalf("expected timer span to link to the scheduling span, got %v", links)
+	}
+}
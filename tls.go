@@ -0,0 +1,99 @@
+package otelx
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// TLSConfig secures the connection of the otlp and otlphttp exporters, and of the metrics and logs
+// sharing their pipeline, e.g. to a collector requiring mutual TLS. Each certificate and key is
+// given either as a file path or as a PEM blob; unset fields fall back to the system roots and no
+// client certificate.
+type TLSConfig struct {
+	// CAFile or CAPEM holds the CA certificates used to verify the collector instead of the system
+	// roots.
+	CAFile string `json:"caFile"`
+	CAPEM  string `json:"caPem"`
+	// CertFile/CertPEM and KeyFile/KeyPEM hold the client certificate and private key presented
+	// for mutual TLS; they must be set together.
+	CertFile string `json:"certFile"`
+	CertPEM  string `json:"certPem"`
+	KeyFile  string `json:"keyFile"`
+	KeyPEM   string `json:"keyPem"`
+	// ServerName overrides the host name verified against the collector's certificate, e.g. when
+	// connecting through an IP address or a proxy.
+	ServerName string `json:"serverName"`
+}
+
+func (c TLSConfig) validate() error {
+	if c.CAFile != "" && c.CAPEM != "" {
+		return errors.New("caFile and caPem are mutually exclusive")
+	}
+	if c.CertFile != "" && c.CertPEM != "" {
+		return errors.New("certFile and certPem are mutually exclusive")
+	}
+	if c.KeyFile != "" && c.KeyPEM != "" {
+		return errors.New("keyFile and keyPem are mutually exclusive")
+	}
+	if (c.CertFile != "" || c.CertPEM != "") != (c.KeyFile != "" || c.KeyPEM != "") {
+		return errors.New("client certificate and key must be set together")
+	}
+	return nil
+}
+
+func (c TLSConfig) sanitize() TLSConfig {
+	c.CAFile = strings.TrimSpace(c.CAFile)
+	c.CertFile = strings.TrimSpace(c.CertFile)
+	c.KeyFile = strings.TrimSpace(c.KeyFile)
+	c.ServerName = strings.TrimSpace(c.ServerName)
+	return c
+}
+
+// tlsConfig loads the certificates and returns the client TLS settings.
+func (c TLSConfig) tlsConfig() (*tls.Config, error) {
+	cfg := &tls.Config{MinVersion: tls.VersionTLS12, ServerName: c.ServerName}
+
+	ca, err := pemOrFile(c.CAPEM, c.CAFile)
+	if err != nil {
+		return nil, fmt.Errorf("read ca: %w", err)
+	}
+	if len(ca) > 0 {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(ca) {
+			return nil, errors.New("ca contains no PEM certificates")
+		}
+		cfg.RootCAs = pool
+	}
+
+	cert, err := pemOrFile(c.CertPEM, c.CertFile)
+	if err != nil {
+		return nil, fmt.Errorf("read client certificate: %w", err)
+	}
+	key, err := pemOrFile(c.KeyPEM, c.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("read client key: %w", err)
+	}
+	if len(cert) > 0 {
+		pair, err := tls.X509KeyPair(cert, key)
+		if err != nil {
+			return nil, fmt.Errorf("load client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{pair}
+	}
+	return cfg, nil
+}
+
+// pemOrFile returns blob, or the content of path when blob is empty.
+func pemOrFile(blob, path string) ([]byte, error) {
+	if blob != "" {
+		return []byte(blob), nil
+	}
+	if path == "" {
+		return nil, nil
+	}
+	return os.ReadFile(path)
+}
@@ -0,0 +1,140 @@
+package otelx
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type testCert struct {
+	cert    *x509.Certificate
+	key     *ecdsa.PrivateKey
+	certPEM string
+	keyPEM  string
+}
+
+func newTestCert(t *testing.T, name string, parent *testCert, usage x509.ExtKeyUsage) *testCert {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+	}
+	signer, signerKey := tmpl, key
+	if parent == nil {
+		tmpl.IsCA, tmpl.BasicConstraintsValid, tmpl.KeyUsage = true, true, x509.KeyUsageCertSign
+	} else {
+		signer, signerKey = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, signer, &key.PublicKey, signerKey)
+	if err != nil {
+		t.Fatalf("create certificate: %v", err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+	keyDER, _ := x509.MarshalECPrivateKey(key)
+	return &testCert{
+		cert:    cert,
+		key:     key,
+		certPEM: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		keyPEM:  string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})),
+	}
+}
+
+func TestTLSMutualAuth(t *testing.T) {
+	ca := newTestCert(t, "test-ca", nil, x509.ExtKeyUsageAny)
+	server := newTestCert(t, "collector.internal", ca, x509.ExtKeyUsageServerAuth)
+	client := newTestCert(t, "svc", ca, x509.ExtKeyUsageClientAuth)
+
+	pool := x509.NewCertPool()
+	pool.AddCert(ca.cert)
+	serverPair, err := tls.X509KeyPair([]byte(server.certPEM), []byte(server.keyPEM))
+	if err != nil {
+		t.Fatalf("server key pair: %v", err)
+	}
+	clients := make(chan string, 1)
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		clients <- r.TLS.PeerCertificates[0].Subject.CommonName
+	}))
+	srv.TLS = &tls.Config{Certificates: []tls.Certificate{serverPair}, ClientCAs: pool, ClientAuth: tls.RequireAndVerifyClientCert}
+	srv.StartTLS()
+	defer srv.Close()
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(caFile, []byte(ca.certPEM), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: trace.TraceID{1}, SpanID: trace.SpanID{1}, TraceFlags: trace.FlagsSampled,
+	})
+	spans := []sdktrace.ReadOnlySpan{tracetest.SpanStub{Name: "op", SpanContext: sc}.Snapshot()}
+	_, port, _ := net.SplitHostPort(strings.TrimPrefix(srv.URL, "https://"))
+	ec := ExporterConfig{
+		Exporter: ExporterOTLPHTTP,
+		Endpoint: "127.0.0.1:" + port,
+		TLS:      &TLSConfig{CAFile: caFile, CertPEM: client.certPEM, KeyPEM: client.keyPEM, ServerName: "collector.internal"},
+	}
+	exporter, err := buildExporter(context.Background(), ec, nil)
+	if err != nil {
+		t.Fatalf("build exporter: %v", err)
+	}
+	defer exporter.Shutdown(context.Background())
+	if err := exporter.ExportSpans(context.Background(), spans); err != nil {
+		t.Fatalf("export over mTLS failed: %v", err)
+	}
+	if got := <-clients; got != "svc" {
+		t.Fatalf("expected the client certificate to be presented, got %q", got)
+	}
+}
+
+func TestTLSValidation(t *testing.T) {
+	cases := map[string]Config{
+		"insecure":    {ServiceName: "svc", Exporter: ExporterOTLP, Insecure: true, TLS: &TLSConfig{ServerName: "x"}},
+		"non-otlp":    {ServiceName: "svc", Exporter: ExporterZipkin, TLS: &TLSConfig{ServerName: "x"}},
+		"ca-twice":    {ServiceName: "svc", Exporter: ExporterOTLP, TLS: &TLSConfig{CAFile: "ca.pem", CAPEM: "pem"}},
+		"cert-no-key": {ServiceName: "svc", Exporter: ExporterOTLP, TLS: &TLSConfig{CertFile: "cert.pem"}},
+		"missing-ca":  {ServiceName: "svc", Exporter: ExporterOTLP, TLS: &TLSConfig{CAFile: filepath.Join(t.TempDir(), "missing.pem")}},
+		"bad-ca":      {ServiceName: "svc", Exporter: ExporterOTLPHTTP, TLS: &TLSConfig{CAPEM: "not a certificate"}},
+	}
+	for name, cfg := range cases {
+		if _, err := Setup(context.Background(), cfg, nil); err == nil {
+			t.Fatalf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestDiffConfigRedactsTLSKey(t *testing.T) {
+	old := Config{ServiceName: "svc", Exporter: ExporterOTLP, TLS: &TLSConfig{CertPEM: "cert", KeyPEM: "secret-1"}}
+	updated := Config{ServiceName: "svc", Exporter: ExporterOTLP, TLS: &TLSConfig{CertPEM: "cert", KeyPEM: "secret-2"}}
+	changes := DiffConfig(old, updated)
+	if len(changes) != 1 {
+		t.Fatalf("expected one change, got %+v", changes)
+	}
+	if strings.Contains(changes[0].Old+changes[0].New, "secret") {
+		t.Fatalf("expected the key to be redacted, got %+v", changes[0])
+	}
+}
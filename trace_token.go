@@ -0,0 +1,134 @@
+package otelx
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// traceTokenVersion is the first byte of every trace reference token; it is also authenticated
+// as additional data, so a token cannot be replayed under another layout.
+const traceTokenVersion byte = 1
+
+// traceTokenPayload is trace id, span id, trace flags and expiry (unix seconds).
+const traceTokenPayload = 16 + 8 + 1 + 8
+
+// DefaultTraceTokenTTL is how long trace reference tokens stay valid when NewTraceTokens gets no TTL.
+const DefaultTraceTokenTTL = 7 * 24 * time.Hour
+
+var (
+	// ErrTraceTokenInvalid is returned for tokens that are malformed, tampered with or minted with
+	// another secret.
+	ErrTraceTokenInvalid = errors.New("otelx: invalid trace token")
+	// ErrTraceTokenExpired is returned for authentic tokens past their TTL.
+	ErrTraceTokenExpired = errors.New("otelx: trace token expired")
+)
+
+// TraceTokens mints short-lived trace reference tokens for support flows: an error page or email
+// shows the token, and support tooling holding the same secret exchanges it for the trace URL.
+// Tokens are encrypted and authenticated, so end users see neither the trace id nor a value they
+// can forge.
+type TraceTokens struct {
+	aead        cipher.AEAD
+	ttl         time.Duration
+	urlTemplate string
+	now         func() time.Time
+}
+
+// NewTraceTokens creates a minter/resolver from secret (at least 16 bytes, shared by all services
+// and the support tooling). urlTemplate is the trace viewer URL with {traceId} and optionally
+// {spanId} placeholders, e.g. "https://grafana.example.com/explore?traceId={traceId}"; it may be
+// empty on services that only mint. A ttl of zero means DefaultTraceTokenTTL.
+func NewTraceTokens(secret []byte, ttl time.Duration, urlTemplate string) (*TraceTokens, error) {
+	if len(secret) < 16 {
+		return nil, fmt.Errorf("otelx: trace token secret must be at least 16 bytes")
+	}
+	if ttl < 0 {
+		return nil, fmt.Errorf("otelx: trace token ttl must not be negative")
+	}
+	if ttl == 0 {
+		ttl = DefaultTraceTokenTTL
+	}
+	if urlTemplate != "" && !strings.Contains(urlTemplate, "{traceId}") {
+		return nil, fmt.Errorf("otelx: trace URL template must contain {traceId}")
+	}
+	key := sha256.Sum256(secret)
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, fmt.Errorf("otelx: trace tokens: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("otelx: trace tokens: %w", err)
+	}
+	return &TraceTokens{aead: aead, ttl: ttl, urlTemplate: urlTemplate, now: time.Now}, nil
+}
+
+// Mint returns a URL-safe token for the span active in ctx, or ErrNoSpanContext.
+func (t *TraceTokens) Mint(ctx context.Context) (string, error) {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return "", ErrNoSpanContext
+	}
+	payload := make([]byte, traceTokenPayload)
+	traceID, spanID := sc.TraceID(), sc.SpanID()
+	copy(payload[0:16], traceID[:])
+	copy(payload[16:24], spanID[:])
+	payload[24] = byte(sc.TraceFlags())
+	binary.BigEndian.PutUint64(payload[25:], uint64(t.now().Add(t.ttl).Unix()))
+
+	nonceSize := t.aead.NonceSize()
+	buf := make([]byte, 1+nonceSize, 1+nonceSize+traceTokenPayload+t.aead.Overhead())
+	buf[0] = traceTokenVersion
+	if _, err := rand.Read(buf[1:]); err != nil {
+		return "", fmt.Errorf("otelx: mint trace token: %w", err)
+	}
+	buf = t.aead.Seal(buf, buf[1:], payload, buf[:1])
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// Resolve returns the span context a token was minted for. It fails with ErrTraceTokenInvalid or
+// ErrTraceTokenExpired.
+func (t *TraceTokens) Resolve(token string) (trace.SpanContext, error) {
+	buf, err := base64.RawURLEncoding.DecodeString(token)
+	nonceSize := t.aead.NonceSize()
+	if err != nil || len(buf) < 1+nonceSize || buf[0] != traceTokenVersion {
+		return trace.SpanContext{}, ErrTraceTokenInvalid
+	}
+	payload, err := t.aead.Open(nil, buf[1:1+nonceSize], buf[1+nonceSize:], buf[:1])
+	if err != nil || len(payload) != traceTokenPayload {
+		return trace.SpanContext{}, ErrTraceTokenInvalid
+	}
+	if expiry := time.Unix(int64(binary.BigEndian.Uint64(payload[25:])), 0); !t.now().Before(expiry) {
+		return trace.SpanContext{}, ErrTraceTokenExpired
+	}
+	cfg := trace.SpanContextConfig{TraceFlags: trace.TraceFlags(payload[24]), Remote: true}
+	copy(cfg.TraceID[:], payload[0:16])
+	copy(cfg.SpanID[:], payload[16:24])
+	return trace.NewSpanContext(cfg), nil
+}
+
+// URL resolves token and fills the trace URL template.
+func (t *TraceTokens) URL(token string) (string, error) {
+	if t.urlTemplate == "" {
+		return "", fmt.Errorf("otelx: no trace URL template configured")
+	}
+	sc, err := t.Resolve(token)
+	if err != nil {
+		return "", err
+	}
+	return strings.NewReplacer(
+		"{traceId}", sc.TraceID().String(),
+		"{spanId}", sc.SpanID().String(),
+	).Replace(t.urlTemplate), nil
+}
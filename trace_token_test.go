@@ -0,0 +1,90 @@
+package otelx
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTraceTokensRoundTrip(t *testing.T) {
+	tokens, err := NewTraceTokens([]byte("0123456789abcdef"), time.Hour, "https://tempo.example.com/trace/{traceId}?span={spanId}")
+	if err != nil {
+		t.Fatalf("new trace tokens: %v", err)
+	}
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36},
+		SpanID:     trace.SpanID{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7},
+		TraceFlags: trace.FlagsSampled,
+	})
+	ctx := trace.ContextWithSpanContext(context.Background(), sc)
+
+	token, err := tokens.Mint(ctx)
+	if err != nil {
+		t.Fatalf("mint failed: %v", err)
+	}
+	if strings.Contains(token, sc.TraceID().String()) {
+		t.Fatalf("token exposes the trace id: %s", token)
+	}
+	if again, _ := tokens.Mint(ctx); again == token {
+		t.Fatalf("expected a fresh nonce per token")
+	}
+
+	url, err := tokens.URL(token)
+	if err != nil {
+		t.Fatalf("url failed: %v", err)
+	}
+	if url != "https://tempo.example.com/trace/4bf92f3577b34da6a3ce929d0e0e4736?span=00f067aa0ba902b7" {
+		t.Fatalf("unexpected url %q", url)
+	}
+
+	tokens.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+	if _, err := tokens.Resolve(token); !errors.Is(err, ErrTraceTokenExpired) {
+		t.Fatalf("expected expired token, got %v", err)
+	}
+}
+
+func TestTraceTokensRejectForgery(t *testing.T) {
+	tokens, _ := NewTraceTokens([]byte("0123456789abcdef"), 0, "")
+	other, _ := NewTraceTokens([]byte("fedcba9876543210"), 0, "")
+	ctx, span := sdktrace.NewTracerProvider().Tracer("test").Start(context.Background(), "op")
+	defer span.End()
+
+	token, err := tokens.Mint(ctx)
+	if err != nil {
+		t.Fatalf("mint failed: %v", err)
+	}
+	tampered := []byte(token)
+	if tampered[20] == 'A' {
+		tampered[20] = 'B'
+	} else {
+		tampered[20] = 'A'
+	}
+	for name, candidate := range map[string]string{
+		"other secret": token,
+		"tampered":     string(tampered),
+		"garbage":      "not-a-token",
+	} {
+		verifier := tokens
+		if name == "other secret" {
+			verifier = other
+		}
+		if _, err := verifier.Resolve(candidate); !errors.Is(err, ErrTraceTokenInvalid) {
+			t.Fatalf("%s: expected ErrTraceTokenInvalid, got %v", name, err)
+		}
+	}
+
+	if _, err := tokens.Mint(context.Background()); !errors.Is(err, ErrNoSpanContext) {
+		t.Fatalf("expected ErrNoSpanContext, got %v", err)
+	}
+	if _, err := tokens.URL(token); err == nil {
+		t.Fatalf("expected an error without a URL template")
+	}
+	if _, err := NewTraceTokens([]byte("short"), 0, ""); err == nil {
+		t.Fatalf("expected short secrets to be rejected")
+	}
+}
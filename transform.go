@@ -0,0 +1,124 @@
+package otelx
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// spanTransform rewrites an ended span right before export. Returning nil drops the span.
+type spanTransform func(sdktrace.ReadOnlySpan) sdktrace.ReadOnlySpan
+
+// newTransformExporter wraps exporter so every exported span passes through transforms in order.
+func newTransformExporter(exporter sdktrace.SpanExporter, transforms ...spanTransform) sdktrace.SpanExporter {
+	if len(transforms) == 0 {
+		return exporter
+	}
+	return &transformExporter{SpanExporter: exporter, transforms: transforms}
+}
+
+type transformExporter struct {
+	sdktrace.SpanExporter
+	transforms []spanTransform
+}
+
+func (e *transformExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	out := make([]sdktrace.ReadOnlySpan, 0, len(spans))
+	for _, span := range spans {
+		for _, transform := range e.transforms {
+			if span = transform(span); span == nil {
+				break
+			}
+		}
+		if span != nil {
+			out = append(out, span)
+		}
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return e.SpanExporter.ExportSpans(ctx, out)
+}
+
+// overrideSpan presents a ReadOnlySpan with selected fields replaced, so transforms can rewrite
+// spans without copying them. Nil or zero fields fall through to the wrapped span.
+type overrideSpan struct {
+	sdktrace.ReadOnlySpan
+	attrs         []attribute.KeyValue
+	events        []sdktrace.Event
+	droppedEvents int
+	startTime     time.Time
+	endTime       time.Time
+	resource      *resource.Resource
+	spanContext   trace.SpanContext
+}
+
+// override returns span as an *overrideSpan, reusing it when it already is one.
+func override(span sdktrace.ReadOnlySpan) *overrideSpan {
+	if o, ok := span.(*overrideSpan); ok {
+		return o
+	}
+	return &overrideSpan{ReadOnlySpan: span}
+}
+
+// setAttributes replaces the span attributes with a private copy of attrs.
+func (s *overrideSpan) setAttributes(attrs ...attribute.KeyValue) {
+	s.attrs = append(make([]attribute.KeyValue, 0, len(attrs)), attrs...)
+}
+
+// addAttributes appends attrs without touching the wrapped span's attribute slice.
+func (s *overrideSpan) addAttributes(attrs ...attribute.KeyValue) {
+	current := s.Attributes()
+	merged := make([]attribute.KeyValue, 0, len(current)+len(attrs))
+	s.attrs = append(append(merged, current...), attrs...)
+}
+
+func (s *overrideSpan) Attributes() []attribute.KeyValue {
+	if s.attrs != nil {
+		return s.attrs
+	}
+	return s.ReadOnlySpan.Attributes()
+}
+
+func (s *overrideSpan) Events() []sdktrace.Event {
+	if s.events != nil {
+		return s.events
+	}
+	return s.ReadOnlySpan.Events()
+}
+
+func (s *overrideSpan) DroppedEvents() int {
+	return s.ReadOnlySpan.DroppedEvents() + s.droppedEvents
+}
+
+func (s *overrideSpan) StartTime() time.Time {
+	if !s.startTime.IsZero() {
+		return s.startTime
+	}
+	return s.ReadOnlySpan.StartTime()
+}
+
+func (s *overrideSpan) EndTime() time.Time {
+	if !s.endTime.IsZero() {
+		return s.endTime
+	}
+	return s.ReadOnlySpan.EndTime()
+}
+
+func (s *overrideSpan) Resource() *resource.Resource {
+	if s.resource != nil {
+		return s.resource
+	}
+	return s.ReadOnlySpan.Resource()
+}
+
+func (s *overrideSpan) SpanContext() trace.SpanContext {
+	if s.spanContext.IsValid() {
+		return s.spanContext
+	}
+	return s.ReadOnlySpan.SpanContext()
+}
@@ -0,0 +1,120 @@
+package otelx
+
+import (
+	"errors"
+	"fmt"
+	"unicode/utf8"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// TruncatedKeySuffix is appended to an attribute key to name its companion "was truncated" attribute.
+const TruncatedKeySuffix = "_truncated"
+
+// TruncatedKey marks spans with at least one truncated attribute value, so they can be found with a
+// single query.
+const TruncatedKey = attribute.Key("otelx.truncated")
+
+// TruncationConfig cuts string attribute values longer than MaxBytes at export time, e.g. to keep
+// megabyte-sized SQL statements out of exports.
+type TruncationConfig struct {
+	MaxBytes int `json:"maxBytes"`
+	// Marker is appended to cut values, e.g. "…(truncated)". Empty appends "…(+N bytes)" with the
+	// number of bytes removed.
+	Marker string `json:"marker"`
+}
+
+func (c TruncationConfig) validate() error {
+	if c.MaxBytes <= 0 {
+		return errors.New("maxBytes must be positive")
+	}
+	return nil
+}
+
+// attrTruncator cuts string attribute values to maxBytes and marks what was removed.
+type attrTruncator struct {
+	maxBytes int
+	marker   string
+}
+
+// truncate shortens string (and string slice) attribute values longer than maxBytes. Each cut value
+// ends with the marker and gains a companion <key>_truncated=true attribute; the span gains
+// otelx.truncated=true.
+func (t attrTruncator) truncate(span sdktrace.ReadOnlySpan) sdktrace.ReadOnlySpan {
+	attrs := span.Attributes()
+	var out, companions []attribute.KeyValue
+	for i, kv := range attrs {
+		value, cut := t.truncateValue(kv.Value)
+		if !cut {
+			if out != nil {
+				out = append(out, kv)
+			}
+			continue
+		}
+		if out == nil {
+			out = append(make([]attribute.KeyValue, 0, len(attrs)), attrs[:i]...)
+		}
+		out = append(out, attribute.KeyValue{Key: kv.Key, Value: value})
+		companions = append(companions, attribute.Bool(string(kv.Key)+TruncatedKeySuffix, true))
+	}
+	if out == nil {
+		return span
+	}
+	o := override(span)
+	o.setAttributes(append(append(out, companions...), TruncatedKey.Bool(true))...)
+	return o
+}
+
+func (t attrTruncator) truncateValue(v attribute.Value) (attribute.Value, bool) {
+	switch v.Type() {
+	case attribute.STRING:
+		if s, cut := t.truncateString(v.AsString()); cut {
+			return attribute.StringValue(s), true
+		}
+	case attribute.STRINGSLICE:
+		values := v.AsStringSlice()
+		changed := false
+		for i, s := range values {
+			if truncated, cut := t.truncateString(s); cut {
+				values[i] = truncated
+				changed = true
+			}
+		}
+		if changed {
+			return attribute.StringSliceValue(values), true
+		}
+	}
+	return v, false
+}
+
+// truncateString truncates s like truncateBytes, appending the marker instead of the byte count
+// when one is set.
+func (t attrTruncator) truncateString(s string) (string, bool) {
+	if t.marker == "" {
+		return truncateBytes(s, t.maxBytes)
+	}
+	if len(s) <= t.maxBytes {
+		return s, false
+	}
+	return s[:runeCut(s, t.maxBytes)] + t.marker, true
+}
+
+// truncateBytes keeps at most maxBytes of s, backing off to a rune boundary, and appends a marker
+// with the number of bytes removed.
+func truncateBytes(s string, maxBytes int) (string, bool) {
+	if len(s) <= maxBytes {
+		return s, false
+	}
+	cut := runeCut(s, maxBytes)
+	return fmt.Sprintf("%s…(+%d bytes)", s[:cut], len(s)-cut), true
+}
+
+// runeCut returns the largest rune boundary of s at or below maxBytes (< len(s)).
+func runeCut(s string, maxBytes int) int {
+	cut := maxBytes
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return cut
+}
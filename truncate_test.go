@@ -0,0 +1,93 @@
+package otelx
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestAttributeTruncationMarksCutValues(t *testing.T) {
+	mem := tracetest.NewInMemoryExporter()
+	exporter := newTransformExporter(mem, attrTruncator{maxBytes: 8}.truncate)
+
+	span := spanWithAttributes(t,
+		attribute.String("db.statement", strings.Repeat("x", 20)),
+		attribute.String("short", "ok"),
+		attribute.String("unicode", "ééééé"),
+	)
+	if err := exporter.ExportSpans(context.Background(), []sdktrace.ReadOnlySpan{span}); err != nil {
+		t.Fatalf("export failed: %v", err)
+	}
+
+	attrs := mem.GetSpans()[0].Attributes
+	if !spanHasAttribute(attrs, "db.statement", "xxxxxxxx…(+12 bytes)") {
+		t.Fatalf("expected truncated statement with marker, got %v", attrs)
+	}
+	if !spanHasAttribute(attrs, "db.statement_truncated", "true") {
+		t.Fatalf("expected companion truncated attribute, got %v", attrs)
+	}
+	if !spanHasAttribute(attrs, TruncatedKey, "true") {
+		t.Fatalf("expected the span to be marked truncated, got %v", attrs)
+	}
+	if !spanHasAttribute(attrs, "short", "ok") || spanHasAttribute(attrs, "short_truncated", "true") {
+		t.Fatalf("expected short value to be untouched, got %v", attrs)
+	}
+	if !spanHasAttribute(attrs, "unicode", "éééé…(+2 bytes)") {
+		t.Fatalf("expected truncation on a rune boundary, got %v", attrs)
+	}
+	if orig := span.Attributes(); !spanHasAttribute(orig, "db.statement", strings.Repeat("x", 20)) {
+		t.Fatalf("expected original span to be left intact")
+	}
+}
+
+func TestTruncationConfig(t *testing.T) {
+	cfg := Config{
+		ServiceName:   "svc",
+		Exporter:      ExporterMemory,
+		SamplingRatio: Float64(1),
+		Truncation:    &TruncationConfig{MaxBytes: 6, Marker: "…(truncated)"},
+	}
+	prov, err := Setup(context.Background(), cfg, nil)
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	defer prov.Shutdown(context.Background())
+
+	_, span := prov.TP.Tracer("test").Start(context.Background(), "kept")
+	span.SetAttributes(attribute.String("db.statement", "SELECT * FROM videos"))
+	span.End()
+	_, span = prov.TP.Tracer("test").Start(context.Background(), "short")
+	span.SetAttributes(attribute.String("db.statement", "SELECT"))
+	span.End()
+
+	spans := prov.RecordedSpans()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+	attrs := spans[0].Attributes
+	if !spanHasAttribute(attrs, "db.statement", "SELECT…(truncated)") || !spanHasAttribute(attrs, TruncatedKey, "true") {
+		t.Fatalf("expected a marked, truncated statement, got %v", attrs)
+	}
+	if spanHasAttribute(spans[1].Attributes, TruncatedKey, "true") {
+		t.Fatalf("expected short values to leave the span unmarked")
+	}
+
+	cfg.Truncation = &TruncationConfig{}
+	if _, err := Setup(context.Background(), cfg, nil); err == nil || !strings.Contains(err.Error(), "otelx: truncation: maxBytes must be positive") {
+		t.Fatalf("expected a validation error, got %v", err)
+	}
+}
+
+func spanWithAttributes(t *testing.T, attrs ...attribute.KeyValue) sdktrace.ReadOnlySpan {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	_, span := tp.Tracer("test").Start(context.Background(), "span")
+	span.SetAttributes(attrs...)
+	span.End()
+	return recorder.Ended()[0]
+}
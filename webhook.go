@@ -0,0 +1,92 @@
+package otelx
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// maxWebhookPeek bounds how much of a webhook body is buffered to look for a traceparent.
+const maxWebhookPeek = 1 << 20
+
+// WebhookExtractor returns the W3C traceparent (and optional tracestate) that the originating
+// service stored in a webhook delivery. body holds up to the first 1 MiB of the request body.
+type WebhookExtractor func(r *http.Request, body []byte) (traceparent, tracestate string)
+
+// JSONFieldExtractor reads the traceparent from a dotted path in a JSON body, e.g.
+// "metadata.traceparent". A sibling "tracestate" field is picked up when present.
+func JSONFieldExtractor(path string) WebhookExtractor {
+	keys := strings.Split(path, ".")
+	return func(_ *http.Request, body []byte) (string, string) {
+		var doc map[string]any
+		if err := json.Unmarshal(body, &doc); err != nil {
+			return "", ""
+		}
+		for _, key := range keys[:len(keys)-1] {
+			next, ok := doc[key].(map[string]any)
+			if !ok {
+				return "", ""
+			}
+			doc = next
+		}
+		traceparent, _ := doc[keys[len(keys)-1]].(string)
+		tracestate, _ := doc["tracestate"].(string)
+		return traceparent, tracestate
+	}
+}
+
+// StartWebhookSpan starts a new root server span named name and links it to the trace identified by
+// traceparent/tracestate. Webhooks break parentage by design, so the originating trace is linked
+// rather than continued. Invalid or empty traceparents yield an unlinked root span.
+func StartWebhookSpan(ctx context.Context, name, traceparent, tracestate string) (context.Context, trace.Span) {
+	opts := []trace.SpanStartOption{trace.WithNewRoot(), trace.WithSpanKind(trace.SpanKindServer)}
+	if origin := parseTraceparent(traceparent, tracestate); origin.IsValid() {
+		opts = append(opts, trace.WithLinks(trace.Link{SpanContext: origin}))
+	}
+	return otel.Tracer(instrumentationName).Start(ctx, name, opts...)
+}
+
+// WebhookHandler wraps a webhook receiver so every delivery runs in a new root span linked to the
+// trace recorded in the payload by extract. The request body is left intact for next.
+func WebhookHandler(operation string, extract WebhookExtractor, next http.Handler) http.Handler {
+	if operation == "" {
+		operation = "webhook"
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var traceparent, tracestate string
+		if extract != nil {
+			var peek []byte
+			if r.Body != nil {
+				peek, _ = io.ReadAll(io.LimitReader(r.Body, maxWebhookPeek))
+				r.Body = readCloser{Reader: io.MultiReader(bytes.NewReader(peek), r.Body), Closer: r.Body}
+			}
+			traceparent, tracestate = extract(r, peek)
+		}
+		ctx, span := StartWebhookSpan(r.Context(), operation, traceparent, tracestate)
+		defer span.End()
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func parseTraceparent(traceparent, tracestate string) trace.SpanContext {
+	if traceparent == "" {
+		return trace.SpanContext{}
+	}
+	carrier := propagation.MapCarrier{"traceparent": traceparent}
+	if tracestate != "" {
+		carrier["tracestate"] = tracestate
+	}
+	return trace.SpanContextFromContext(propagation.TraceContext{}.Extract(context.Background(), carrier))
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
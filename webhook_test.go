@@ -0,0 +1,63 @@
+package otelx
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestWebhookHandlerLinksOriginatingTrace(t *testing.T) {
+	restore := saveGlobal()
+	defer restore()
+
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	const payload = `{"event":"paid","metadata":{"traceparent":"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"}}`
+	var seenBody string
+	handler := WebhookHandler("payments.webhook", JSONFieldExtractor("metadata.traceparent"),
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			seenBody = string(body)
+		}))
+	req := httptest.NewRequest(http.MethodPost, "http://localhost/webhook", strings.NewReader(payload))
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	if seenBody != payload {
+		t.Fatalf("expected body to be preserved, got %q", seenBody)
+	}
+	ended := recorder.Ended()
+	if len(ended) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(ended))
+	}
+	span := ended[0]
+	if span.Parent().IsValid() {
+		t.Fatalf("expected webhook span to be a root span")
+	}
+	links := span.Links()
+	if len(links) != 1 || links[0].SpanContext.TraceID().String() != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Fatalf("expected link to originating trace, got %v", links)
+	}
+}
+
+func TestWebhookHandlerWithoutTraceparent(t *testing.T) {
+	restore := saveGlobal()
+	defer restore()
+
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	handler := WebhookHandler("", JSONFieldExtractor("metadata.traceparent"), http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "http://localhost/", strings.NewReader(`not json`)))
+
+	ended := recorder.Ended()
+	if len(ended) != 1 || len(ended[0].Links()) != 0 || ended[0].Name() != "webhook" {
+		t.Fatalf("expected unlinked default-named span, got %v", ended)
+	}
+}
@@ -0,0 +1,231 @@
+package otelx
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/xray"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// xrayBatchSize is the PutTraceSegments limit on documents per call.
+	xrayBatchSize = 50
+	// xrayMaxAnnotations is the X-Ray limit on indexed annotations per segment.
+	xrayMaxAnnotations = 50
+)
+
+// xrayAPI is the subset of the X-Ray client used by xrayExporter.
+type xrayAPI interface {
+	PutTraceSegments(ctx context.Context, in *xray.PutTraceSegmentsInput, opts ...func(*xray.Options)) (*xray.PutTraceSegmentsOutput, error)
+}
+
+// xrayExporter converts spans to X-Ray segment documents and sends them with PutTraceSegments,
+// so services can report to X-Ray without running the X-Ray daemon or a collector.
+type xrayExporter struct {
+	client xrayAPI
+}
+
+func newXRayExporter(client xrayAPI) *xrayExporter {
+	return &xrayExporter{client: client}
+}
+
+func (e *xrayExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	docs := make([]string, 0, len(spans))
+	for _, span := range spans {
+		doc, err := json.Marshal(xraySegmentFromSpan(span))
+		if err != nil {
+			return fmt.Errorf("otelx: encode xray segment: %w", err)
+		}
+		docs = append(docs, string(doc))
+	}
+	for start := 0; start < len(docs); start += xrayBatchSize {
+		end := min(start+xrayBatchSize, len(docs))
+		out, err := e.client.PutTraceSegments(ctx, &xray.PutTraceSegmentsInput{TraceSegmentDocuments: docs[start:end]})
+		if err != nil {
+			return fmt.Errorf("otelx: put xray segments: %w", err)
+		}
+		if len(out.UnprocessedTraceSegments) > 0 {
+			first := out.UnprocessedTraceSegments[0]
+			return fmt.Errorf("otelx: xray rejected %d segments: %s", len(out.UnprocessedTraceSegments), aws.ToString(first.Message))
+		}
+	}
+	return nil
+}
+
+func (e *xrayExporter) Shutdown(context.Context) error {
+	return nil
+}
+
+// xraySegment is the X-Ray segment document format, see
+// https://docs.aws.amazon.com/xray/latest/devguide/xray-api-segmentdocuments.html.
+type xraySegment struct {
+	Name        string                    `json:"name"`
+	ID          string                    `json:"id"`
+	TraceID     string                    `json:"trace_id"`
+	ParentID    string                    `json:"parent_id,omitempty"`
+	Type        string                    `json:"type,omitempty"`
+	Namespace   string                    `json:"namespace,omitempty"`
+	StartTime   float64                   `json:"start_time"`
+	EndTime     float64                   `json:"end_time"`
+	Fault       bool                      `json:"fault,omitempty"`
+	Error       bool                      `json:"error,omitempty"`
+	HTTP        *xrayHTTP                 `json:"http,omitempty"`
+	Annotations map[string]any            `json:"annotations,omitempty"`
+	Metadata    map[string]map[string]any `json:"metadata,omitempty"`
+}
+
+type xrayHTTP struct {
+	Request  *xrayHTTPRequest  `json:"request,omitempty"`
+	Response *xrayHTTPResponse `json:"response,omitempty"`
+}
+
+type xrayHTTPRequest struct {
+	Method string `json:"method,omitempty"`
+	URL    string `json:"url,omitempty"`
+}
+
+type xrayHTTPResponse struct {
+	Status int64 `json:"status,omitempty"`
+}
+
+// xraySegmentFromSpan maps server spans and local roots to segments, and every other span to an
+// independent subsegment of its parent.
+func xraySegmentFromSpan(span sdktrace.ReadOnlySpan) xraySegment {
+	sc := span.SpanContext()
+	seg := xraySegment{
+		Name:      span.Name(),
+		ID:        sc.SpanID().String(),
+		TraceID:   xrayTraceID(sc.TraceID()),
+		StartTime: xrayTime(span.StartTime().UnixNano()),
+		EndTime:   xrayTime(span.EndTime().UnixNano()),
+	}
+	parent := span.Parent()
+	if parent.IsValid() {
+		seg.ParentID = parent.SpanID().String()
+	}
+	if span.SpanKind() == trace.SpanKindServer || !parent.IsValid() || parent.IsRemote() {
+		if res := span.Resource(); res != nil {
+			if name, ok := res.Set().Value(semconv.ServiceNameKey); ok && name.AsString() != "" {
+				seg.Name = name.AsString()
+			}
+		}
+	} else {
+		seg.Type = "subsegment"
+		if span.SpanKind() == trace.SpanKindClient {
+			seg.Namespace = "remote"
+		}
+	}
+	seg.Name = xraySegmentName(seg.Name)
+
+	var (
+		status int64
+		req    xrayHTTPRequest
+	)
+	for _, kv := range span.Attributes() {
+		switch kv.Key {
+		case semconv.HTTPRequestMethodKey, "http.method":
+			req.Method = kv.Value.Emit()
+			continue
+		case semconv.URLFullKey, "http.url":
+			req.URL = kv.Value.Emit()
+			continue
+		case semconv.HTTPResponseStatusCodeKey, "http.status_code":
+			status = kv.Value.AsInt64()
+			continue
+		}
+		seg.addAttribute(kv)
+	}
+	if req.Method != "" || req.URL != "" || status != 0 {
+		seg.HTTP = &xrayHTTP{}
+		if req.Method != "" || req.URL != "" {
+			seg.HTTP.Request = &req
+		}
+		if status != 0 {
+			seg.HTTP.Response = &xrayHTTPResponse{Status: status}
+		}
+	}
+
+	switch {
+	case status >= 500:
+		seg.Fault = true
+	case status >= 400:
+		seg.Error = true
+	case span.Status().Code == codes.Error:
+		seg.Fault = true
+	}
+	return seg
+}
+
+// addAttribute records scalar attributes as indexed annotations while the X-Ray limit allows and
+// everything else as metadata.
+func (seg *xraySegment) addAttribute(kv attribute.KeyValue) {
+	key := xrayAnnotationKey(string(kv.Key))
+	var value any
+	switch kv.Value.Type() {
+	case attribute.STRING:
+		value = kv.Value.AsString()
+	case attribute.BOOL:
+		value = kv.Value.AsBool()
+	case attribute.INT64:
+		value = kv.Value.AsInt64()
+	case attribute.FLOAT64:
+		value = kv.Value.AsFloat64()
+	}
+	if value != nil && len(seg.Annotations) < xrayMaxAnnotations {
+		if seg.Annotations == nil {
+			seg.Annotations = map[string]any{}
+		}
+		seg.Annotations[key] = value
+		return
+	}
+	if seg.Metadata == nil {
+		seg.Metadata = map[string]map[string]any{"default": {}}
+	}
+	seg.Metadata["default"][string(kv.Key)] = kv.Value.AsInterface()
+}
+
+// xrayTraceID renders a trace id as 1-<8 hex epoch>-<24 hex>. X-Ray only accepts ids whose first
+// four bytes are a recent Unix time, which the X-Ray id generator installed by Setup guarantees.
+func xrayTraceID(id trace.TraceID) string {
+	s := id.String()
+	return "1-" + s[:8] + "-" + s[8:]
+}
+
+func xrayTime(unixNano int64) float64 {
+	return float64(unixNano) / 1e9
+}
+
+// xrayAnnotationKey keeps the characters X-Ray allows in annotation keys.
+func xrayAnnotationKey(key string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+			return r
+		}
+		return '_'
+	}, key)
+}
+
+// xraySegmentName drops characters X-Ray rejects in segment names and applies its length limit.
+func xraySegmentName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`<>"'\`, r) {
+			return -1
+		}
+		return r
+	}, name)
+	if len(name) > 200 {
+		name = strings.ToValidUTF8(name[:200], "")
+	}
+	if name == "" {
+		return "unknown"
+	}
+	return name
+}
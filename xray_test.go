@@ -0,0 +1,96 @@
+package otelx
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/xray"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type fakeXRay struct {
+	docs []string
+}
+
+func (f *fakeXRay) PutTraceSegments(_ context.Context, in *xray.PutTraceSegmentsInput, _ ...func(*xray.Options)) (*xray.PutTraceSegmentsOutput, error) {
+	f.docs = append(f.docs, in.TraceSegmentDocuments...)
+	return &xray.PutTraceSegmentsOutput{}, nil
+}
+
+func TestXRayExporterConvertsSpans(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	tracer := tp.Tracer("test")
+	ctx, server := tracer.Start(context.Background(), "GET /items", trace.WithSpanKind(trace.SpanKindServer))
+	server.SetAttributes(
+		attribute.String("http.request.method", "GET"),
+		attribute.Int("http.response.status_code", 503),
+		attribute.String("user.id", "u-1"),
+		attribute.StringSlice("tags", []string{"a", "b"}),
+	)
+	_, client := tracer.Start(ctx, "db.query", trace.WithSpanKind(trace.SpanKindClient))
+	client.End()
+	server.End()
+
+	fake := &fakeXRay{}
+	if err := newXRayExporter(fake).ExportSpans(context.Background(), recorder.Ended()); err != nil {
+		t.Fatalf("export failed: %v", err)
+	}
+	if len(fake.docs) != 2 {
+		t.Fatalf("expected two segment documents, got %d", len(fake.docs))
+	}
+
+	var sub, seg xraySegment
+	_ = json.Unmarshal([]byte(fake.docs[0]), &sub)
+	_ = json.Unmarshal([]byte(fake.docs[1]), &seg)
+
+	tid := server.SpanContext().TraceID().String()
+	if seg.TraceID != "1-"+tid[:8]+"-"+tid[8:] {
+		t.Fatalf("unexpected xray trace id %q", seg.TraceID)
+	}
+	if seg.Type != "" || seg.HTTP == nil || seg.HTTP.Response.Status != 503 || !seg.Fault {
+		t.Fatalf("expected a faulted http segment, got %+v", seg)
+	}
+	if seg.Annotations["user_id"] != "u-1" {
+		t.Fatalf("expected sanitized annotation, got %v", seg.Annotations)
+	}
+	if _, ok := seg.Metadata["default"]["tags"]; !ok {
+		t.Fatalf("expected slice attribute in metadata, got %v", seg.Metadata)
+	}
+	if sub.Type != "subsegment" || sub.Namespace != "remote" || sub.ParentID != seg.ID || sub.Name != "db.query" {
+		t.Fatalf("expected remote subsegment of the server span, got %+v", sub)
+	}
+}
+
+func TestSetupXRayUsesCompatibleIDsAndPropagator(t *testing.T) {
+	t.Setenv("AWS_EC2_METADATA_DISABLED", "true")
+	cfg := Config{ServiceName: "svc", Exporter: ExporterXRay, AWSRegion: "us-east-1", SamplingRatio: Float64(1)}
+	prov, err := Setup(context.Background(), cfg, noopLogger{})
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	defer prov.TP.Shutdown(context.Background())
+
+	ctx, span := prov.TP.Tracer("test").Start(context.Background(), "op")
+	defer span.End()
+	tid := span.SpanContext().TraceID()
+	if epoch := int64(binary.BigEndian.Uint32(tid[:4])); time.Now().Unix()-epoch > 60 {
+		t.Fatalf("expected trace id to start with the current epoch, got %s", tid)
+	}
+	carrier := propagation.MapCarrier{}
+	prov.Propagator.Inject(ctx, carrier)
+	if carrier.Get("X-Amzn-Trace-Id") == "" {
+		t.Fatalf("expected X-Amzn-Trace-Id to be propagated, got %v", carrier)
+	}
+
+	if _, err := Setup(context.Background(), Config{ServiceName: "svc", Exporter: ExporterOTLP, AWSRegion: "us-east-1"}, nil); err == nil {
+		t.Fatalf("expected awsRegion without exporter=xray to be rejected")
+	}
+}